@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/config"
+	"github.com/hha-nguyen/canopy-cli/internal/discover"
+	"github.com/hha-nguyen/canopy-cli/internal/fingerprint"
+	"github.com/hha-nguyen/canopy-cli/internal/output"
+)
+
+type appScan struct {
+	app     discover.App
+	result  *api.ScanResult
+	input   *scanInput
+	skipped bool
+	err     error
+}
+
+// resolveApps returns the apps listed in the project config, or discovers
+// them under root. only restricts the set to the given names or paths.
+// Configured apps without a platform use --platform when it is given and
+// otherwise the platform detected from the app's directory.
+func resolveApps(root string, cfg *config.ProjectConfig, only []string, platformExplicit bool) ([]discover.App, error) {
+	var apps []discover.App
+
+	if cfg != nil && len(cfg.Apps) > 0 {
+		for _, app := range cfg.Apps {
+			rel := path.Clean(filepath.ToSlash(app.Path))
+			dir := filepath.Join(root, filepath.FromSlash(rel))
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				return nil, fmt.Errorf("app %s: %s is not a directory", app.DisplayName(), rel)
+			}
+
+			platform := app.Platform
+			if platform == "" {
+				platform = scanPlatform
+				if detected, ok := discover.DetectApp(dir); ok && !platformExplicit {
+					platform = detected.Platform
+				}
+			}
+			apps = append(apps, discover.App{Name: app.DisplayName(), Path: rel, Platform: platform})
+		}
+	} else {
+		discovered, err := discover.Discover(root)
+		if err != nil {
+			return nil, fmt.Errorf("discover apps: %w", err)
+		}
+		if len(discovered) == 0 {
+			return nil, fmt.Errorf("no apps found under %s", root)
+		}
+		apps = discovered
+	}
+
+	if len(only) == 0 {
+		return apps, nil
+	}
+
+	var selected []discover.App
+	for _, want := range only {
+		found := false
+		for _, app := range apps {
+			if app.Name == want || app.Path == path.Clean(filepath.ToSlash(want)) {
+				selected = append(selected, app)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown app %q", want)
+		}
+	}
+
+	return selected, nil
+}
+
+// runAppScans scans every app concurrently and reports the merged result
+// with a single gate evaluation and exit code.
+func runAppScans(ctx context.Context, client *api.Client, root string, apps []discover.App, inputOpts inputOptions, gitMeta *api.GitMetadata, projectCfg *config.ProjectConfig, reports []output.Report, formatOpts output.Options, gatePolicy config.GateConfig) error {
+	if !IsQuiet() {
+		fmt.Printf("Scanning %d apps...\n", len(apps))
+		for _, app := range apps {
+			if app.Name != app.Path {
+				fmt.Printf("  %s: %s (%s)\n", app.Name, app.Path, app.Platform)
+			} else {
+				fmt.Printf("  %s (%s)\n", app.Path, app.Platform)
+			}
+		}
+	}
+
+	concurrency := scanConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	scans := make([]appScan, len(apps))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, app := range apps {
+		wg.Add(1)
+		go func(i int, app discover.App) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			scans[i] = scanApp(ctx, client, root, app, inputOpts, gitMeta)
+		}(i, app)
+	}
+	wg.Wait()
+
+	result := mergeAppResults(scans, gitMeta)
+
+	applyPolicy(result, projectCfg, scanOnlyRules, scanSkipRules)
+	if err := applyBaseline(result, scanBaseline); err != nil {
+		return err
+	}
+
+	summarizeApps(result)
+
+	failed := 0
+	for _, app := range result.Apps {
+		if app.Status == "FAILED" {
+			failed++
+		}
+	}
+
+	if err := outputResults(result, reports, formatOpts, gatePolicy); err != nil {
+		return err
+	}
+	if failed > 0 && scanFailOnErr {
+		return fmt.Errorf("%d of %d app scans failed", failed, len(apps))
+	}
+	return nil
+}
+
+func scanApp(ctx context.Context, client *api.Client, root string, app discover.App, inputOpts inputOptions, gitMeta *api.GitMetadata) appScan {
+	scan := appScan{app: app}
+
+	logf := func(format string, args ...interface{}) {
+		if !IsQuiet() {
+			fmt.Printf("[%s] "+format, append([]interface{}{app.Name}, args...)...)
+		}
+	}
+
+	dir := filepath.Join(root, filepath.FromSlash(app.Path))
+	info, err := os.Stat(dir)
+	if err != nil {
+		scan.err = fmt.Errorf("access app path: %w", err)
+	} else if input, err := prepareScanInput(dir, info, inputOpts, logf); err != nil {
+		scan.err = err
+	} else if input == nil {
+		scan.skipped = true
+	} else {
+		defer input.cleanup()
+		scan.input = input
+		scan.result, scan.err = runRemoteScan(ctx, client, input.archivePath, parsePlatform(app.Platform), false, logf,
+			api.WithProjectID(scanProjectID),
+			api.WithArtifactType(input.artifactType()),
+			api.WithGitMetadata(gitMeta),
+			api.WithSBOM(input.sbom),
+			api.WithFramework(input.framework(), input.pluginData),
+		)
+	}
+
+	switch {
+	case scan.err != nil:
+		fmt.Fprintln(os.Stderr, color.RedString("✗ [%s] %v", app.Name, scan.err))
+	case scan.result != nil && scan.result.Status == "FAILED":
+		fmt.Fprintln(os.Stderr, color.RedString("✗ [%s] scan failed", app.Name))
+	case scan.result != nil:
+		logf("Scan completed\n")
+	}
+
+	return scan
+}
+
+// summarizeApps recomputes per-app summaries after rule policy,
+// suppressions and the baseline have been applied to the merged findings.
+func summarizeApps(result *api.ScanResult) {
+	for i := range result.Apps {
+		app := &result.Apps[i]
+		if app.Summary == nil {
+			continue
+		}
+
+		var findings []api.Finding
+		for _, finding := range result.Findings {
+			if finding.App == app.Name {
+				findings = append(findings, finding)
+			}
+		}
+		app.Summary = api.Summarize(findings, app.Summary.Passed)
+	}
+}
+
+// mergeAppResults combines per-app results into one. File paths are made
+// relative to the repository root and findings are tagged with their app.
+func mergeAppResults(scans []appScan, gitMeta *api.GitMetadata) *api.ScanResult {
+	merged := &api.ScanResult{
+		Status:   "FAILED",
+		Findings: []api.Finding{},
+		Git:      gitMeta,
+	}
+
+	platforms := make(map[api.Platform]bool)
+	var ids []string
+	passed := 0
+
+	for _, scan := range scans {
+		platform := parsePlatform(scan.app.Platform)
+		platforms[platform] = true
+
+		app := api.AppResult{
+			Name:     scan.app.Name,
+			Path:     scan.app.Path,
+			Platform: string(platform),
+		}
+
+		if scan.err != nil {
+			app.Status = "FAILED"
+			app.Error = scan.err.Error()
+			merged.Errors = append(merged.Errors, fmt.Sprintf("%s: %v", app.Name, scan.err))
+			merged.Apps = append(merged.Apps, app)
+			continue
+		}
+		if scan.skipped {
+			app.Status = "SKIPPED"
+			merged.Status = "COMPLETED"
+			merged.Apps = append(merged.Apps, app)
+			continue
+		}
+
+		res := scan.result
+		app.ScanID = res.ID
+		if res.ID != "" {
+			ids = append(ids, res.ID)
+		}
+		app.Status = res.Status
+		for _, e := range res.Errors {
+			merged.Errors = append(merged.Errors, fmt.Sprintf("%s: %s", app.Name, e))
+		}
+		if res.Status == "FAILED" {
+			if len(res.Errors) == 0 {
+				merged.Errors = append(merged.Errors, fmt.Sprintf("%s: scan failed", app.Name))
+			}
+			merged.Apps = append(merged.Apps, app)
+			continue
+		}
+
+		scan.input.apply(res)
+		app.Summary = res.Summary
+		merged.Apps = append(merged.Apps, app)
+		merged.Status = "COMPLETED"
+
+		if merged.PolicyVersion == "" {
+			merged.PolicyVersion = res.PolicyVersion
+		}
+		if res.DurationMs > merged.DurationMs {
+			merged.DurationMs = res.DurationMs
+		}
+		if res.RiskAssessment != nil && (merged.RiskAssessment == nil || res.RiskAssessment.Score > merged.RiskAssessment.Score) {
+			merged.RiskAssessment = res.RiskAssessment
+		}
+		if merged.CreatedAt.IsZero() || (!res.CreatedAt.IsZero() && res.CreatedAt.Before(merged.CreatedAt)) {
+			merged.CreatedAt = res.CreatedAt
+		}
+		if res.CompletedAt != nil && (merged.CompletedAt == nil || res.CompletedAt.After(*merged.CompletedAt)) {
+			merged.CompletedAt = res.CompletedAt
+		}
+		if res.Summary != nil {
+			passed += res.Summary.Passed
+		}
+
+		for _, finding := range res.Findings {
+			if finding.FilePath != "" && app.Path != "." {
+				finding.FilePath = path.Join(app.Path, fingerprint.NormalizePath(finding.FilePath))
+			}
+			if finding.Platform == "" && platform != api.PlatformBoth {
+				finding.Platform = string(platform)
+			}
+			finding.App = app.Name
+			merged.Findings = append(merged.Findings, finding)
+		}
+		for _, te := range res.Entitlements {
+			if te.File != "" && app.Path != "." {
+				te.File = path.Join(app.Path, te.File)
+			}
+			te.App = app.Name
+			merged.Entitlements = append(merged.Entitlements, te)
+		}
+	}
+
+	merged.Platform = string(api.PlatformBoth)
+	if len(platforms) == 1 {
+		for p := range platforms {
+			merged.Platform = string(p)
+		}
+	}
+
+	// Each app has its own scan; the merged result is identified by all of
+	// them so reports (e.g. SARIF automationDetails) name the run.
+	merged.ID = strings.Join(ids, "+")
+	merged.Summary = api.Summarize(merged.Findings, passed)
+	return merged
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/baseline"
+	"github.com/spf13/cobra"
+)
+
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Manage accepted findings",
+	Long: `Manage a baseline of accepted findings.
+
+A baseline lets existing findings pass while new findings still fail the
+scan. Commit the baseline file to your repository and pass it to
+canopy scan --baseline.
+
+Findings fixed since the baseline are listed in every output format except
+sonarqube, whose import format only holds open issues.`,
+}
+
+var baselineCreateCmd = &cobra.Command{
+	Use:   "create [path]",
+	Short: "Create a baseline from scan results",
+	Long: `Create a baseline file containing every finding of a scan.
+
+Use --from to read a result saved with --format json, or --scan to fetch
+a completed scan by ID. A fetched scan only holds the server findings, so
+the local preflight checks and SDK and plugin attribution of canopy scan are
+run on path (default: current directory) before the baseline is written.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBaselineCreate,
+}
+
+var (
+	baselineFrom   string
+	baselineScanID string
+	baselineOutput string
+)
+
+func init() {
+	rootCmd.AddCommand(baselineCmd)
+	baselineCmd.AddCommand(baselineCreateCmd)
+
+	baselineCreateCmd.Flags().StringVar(&baselineFrom, "from", "", "Saved JSON scan result")
+	baselineCreateCmd.Flags().StringVar(&baselineScanID, "scan", "", "Scan ID to fetch")
+	baselineCreateCmd.Flags().StringVarP(&baselineOutput, "output", "o", baseline.DefaultPath, "Baseline file to write")
+}
+
+func runBaselineCreate(cmd *cobra.Command, args []string) error {
+	if (baselineFrom == "") == (baselineScanID == "") {
+		return fmt.Errorf("exactly one of --from or --scan is required")
+	}
+
+	if baselineFrom != "" && len(args) > 0 {
+		return fmt.Errorf("a path can only be given with --scan; results saved by canopy scan already include local findings")
+	}
+
+	var result *api.ScanResult
+	var err error
+	if baselineFrom != "" {
+		result, err = loadScanResult(baselineFrom)
+	} else {
+		result, err = fetchScanResult(baselineScanID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if baselineScanID != "" {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", dir)
+		}
+		collectLocal(dir, inputOptions{}, func(string, ...interface{}) {}).apply(result)
+	}
+
+	file := baseline.Create(result)
+	if err := baseline.Save(file, baselineOutput); err != nil {
+		return err
+	}
+
+	color.Green("✓ Baseline with %d findings written to %s", len(result.Findings), baselineOutput)
+	return nil
+}
+
+func applyBaseline(result *api.ScanResult, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	file, err := baseline.Load(path)
+	if err != nil {
+		return err
+	}
+
+	baseline.Apply(result, file, path)
+	return nil
+}
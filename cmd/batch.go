@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/config"
+	"github.com/hha-nguyen/canopy-cli/internal/exit"
+	"github.com/hha-nguyen/canopy-cli/internal/gate"
+	"github.com/hha-nguyen/canopy-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var scanBatchCmd = &cobra.Command{
+	Use:   "scan-batch <manifest.yaml>",
+	Short: "Scan many projects listed in a manifest",
+	Long: `Scan every directory or archive listed in a manifest with a bounded number
+of concurrent scans and a request rate shared by all of them.
+
+A failing entry does not stop the batch. Per-entry reports and summary.json are
+written to the output directory. Exits with code 1 when any entry fails its
+gate, and with an error when any scan fails.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScanBatch,
+}
+
+var (
+	batchConcurrency int
+	batchRateLimit   float64
+	batchOutputDir   string
+	batchFormats     []string
+	batchTimeout     time.Duration
+)
+
+const (
+	defaultBatchConcurrency = 4
+	defaultBatchRateLimit   = 2
+	defaultBatchOutputDir   = "canopy-reports"
+)
+
+func init() {
+	rootCmd.AddCommand(scanBatchCmd)
+
+	scanBatchCmd.Flags().IntVar(&batchConcurrency, "concurrency", defaultBatchConcurrency, "Number of scans run in parallel")
+	scanBatchCmd.Flags().Float64Var(&batchRateLimit, "rate-limit", defaultBatchRateLimit, "Maximum API requests per second across all scans (0 for unlimited)")
+	scanBatchCmd.Flags().StringVar(&batchOutputDir, "output-dir", defaultBatchOutputDir, "Directory for per-entry reports and summary.json")
+	scanBatchCmd.Flags().StringSliceVar(&batchFormats, "formats", []string{"json"}, "Report formats written for each entry")
+	scanBatchCmd.Flags().DurationVar(&batchTimeout, "timeout", 10*time.Minute, "Timeout for each entry")
+}
+
+type batchEntryResult struct {
+	Name     string           `json:"name"`
+	Path     string           `json:"path"`
+	Platform string           `json:"platform"`
+	ScanID   string           `json:"scan_id,omitempty"`
+	Status   string           `json:"status"`
+	Error    string           `json:"error,omitempty"`
+	Summary  *api.ScanSummary `json:"summary,omitempty"`
+	Gate     *api.GateResult  `json:"gate,omitempty"`
+	Reports  []string         `json:"reports,omitempty"`
+}
+
+type batchSummary struct {
+	Manifest    string             `json:"manifest"`
+	StartedAt   time.Time          `json:"started_at"`
+	CompletedAt time.Time          `json:"completed_at"`
+	Total       int                `json:"total"`
+	Passed      int                `json:"passed"`
+	GateFailed  int                `json:"gate_failed"`
+	Failed      int                `json:"failed"`
+	Entries     []batchEntryResult `json:"entries"`
+}
+
+func runScanBatch(cmd *cobra.Command, args []string) error {
+	manifest, err := config.LoadBatchManifest(args[0])
+	if err != nil {
+		return err
+	}
+
+	concurrency := batchConcurrency
+	if !cmd.Flags().Changed("concurrency") && manifest.Concurrency > 0 {
+		concurrency = manifest.Concurrency
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	rateLimit := batchRateLimit
+	if !cmd.Flags().Changed("rate-limit") && manifest.RateLimit > 0 {
+		rateLimit = manifest.RateLimit
+	}
+
+	outputDir := batchOutputDir
+	if !cmd.Flags().Changed("output-dir") && manifest.OutputDir != "" {
+		outputDir = manifest.ResolvePath(manifest.OutputDir)
+	}
+
+	formats := batchFormats
+	if !cmd.Flags().Changed("formats") && len(manifest.Formats) > 0 {
+		formats = manifest.Formats
+	}
+	for _, format := range formats {
+		if !output.IsSupportedFormat(format) {
+			return fmt.Errorf("unsupported format %q (supported: %s)", format, strings.Join(output.SupportedFormats, ", "))
+		}
+	}
+
+	apiKey, err := requireAPIKey()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+
+	client := api.NewClient(GetAPIURL(), apiKey, api.WithRateLimiter(api.NewRateLimiter(rateLimit)))
+
+	if !IsQuiet() {
+		fmt.Printf("Scanning %d entries (%d at a time)...\n", len(manifest.Entries), concurrency)
+	}
+
+	summary := batchSummary{
+		Manifest:  manifest.Path(),
+		StartedAt: time.Now().UTC(),
+		Total:     len(manifest.Entries),
+		Entries:   make([]batchEntryResult, len(manifest.Entries)),
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				summary.Entries[i] = runBatchEntry(client, manifest, manifest.Entries[i], formats, outputDir)
+			}
+		}()
+	}
+	for i := range manifest.Entries {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	summary.CompletedAt = time.Now().UTC()
+	for _, entry := range summary.Entries {
+		switch {
+		case entry.Status != "COMPLETED":
+			summary.Failed++
+		case entry.Gate != nil && !entry.Gate.Passed:
+			summary.GateFailed++
+		default:
+			summary.Passed++
+		}
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal summary: %w", err)
+	}
+	summaryPath := filepath.Join(outputDir, "summary.json")
+	if err := os.WriteFile(summaryPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("write summary: %w", err)
+	}
+
+	printBatchSummary(summary)
+	if !IsQuiet() {
+		color.Green("✓ Summary written to %s", summaryPath)
+	}
+
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d of %d scans failed", summary.Failed, summary.Total)
+	}
+	if summary.GateFailed > 0 {
+		os.Exit(exit.IssuesFound)
+	}
+	return nil
+}
+
+func runBatchEntry(client *api.Client, manifest *config.BatchManifest, entry config.BatchEntry, formats []string, outputDir string) batchEntryResult {
+	res := batchEntryResult{
+		Name:     entry.DisplayName(),
+		Path:     entry.Path,
+		Platform: string(parsePlatform(entry.Platform)),
+	}
+
+	logf := func(format string, args ...interface{}) {
+		if !IsQuiet() {
+			fmt.Printf("[%s] "+format, append([]interface{}{res.Name}, args...)...)
+		}
+	}
+	fail := func(err error) batchEntryResult {
+		res.Status = "FAILED"
+		res.Error = err.Error()
+		fmt.Fprintln(os.Stderr, color.RedString("✗ [%s] %v", res.Name, err))
+		return res
+	}
+
+	absPath, err := filepath.Abs(manifest.ResolvePath(entry.Path))
+	if err != nil {
+		return fail(fmt.Errorf("resolve path: %w", err))
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return fail(fmt.Errorf("access path: %w", err))
+	}
+
+	vcsDir := absPath
+	if !info.IsDir() {
+		vcsDir = filepath.Dir(absPath)
+	}
+
+	projectCfg, err := loadProjectConfig("", vcsDir)
+	if err != nil {
+		return fail(err)
+	}
+	gitMeta, err := collectGitMetadata(vcsDir, gitOptions{})
+	if err != nil {
+		return fail(err)
+	}
+
+	input, err := prepareScanInput(absPath, info, inputOptions{}, logf)
+	if err != nil {
+		return fail(err)
+	}
+	defer input.cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), batchTimeout)
+	defer cancel()
+
+	platform, err := resolvePlatform(entry.Platform, entry.Platform != "", input.archiveType)
+	if err != nil {
+		return fail(err)
+	}
+	if input.plugins != nil && entry.Platform == "" {
+		platform = parsePlatform(input.plugins.Platform)
+	}
+	res.Platform = string(platform)
+
+	result, err := runRemoteScan(ctx, client, input.archivePath, platform, false, logf,
+		api.WithProjectID(entry.Project),
+		api.WithArtifactType(input.artifactType()),
+		api.WithGitMetadata(gitMeta),
+		api.WithSBOM(input.sbom),
+		api.WithFramework(input.framework(), input.pluginData),
+	)
+	if err != nil {
+		return fail(err)
+	}
+
+	res.ScanID = result.ID
+	if result.Status == "FAILED" {
+		return fail(fmt.Errorf("scan %s failed", result.ID))
+	}
+	res.Status = result.Status
+
+	if result.Git == nil {
+		result.Git = gitMeta
+	}
+	input.apply(result)
+	applyPolicy(result, projectCfg, nil, nil)
+	result.Gate = gate.Evaluate(result, batchGate(manifest, entry, projectCfg))
+	res.Summary = result.Summary
+	res.Gate = result.Gate
+
+	opts := output.Options{NoColor: true, ToolVersion: version}
+	for _, format := range formats {
+		path := filepath.Join(outputDir, batchReportName(res.Name, format))
+		formatted, err := output.NewFormatter(format, opts).Format(result)
+		if err != nil {
+			return fail(fmt.Errorf("format %s output: %w", format, err))
+		}
+		if err := os.WriteFile(path, formatted, 0644); err != nil {
+			return fail(fmt.Errorf("write report: %w", err))
+		}
+		res.Reports = append(res.Reports, path)
+	}
+
+	if result.Gate.Passed {
+		logf("Completed, gate passed\n")
+	} else {
+		logf("Completed, gate failed\n")
+	}
+	return res
+}
+
+// batchGate mirrors resolveGate: a threshold set in the manifest is added
+// on top of the project gate, otherwise the project gate replaces it except
+// for the default blocker limit.
+func batchGate(manifest *config.BatchManifest, entry config.BatchEntry, projectCfg *config.ProjectConfig) config.GateConfig {
+	threshold := entry.Threshold
+	if threshold == "" {
+		threshold = manifest.Threshold
+	}
+	thresholdGate := gate.FromThreshold(exit.ParseThreshold(threshold))
+
+	if projectCfg == nil || projectCfg.Gate == nil {
+		return thresholdGate
+	}
+	if threshold != "" {
+		return gate.Merge(*projectCfg.Gate, thresholdGate)
+	}
+	return gate.WithDefault(*projectCfg.Gate)
+}
+
+func batchReportName(name, format string) string {
+	name = strings.NewReplacer("/", "-", "\\", "-", " ", "-").Replace(name)
+
+	switch format {
+	case "text":
+		return name + ".txt"
+	case "json":
+		return name + ".json"
+	case "sarif":
+		return name + ".sarif"
+	case "csv", "tsv":
+		return name + "." + format
+	case "junit", "checkstyle":
+		return name + "." + format + ".xml"
+	default:
+		return name + "." + format + ".json"
+	}
+}
+
+func printBatchSummary(summary batchSummary) {
+	fmt.Println()
+	fmt.Printf("%-30s  %-9s  %7s  %5s  %6s  %3s  %s\n", "Name", "Status", "Blocker", "High", "Medium", "Low", "Gate")
+	for _, entry := range summary.Entries {
+		if entry.Summary == nil {
+			fmt.Printf("%-30s  %-9s  %7s  %5s  %6s  %3s  %s\n", entry.Name, entry.Status, "-", "-", "-", "-", "-")
+			continue
+		}
+
+		gateStatus := color.GreenString("passed")
+		if entry.Gate != nil && !entry.Gate.Passed {
+			gateStatus = color.RedString("failed")
+		}
+		s := entry.Summary
+		fmt.Printf("%-30s  %-9s  %7d  %5d  %6d  %3d  %s\n", entry.Name, entry.Status, s.Blocker, s.High, s.Medium, s.Low, gateStatus)
+	}
+	fmt.Println()
+	fmt.Printf("%d scanned: %d passed, %d failed the gate, %d failed to scan\n",
+		summary.Total, summary.Passed, summary.GateFailed, summary.Failed)
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/diff"
+	"github.com/hha-nguyen/canopy-cli/internal/exit"
+	"github.com/hha-nguyen/canopy-cli/internal/output"
+	"github.com/hha-nguyen/canopy-cli/internal/preflight"
+	"github.com/hha-nguyen/canopy-cli/internal/suppress"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <scanA> <scanB>",
+	Short: "Compare two scans",
+	Long: `Compare two scans and report introduced, resolved and unchanged findings,
+severity changes, the risk score delta and policy version changes.
+
+Each argument is either a scan ID or a result saved with --format json.
+A scan fetched by ID only holds the server findings, so when only one side
+is fetched, local preflight findings and expired-suppression findings are
+left out of the comparison.
+Exits with code 1 when scanB introduces findings or raises the severity of
+an existing finding.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+var (
+	diffFormat        string
+	diffOutput        string
+	diffFailOnRegress bool
+)
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "text", "Output format: text, json, markdown")
+	diffCmd.Flags().StringVarP(&diffOutput, "output", "o", "", "Write output to file instead of stdout")
+	diffCmd.Flags().BoolVar(&diffFailOnRegress, "fail-on-regression", true, "Exit with code 1 when regressions are found")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	base, baseFetched, err := resolveScan(args[0])
+	if err != nil {
+		return err
+	}
+
+	head, headFetched, err := resolveScan(args[1])
+	if err != nil {
+		return err
+	}
+
+	if baseFetched != headFetched {
+		if !IsQuiet() {
+			fmt.Fprintln(os.Stderr, "Leaving local findings out: a scan fetched by ID only holds server findings")
+		}
+		base = withoutLocalFindings(base)
+		head = withoutLocalFindings(head)
+	}
+
+	result := diff.Compare(base, head)
+
+	formatted, err := diff.Format(result, strings.ToLower(diffFormat), color.NoColor || diffOutput != "")
+	if err != nil {
+		return err
+	}
+
+	if diffOutput != "" {
+		if err := os.WriteFile(diffOutput, formatted, 0644); err != nil {
+			return fmt.Errorf("write output file: %w", err)
+		}
+		if !IsQuiet() {
+			color.Green("✓ Diff written to %s", diffOutput)
+		}
+	} else {
+		fmt.Print(string(formatted))
+	}
+
+	if diffFailOnRegress && result.HasRegressions() {
+		os.Exit(exit.IssuesFound)
+	}
+
+	return nil
+}
+
+// resolveScan loads a saved result or fetches a scan by ID, and reports
+// whether it was fetched. A reference that looks like a file path is never
+// sent to the API.
+func resolveScan(ref string) (*api.ScanResult, bool, error) {
+	if ref == output.Stdout {
+		result, err := loadScanResult(ref)
+		return result, false, err
+	}
+
+	info, err := os.Stat(ref)
+	switch {
+	case err == nil && info.IsDir():
+		return nil, false, fmt.Errorf("%s is a directory, expected a scan ID or a result saved with --format json", ref)
+	case err == nil:
+		result, err := loadScanResult(ref)
+		return result, false, err
+	case looksLikePath(ref):
+		return nil, false, fmt.Errorf("open results file: %w", err)
+	}
+
+	result, err := fetchScanResult(ref)
+	return result, true, err
+}
+
+func looksLikePath(ref string) bool {
+	return strings.ContainsAny(ref, `/\`) || strings.HasSuffix(strings.ToLower(ref), ".json")
+}
+
+// withoutLocalFindings drops the findings added on the client: local
+// preflight checks and expired suppressions of the project config.
+func withoutLocalFindings(result *api.ScanResult) *api.ScanResult {
+	stripped := *result
+	stripped.Findings = make([]api.Finding, 0, len(result.Findings))
+	for _, finding := range result.Findings {
+		if finding.Source != preflight.Source && finding.RuleCode != suppress.ExpiredRuleCode {
+			stripped.Findings = append(stripped.Findings, finding)
+		}
+	}
+	return &stripped
+}
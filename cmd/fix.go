@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/exit"
+	"github.com/hha-nguyen/canopy-cli/internal/fix"
+	"github.com/hha-nguyen/canopy-cli/internal/preflight"
+	"github.com/spf13/cobra"
+)
+
+var fixCmd = &cobra.Command{
+	Use:   "fix [path]",
+	Short: "Apply remediation templates to project files",
+	Long: `Apply the remediation templates of findings to the project in place.
+
+Findings come from a saved JSON result (--from), a completed scan (--scan),
+or, with neither, from the local preflight checks. Each fix is shown as a
+diff and applied after confirmation; --yes applies all of them and --dry-run
+only shows the diffs. Templates whose purpose string is placeholder text ask
+for the text to write; with --yes or --dry-run they are left unfixed. The
+command exits with status 1 when a fix cannot be applied.
+
+Files under build outputs and vendored dependencies (build/, intermediates/,
+DerivedData/, Pods/, Carthage/, node_modules/) are never edited.
+
+Supported fixes:
+  - Add a missing Info.plist key
+  - Declare a required-reason API in PrivacyInfo.xcprivacy
+  - Add or update a <uses-permission> in AndroidManifest.xml
+  - Add an attribute such as android:exported to a manifest component
+  - Raise a literal targetSdk/minSdk in build.gradle[.kts]`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runFix,
+}
+
+var (
+	fixFrom      string
+	fixScanID    string
+	fixDryRun    bool
+	fixYes       bool
+	fixOnlyRules []string
+)
+
+func init() {
+	rootCmd.AddCommand(fixCmd)
+
+	fixCmd.Flags().StringVar(&fixFrom, "from", "", "Saved JSON scan result")
+	fixCmd.Flags().StringVar(&fixScanID, "scan", "", "Scan ID to fetch")
+	fixCmd.Flags().BoolVar(&fixDryRun, "dry-run", false, "Show the diffs without changing files")
+	fixCmd.Flags().BoolVarP(&fixYes, "yes", "y", false, "Apply every fix without asking")
+	fixCmd.Flags().StringSliceVar(&fixOnlyRules, "only-rules", nil, "Only fix rules matching these codes, globs or category:<name>")
+}
+
+type fixSummary struct {
+	Applied     map[string]int
+	Declined    int
+	Unsupported int
+	Already     int
+	Failed      int
+	// Reasons is set when a privacy manifest entry with a default reason
+	// code was applied.
+	Reasons bool
+}
+
+func runFix(cmd *cobra.Command, args []string) error {
+	if fixFrom != "" && fixScanID != "" {
+		return fmt.Errorf("--from and --scan cannot be used together")
+	}
+
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolve path: %w", err)
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a project directory", path)
+	}
+
+	var result *api.ScanResult
+	switch {
+	case fixFrom != "":
+		result, err = loadScanResult(fixFrom)
+	case fixScanID != "":
+		result, err = fetchScanResult(fixScanID)
+	default:
+		var findings []api.Finding
+		findings, err = preflight.Run(root)
+		result = &api.ScanResult{Status: "COMPLETED", Findings: findings}
+	}
+	if err != nil {
+		return err
+	}
+
+	projectCfg, err := loadProjectConfig("", root)
+	if err != nil {
+		return err
+	}
+	applyPolicy(result, projectCfg, fixOnlyRules, nil)
+
+	fixer := fix.New(root)
+	summary := fixSummary{Applied: make(map[string]int)}
+	reader := bufio.NewReader(os.Stdin)
+	applyAll := fixYes || fixDryRun
+
+	var candidates []api.Finding
+	for _, f := range result.Findings {
+		if len(f.Suppressions) > 0 {
+			continue
+		}
+		if !fix.Supported(f) {
+			summary.Unsupported++
+			continue
+		}
+		candidates = append(candidates, f)
+	}
+
+	quit := false
+	for i, f := range candidates {
+		header := fmt.Sprintf("\n[%d/%d] %s %s: %s", i+1, len(candidates), f.Severity, f.RuleCode, f.Message)
+		change, err := fixer.Plan(f)
+		if errors.Is(err, fix.ErrPlaceholder) && !applyAll && !quit {
+			// The template has no usable purpose string; ask for one.
+			fmt.Println(header)
+			header = ""
+			purpose, ok, perr := promptPurpose(reader, fix.PurposeKey(f))
+			if perr != nil {
+				quit = true
+			}
+			if !ok {
+				summary.Declined++
+				continue
+			}
+			if f, err = fix.WithPurpose(f, purpose); err == nil {
+				change, err = fixer.Plan(f)
+			}
+		}
+		switch {
+		case errors.Is(err, fix.ErrUnsupported), errors.Is(err, fix.ErrPlaceholder):
+			summary.Unsupported++
+			continue
+		case errors.Is(err, fix.ErrAlreadyApplied):
+			summary.Already++
+			continue
+		case err != nil:
+			fmt.Fprintln(os.Stderr, color.YellowString("Cannot fix %s in %s: %v", f.RuleCode, f.FilePath, err))
+			summary.Failed++
+			continue
+		}
+
+		// After quitting, the remaining findings are still planned so only
+		// those with an applicable fix count as declined.
+		if quit {
+			summary.Declined++
+			continue
+		}
+
+		if header != "" {
+			fmt.Println(header)
+		}
+		fmt.Println(change.Description)
+		printDiff(change.Diff())
+
+		if !applyAll {
+			answer, err := promptFix(reader)
+			if err != nil || answer == "q" {
+				quit = true
+				summary.Declined++
+				continue
+			}
+			if answer == "a" {
+				applyAll = true
+			} else if answer != "y" {
+				summary.Declined++
+				continue
+			}
+		}
+
+		fixer.Accept(change)
+		summary.Applied[change.File]++
+		if filepath.Ext(change.File) == ".xcprivacy" {
+			summary.Reasons = true
+		}
+	}
+
+	if !fixDryRun {
+		if err := fixer.Write(); err != nil {
+			return err
+		}
+	}
+
+	printFixSummary(summary)
+	if summary.Failed > 0 {
+		os.Exit(exit.IssuesFound)
+	}
+	return nil
+}
+
+// promptPurpose asks for the purpose string of key. An empty answer skips
+// the fix; answers that are placeholder text are asked again.
+func promptPurpose(reader *bufio.Reader, key string) (string, bool, error) {
+	for {
+		fmt.Printf("%s needs a purpose string that explains the access (empty to skip): ", key)
+		line, err := reader.ReadString('\n')
+		purpose := strings.TrimSpace(line)
+		if purpose == "" {
+			if err == io.EOF {
+				fmt.Println()
+			}
+			return "", false, err
+		}
+		if !preflight.IsPlaceholder(key, purpose) {
+			return purpose, true, nil
+		}
+		fmt.Println(color.YellowString("That looks like placeholder text."))
+		if err == io.EOF {
+			return "", false, err
+		}
+	}
+}
+
+// promptFix asks whether to apply a fix: y(es), n(o), a(ll remaining) or
+// q(uit). End of input counts as quit.
+func promptFix(reader *bufio.Reader) (string, error) {
+	for {
+		fmt.Print("Apply this fix? [y]es/[n]o/[a]ll/[q]uit: ")
+		line, err := reader.ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(line))
+		if answer == "" && err == io.EOF {
+			fmt.Println()
+			return "q", err
+		}
+		switch answer {
+		case "y", "yes":
+			return "y", nil
+		case "n", "no", "":
+			return "n", nil
+		case "a", "all":
+			return "a", nil
+		case "q", "quit":
+			return "q", nil
+		}
+	}
+}
+
+func printDiff(diff string) {
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			fmt.Println(color.New(color.Bold).Sprint(line))
+		case strings.HasPrefix(line, "@@"):
+			fmt.Println(color.CyanString(line))
+		case strings.HasPrefix(line, "+"):
+			fmt.Println(color.GreenString(line))
+		case strings.HasPrefix(line, "-"):
+			fmt.Println(color.RedString(line))
+		default:
+			fmt.Println(line)
+		}
+	}
+}
+
+func printFixSummary(s fixSummary) {
+	files := make([]string, 0, len(s.Applied))
+	total := 0
+	for file, n := range s.Applied {
+		files = append(files, file)
+		total += n
+	}
+	sort.Strings(files)
+
+	verb := "Applied"
+	if fixDryRun {
+		verb = "Would apply"
+	}
+
+	fmt.Println()
+	fmt.Println("Summary")
+	fmt.Println("-------")
+	fmt.Printf("%s %d fixes to %d files\n", verb, total, len(files))
+	for _, file := range files {
+		fmt.Printf("  %s (%d)\n", file, s.Applied[file])
+	}
+	if s.Declined > 0 {
+		fmt.Printf("Declined: %d\n", s.Declined)
+	}
+	if s.Already > 0 {
+		fmt.Printf("Already fixed: %d\n", s.Already)
+	}
+	if s.Unsupported > 0 {
+		fmt.Printf("No automatic fix: %d\n", s.Unsupported)
+	}
+	if s.Failed > 0 {
+		fmt.Printf("Failed: %d\n", s.Failed)
+	}
+	if s.Reasons && !fixDryRun {
+		fmt.Println(color.YellowString("Review the changes; generated reason codes are defaults that may not match your use."))
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hha-nguyen/canopy-cli/internal/preflight"
+	"github.com/spf13/cobra"
+)
+
+var metadataCmd = &cobra.Command{
+	Use:   "metadata",
+	Short: "Work with App Store and Google Play listing metadata",
+	Long:  `Validate the store listings and screenshots kept in fastlane's metadata/ and screenshots/ directories.`,
+}
+
+var metadataCheckCmd = &cobra.Command{
+	Use:   "check [dir]",
+	Short: "Check store listing text and screenshots",
+	Long: `Check the App Store (fastlane deliver) and Google Play (fastlane supply)
+listings under dir, which can be the project, its fastlane directory or the
+metadata directory itself. Runs offline; findings use the normal output formats.
+
+Checks:
+  - Field lengths (name, subtitle, keywords, descriptions, release notes)
+    and empty required fields
+  - Terms the stores reject: other platforms, placeholders, prices and
+    rankings in names, pre-release wording
+  - A privacy policy URL in at least one App Store locale, and valid URLs
+  - app_rating_config.json against content the listing describes
+  - Screenshot sizes per device class, required device classes and counts,
+    and the Play feature graphic
+
+canopy scan --metadata runs the same checks before upload.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMetadataCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(metadataCmd)
+	metadataCmd.AddCommand(metadataCheckCmd)
+	addLocalCheckFlags(metadataCheckCmd)
+}
+
+func runMetadataCheck(cmd *cobra.Command, args []string) error {
+	project, err := loadLocalProject(args)
+	if err != nil {
+		return err
+	}
+	if !project.HasStoreMetadata() {
+		return fmt.Errorf("no fastlane store metadata or screenshots found in %s", project.Root)
+	}
+	return runLocalChecks(cmd, project, preflight.CheckStoreMetadata)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/plugins"
+)
+
+var frameworkNames = map[string]string{
+	plugins.Flutter:     "Flutter",
+	plugins.ReactNative: "React Native",
+}
+
+// localPlugins describes the native plugins of a Flutter or React Native
+// directory before upload. Other projects return nil.
+func localPlugins(dir string, logf func(string, ...interface{})) (*plugins.Project, []byte) {
+	project, err := plugins.Detect(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, color.YellowString("Warning: plugin detection failed: %v", err))
+		return nil, nil
+	}
+	if project == nil {
+		return nil, nil
+	}
+
+	data, err := project.JSON()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, color.YellowString("Warning: encode plugins: %v", err))
+		return project, nil
+	}
+	logf("Detected %s project with %d native plugins (platform: %s)\n", frameworkNames[project.Framework], len(project.Plugins), project.Platform)
+	return project, data
+}
+
+// attributePlugins records the plugin that introduced findings in native
+// code of a cross-platform project, unless the server already did.
+func attributePlugins(result *api.ScanResult, project *plugins.Project) {
+	if project == nil {
+		return
+	}
+	for i := range result.Findings {
+		f := &result.Findings[i]
+		if _, ok := f.Evidence["plugin"]; ok {
+			continue
+		}
+		plugin, ok := project.Attribute(*f)
+		if !ok {
+			continue
+		}
+		if f.Evidence == nil {
+			f.Evidence = make(map[string]interface{})
+		}
+		f.Evidence["plugin"] = plugin.Package
+		if plugin.Version != "" {
+			f.Evidence["plugin_version"] = plugin.Version
+		}
+		f.Evidence["framework"] = project.Framework
+	}
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/discover"
+	"github.com/hha-nguyen/canopy-cli/internal/fingerprint"
+	"github.com/hha-nguyen/canopy-cli/internal/output"
+	"github.com/hha-nguyen/canopy-cli/internal/plugins"
+	"github.com/hha-nguyen/canopy-cli/internal/preflight"
+	"github.com/spf13/cobra"
+)
+
+var preflightCmd = &cobra.Command{
+	Use:   "preflight [path]",
+	Short: "Run local checks without uploading",
+	Long: `Run the local preflight checks on a project directory without contacting
+the Canopy API. The same checks run automatically before every scan upload.
+
+Checks:
+  - Info.plist purpose strings (NS*UsageDescription) for the frameworks and
+    APIs the sources use, and empty, placeholder or vague purpose strings
+  - Privacy manifests (PrivacyInfo.xcprivacy) of the app and bundled SDKs,
+    and required-reason APIs used without a declaration
+  - Android targetSdk/minSdk, restricted and undeclared permissions, and
+    exported components (AndroidManifest.xml and build.gradle[.kts])
+  - iOS entitlements and background modes of each Xcode target, checked
+    against the code and dependencies that use them
+
+The capabilities each target declares are listed in the text and JSON output.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPreflight,
+}
+
+func init() {
+	rootCmd.AddCommand(preflightCmd)
+	addLocalCheckFlags(preflightCmd)
+}
+
+// addLocalCheckFlags adds the output, gate and rule flags of an offline
+// command (preflight, privacy-manifest, metadata check). runLocalChecks
+// reads them back from the command, so commands never share flag values.
+func addLocalCheckFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("format", "f", "text", "Output format: text, json, sarif, junit, csv, tsv, checkstyle, sonarqube")
+	cmd.Flags().StringP("output", "o", "", "Write output to file instead of stdout")
+	cmd.Flags().StringP("threshold", "t", "blocker", "Minimum severity to fail: blocker, high, medium, low")
+	cmd.Flags().StringArray("report", nil, "Write a report as format=path (repeatable, path - for stdout)")
+	cmd.Flags().StringSlice("columns", nil, "Columns for csv/tsv output (comma-separated)")
+	cmd.Flags().String("project-config", "", "Project config file (default .canopy.yml in the project)")
+	cmd.Flags().StringSlice("only-rules", nil, "Only report rules matching these codes, globs or category:<name>")
+	cmd.Flags().StringSlice("skip-rules", nil, "Ignore rules matching these codes, globs or category:<name>")
+}
+
+func runPreflight(cmd *cobra.Command, args []string) error {
+	project, err := loadLocalProject(args)
+	if err != nil {
+		return err
+	}
+	return runLocalChecks(cmd, project)
+}
+
+// loadLocalProject resolves the optional path argument of an offline
+// command to a project directory.
+func loadLocalProject(args []string) (*preflight.Project, error) {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolve path: %w", err)
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("access path: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s requires a project directory", absPath)
+	}
+
+	project, err := preflight.LoadProject(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("list project files: %w", err)
+	}
+	return project, nil
+}
+
+// runLocalChecks runs checks (all preflight checks when none are given)
+// and writes the findings like a completed scan.
+func runLocalChecks(cmd *cobra.Command, project *preflight.Project, checks ...preflight.Check) error {
+	flags := cmd.Flags()
+	format, _ := flags.GetString("format")
+	outputPath, _ := flags.GetString("output")
+	threshold, _ := flags.GetString("threshold")
+	reportSpecs, _ := flags.GetStringArray("report")
+	columns, _ := flags.GetStringSlice("columns")
+	projectCfgPath, _ := flags.GetString("project-config")
+	onlyRules, _ := flags.GetStringSlice("only-rules")
+	skipRules, _ := flags.GetStringSlice("skip-rules")
+
+	reports, err := resolveReports(cmd, format, outputPath, reportSpecs)
+	if err != nil {
+		return err
+	}
+	if err := output.ValidateCSVColumns(columns); err != nil {
+		return err
+	}
+
+	projectCfg, err := loadProjectConfig(projectCfgPath, project.Root)
+	if err != nil {
+		return err
+	}
+
+	started := time.Now()
+	var findings []api.Finding
+	if len(checks) == 0 {
+		findings = project.Run()
+	} else {
+		findings = project.RunChecks(checks...)
+	}
+	completed := time.Now()
+
+	result := &api.ScanResult{
+		Status:      "COMPLETED",
+		Platform:    string(localPlatform(project)),
+		DurationMs:  completed.Sub(started).Milliseconds(),
+		Findings:    findings,
+		CreatedAt:   started.UTC(),
+		CompletedAt: &completed,
+	}
+	if len(checks) == 0 {
+		result.Entitlements = project.Entitlements()
+	}
+	if crossPlatform, err := plugins.Detect(project.Root); err == nil {
+		attributePlugins(result, crossPlatform)
+	}
+	result.RecomputeSummary()
+
+	applyPolicy(result, projectCfg, onlyRules, skipRules)
+
+	opts := output.Options{ToolVersion: version, Columns: columns}
+	return outputResults(result, reports, opts, resolveGate(cmd, threshold, projectCfg))
+}
+
+// storeMetadataDirs hold fastlane store listings, which are only uploaded
+// with --metadata.
+var storeMetadataDirs = []string{"fastlane/metadata/", "fastlane/screenshots/"}
+
+// localFindings runs the preflight checks on a directory before upload and
+// collects the capabilities of its iOS targets. With --metadata the store
+// listing checks run too, even with --no-preflight. Failures only produce a
+// warning since the server scan still runs.
+func localFindings(dir string, opts inputOptions, logf func(string, ...interface{})) ([]api.Finding, []api.TargetEntitlements) {
+	if opts.noPreflight && !opts.metadata {
+		return nil, nil
+	}
+
+	project, err := preflight.LoadProject(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, color.YellowString("Warning: preflight checks failed: %v", err))
+		return nil, nil
+	}
+
+	var findings []api.Finding
+	var entitlements []api.TargetEntitlements
+	switch {
+	case opts.noPreflight:
+		findings = project.RunChecks(preflight.CheckStoreMetadata)
+	case opts.metadata:
+		findings = project.Run(preflight.CheckStoreMetadata)
+		entitlements = project.Entitlements()
+	default:
+		findings = project.Run()
+		entitlements = project.Entitlements()
+	}
+	if len(findings) > 0 {
+		logf("Preflight found %d local issues\n", len(findings))
+	}
+	return findings, entitlements
+}
+
+// localPlatform returns the platform of the app rooted at the project
+// directory, or, when none is detected there, the platforms the project has
+// native files for.
+func localPlatform(project *preflight.Project) api.Platform {
+	if app, ok := discover.DetectApp(project.Root); ok {
+		return parsePlatform(app.Platform)
+	}
+
+	apple := len(project.FilesNamed("project.pbxproj", "Info.plist", "Podfile")) > 0
+	google := len(project.FilesNamed("AndroidManifest.xml", "build.gradle", "build.gradle.kts")) > 0
+	switch {
+	case apple && !google:
+		return api.PlatformApple
+	case google && !apple:
+		return api.PlatformGoogle
+	default:
+		return api.PlatformBoth
+	}
+}
+
+// mergeLocalFindings adds preflight findings the server did not already
+// report to a server result and recomputes the summary from the findings,
+// as every later step does.
+func mergeLocalFindings(result *api.ScanResult, local []api.Finding) {
+	var added []api.Finding
+	for _, finding := range local {
+		if !hasEquivalent(result.Findings, finding) {
+			added = append(added, finding)
+		}
+	}
+
+	result.Findings = append(added, result.Findings...)
+	result.RecomputeSummary()
+}
+
+// hasEquivalent reports whether findings holds one for the same rule and
+// file whose evidence agrees with local on every key both set, ignoring
+// line and column numbers.
+func hasEquivalent(findings []api.Finding, local api.Finding) bool {
+	for _, f := range findings {
+		if !strings.EqualFold(f.RuleCode, local.RuleCode) ||
+			fingerprint.NormalizePath(f.FilePath) != fingerprint.NormalizePath(local.FilePath) {
+			continue
+		}
+		equal := true
+		for k, v := range local.Evidence {
+			if other, ok := f.Evidence[k]; ok && !fingerprint.IsLocationKey(k) && !fingerprint.IsAttributionKey(k) && fmt.Sprint(other) != fmt.Sprint(v) {
+				equal = false
+				break
+			}
+		}
+		if equal {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeEntitlements reports the local entitlements unless the server
+// already did.
+func mergeEntitlements(result *api.ScanResult, entitlements []api.TargetEntitlements) {
+	if len(result.Entitlements) == 0 {
+		result.Entitlements = entitlements
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/hha-nguyen/canopy-cli/internal/fix"
+	"github.com/hha-nguyen/canopy-cli/internal/plist"
+	"github.com/hha-nguyen/canopy-cli/internal/preflight"
+	"github.com/spf13/cobra"
+)
+
+var privacyManifestCmd = &cobra.Command{
+	Use:   "privacy-manifest [path]",
+	Short: "Validate or generate the Apple privacy manifest",
+	Long: `Locate PrivacyInfo.xcprivacy files in the project and its bundled SDKs
+(CocoaPods, Carthage and Swift Package Manager checkouts), validate their
+structure and reason codes, and check that every required-reason API used in
+the sources is declared. Runs offline; findings use the normal output formats.
+
+With --generate the manifest draft is printed instead, and with --write it is
+written to the project: an existing manifest keeps its entries and gains the
+missing API categories, each with a default reason you should review.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPrivacyManifest,
+}
+
+var (
+	privacyGenerate bool
+	privacyWrite    bool
+)
+
+func init() {
+	rootCmd.AddCommand(privacyManifestCmd)
+	addLocalCheckFlags(privacyManifestCmd)
+
+	privacyManifestCmd.Flags().BoolVar(&privacyGenerate, "generate", false, "Print a manifest draft instead of validating")
+	privacyManifestCmd.Flags().BoolVar(&privacyWrite, "write", false, "Create or update the app's privacy manifest, then validate it")
+}
+
+func runPrivacyManifest(cmd *cobra.Command, args []string) error {
+	project, err := loadLocalProject(args)
+	if err != nil {
+		return err
+	}
+
+	if privacyGenerate || privacyWrite {
+		target, dict, added, err := project.ManifestDraft()
+		if err != nil {
+			return fmt.Errorf("build manifest draft: %w", err)
+		}
+
+		if !privacyWrite {
+			data, err := plist.EncodeXML(dict)
+			if err != nil {
+				return fmt.Errorf("encode manifest: %w", err)
+			}
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+
+		if err := writeManifest(project, target, dict, added); err != nil {
+			return err
+		}
+	}
+
+	return runLocalChecks(cmd, project, preflight.CheckPrivacyManifest)
+}
+
+// writeManifest creates the manifest from the draft, or adds the missing API
+// categories to an existing one in place, the way canopy fix does, so its
+// formatting, key order and comments are kept.
+func writeManifest(project *preflight.Project, target string, dict map[string]interface{}, added []string) error {
+	full := filepath.Join(project.Root, filepath.FromSlash(target))
+	_, statErr := os.Stat(full)
+	created := os.IsNotExist(statErr)
+
+	switch {
+	case created:
+		data, err := plist.EncodeXML(dict)
+		if err != nil {
+			return fmt.Errorf("encode manifest: %w", err)
+		}
+		if err := fix.WriteFile(full, data); err != nil {
+			return fmt.Errorf("write manifest: %w", err)
+		}
+	case len(added) == 0:
+		fmt.Fprintf(os.Stderr, "%s already declares every required-reason API the sources use\n", target)
+		return nil
+	default:
+		fixer := fix.New(project.Root)
+		for _, finding := range project.RunChecks(preflight.CheckPrivacyManifest) {
+			if finding.RuleCode != preflight.RuleUndeclaredReasonAPI || finding.FilePath != target {
+				continue
+			}
+			change, err := fixer.Plan(finding)
+			if errors.Is(err, fix.ErrAlreadyApplied) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("update manifest: %w", err)
+			}
+			fixer.Accept(change)
+		}
+		if err := fixer.Write(); err != nil {
+			return fmt.Errorf("write manifest: %w", err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Wrote %s (%d API categories added)\n", target, len(added))
+	for _, category := range added {
+		fmt.Fprintf(os.Stderr, "  + %s\n", category)
+	}
+	if len(added) > 0 {
+		fmt.Fprintln(os.Stderr, color.YellowString("Review the generated reason codes; they default to the most common reason."))
+	}
+	if created {
+		fmt.Fprintln(os.Stderr, color.YellowString("Add %s to your app target in Xcode so it is bundled.", target))
+		project.Files = append(project.Files, target)
+	}
+	return nil
+}
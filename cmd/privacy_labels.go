@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/labels"
+	"github.com/spf13/cobra"
+)
+
+var privacyLabelsCmd = &cobra.Command{
+	Use:   "privacy-labels [path]",
+	Short: "Draft the Play Data Safety form and App Store privacy label",
+	Long: `Draft the Google Play Data Safety form and the App Store privacy label
+from the project's Android permissions, Info.plist purpose strings, the SDKs
+found in dependency manifests and lockfiles, and the privacy manifest.
+Findings from a saved result (--from) or a completed scan (--scan) add their
+evidence.
+
+The draft is printed as a markdown checklist or as JSON. It lists
+inconsistencies such as tracking SDKs without NSPrivacyTracking or an App
+Tracking Transparency prompt, and data types missing from the privacy
+manifest. With --declared, a JSON draft holding what the store listings
+currently declare is compared against the detected data.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPrivacyLabels,
+}
+
+var (
+	labelsFrom      string
+	labelsScanID    string
+	labelsDeclared  string
+	labelsFormat    string
+	labelsOutput    string
+	labelsOutputDir string
+)
+
+func init() {
+	rootCmd.AddCommand(privacyLabelsCmd)
+
+	privacyLabelsCmd.Flags().StringVar(&labelsFrom, "from", "", "Saved JSON scan result to take evidence from")
+	privacyLabelsCmd.Flags().StringVar(&labelsScanID, "scan", "", "Scan ID to take evidence from")
+	privacyLabelsCmd.Flags().StringVar(&labelsDeclared, "declared", "", "JSON draft of the current store declarations to compare against")
+	privacyLabelsCmd.Flags().StringVarP(&labelsFormat, "format", "f", "markdown", "Output format: markdown, json")
+	privacyLabelsCmd.Flags().StringVarP(&labelsOutput, "output", "o", "", "Write output to file instead of stdout")
+	privacyLabelsCmd.Flags().StringVar(&labelsOutputDir, "output-dir", "", "Write privacy-labels.md and privacy-labels.json to this directory")
+}
+
+func runPrivacyLabels(cmd *cobra.Command, args []string) error {
+	if labelsFrom != "" && labelsScanID != "" {
+		return fmt.Errorf("--from and --scan cannot be used together")
+	}
+
+	project, err := loadLocalProject(args)
+	if err != nil {
+		return err
+	}
+
+	var findings []api.Finding
+	if labelsFrom != "" || labelsScanID != "" {
+		var result *api.ScanResult
+		if labelsFrom != "" {
+			result, err = loadScanResult(labelsFrom)
+		} else {
+			result, err = fetchScanResult(labelsScanID)
+		}
+		if err != nil {
+			return err
+		}
+		findings = result.Findings
+	}
+
+	var declared *labels.Draft
+	if labelsDeclared != "" {
+		data, err := os.ReadFile(labelsDeclared)
+		if err != nil {
+			return fmt.Errorf("read declared labels: %w", err)
+		}
+		declared = &labels.Draft{}
+		if err := json.Unmarshal(data, declared); err != nil {
+			return fmt.Errorf("parse declared labels %s: %w", labelsDeclared, err)
+		}
+	}
+
+	draft := labels.Build(project, findings, declared)
+
+	if labelsOutputDir != "" {
+		if err := os.MkdirAll(labelsOutputDir, 0755); err != nil {
+			return fmt.Errorf("create output directory: %w", err)
+		}
+		for _, f := range []struct{ format, name string }{{"markdown", "privacy-labels.md"}, {"json", "privacy-labels.json"}} {
+			formatted, err := labels.Format(draft, f.format)
+			if err != nil {
+				return err
+			}
+			path := filepath.Join(labelsOutputDir, f.name)
+			if err := os.WriteFile(path, formatted, 0644); err != nil {
+				return fmt.Errorf("write %s: %w", path, err)
+			}
+			if !IsQuiet() {
+				color.Green("✓ Wrote %s", path)
+			}
+		}
+	} else {
+		formatted, err := labels.Format(draft, strings.ToLower(labelsFormat))
+		if err != nil {
+			return err
+		}
+		if labelsOutput != "" {
+			if err := os.WriteFile(labelsOutput, formatted, 0644); err != nil {
+				return fmt.Errorf("write output file: %w", err)
+			}
+			if !IsQuiet() {
+				color.Green("✓ Privacy labels written to %s", labelsOutput)
+			}
+		} else {
+			fmt.Print(string(formatted))
+		}
+	}
+
+	if n := len(draft.Inconsistencies); n > 0 && !IsQuiet() {
+		fmt.Fprintln(os.Stderr, color.YellowString("%d inconsistencies between the code and the declarations", n))
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/config"
+	"github.com/hha-nguyen/canopy-cli/internal/rules"
+	"github.com/hha-nguyen/canopy-cli/internal/suppress"
+)
+
+func loadProjectConfig(explicit, dir string) (*config.ProjectConfig, error) {
+	path := explicit
+	if path == "" {
+		path = config.FindProjectConfig(dir)
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	cfg, err := config.LoadProject(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg.SetRoot(dir)
+
+	if IsDebug() {
+		fmt.Fprintln(os.Stderr, "Using project config:", path)
+	}
+	return cfg, nil
+}
+
+func applyPolicy(result *api.ScanResult, cfg *config.ProjectConfig, onlyRules, skipRules []string) {
+	policy := rules.Policy{Only: onlyRules, Skip: skipRules}
+	if cfg != nil {
+		policy.Overrides = cfg.Rules
+		policy.Only = append(append([]string{}, cfg.OnlyRules...), policy.Only...)
+		policy.Skip = append(append([]string{}, cfg.SkipRules...), policy.Skip...)
+	}
+
+	if !policy.IsEmpty() {
+		report := rules.Apply(result, policy)
+		if IsDebug() {
+			fmt.Fprintf(os.Stderr, "Rule policy removed %d findings and changed %d severities\n", report.Removed, report.Overrides)
+		}
+	}
+
+	if cfg != nil && len(cfg.Suppressions) > 0 {
+		report := suppress.Apply(result, cfg, time.Now())
+
+		for _, s := range report.Expired {
+			msg := fmt.Sprintf("Warning: suppression for %s expired on %s", s.Rule, s.Expires)
+			if s.Owner != "" {
+				msg += fmt.Sprintf(" (owner: %s)", s.Owner)
+			}
+			fmt.Fprintln(os.Stderr, color.YellowString(msg))
+		}
+
+		if IsDebug() {
+			for _, s := range report.Unused {
+				fmt.Fprintf(os.Stderr, "Suppression for %s matched no findings\n", s.Rule)
+			}
+		}
+	}
+}
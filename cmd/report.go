@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Work with saved scan results",
+	Long:  `Re-render and evaluate scan results saved with --format json.`,
+}
+
+var reportConvertCmd = &cobra.Command{
+	Use:   "convert <results.json>",
+	Short: "Convert saved JSON results to another format",
+	Long: `Load a scan result saved with --format json and render it with any
+supported formatter. Runs fully offline; no API key is required.
+
+Use - to read the results from stdin.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReportConvert,
+}
+
+var (
+	reportFormat    string
+	reportOutput    string
+	reportThreshold string
+	reportReports   []string
+	reportColumns   []string
+	reportBaseline  string
+	reportProject   string
+	reportOnlyRules []string
+	reportSkipRules []string
+)
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(reportConvertCmd)
+
+	reportConvertCmd.Flags().StringVarP(&reportFormat, "format", "f", "text", "Output format: text, json, sarif, junit, csv, tsv, checkstyle, sonarqube")
+	reportConvertCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Write output to file instead of stdout")
+	reportConvertCmd.Flags().StringVarP(&reportThreshold, "threshold", "t", "blocker", "Minimum severity to fail: blocker, high, medium, low")
+	reportConvertCmd.Flags().StringArrayVar(&reportReports, "report", nil, "Write a report as format=path (repeatable, path - for stdout)")
+	reportConvertCmd.Flags().StringSliceVar(&reportColumns, "columns", nil, "Columns for csv/tsv output (comma-separated)")
+	reportConvertCmd.Flags().StringVar(&reportBaseline, "baseline", "", "Baseline file of accepted findings; only new findings fail")
+	reportConvertCmd.Flags().StringVar(&reportProject, "project-config", "", "Project config file (default .canopy.yml in the current directory)")
+	reportConvertCmd.Flags().StringSliceVar(&reportOnlyRules, "only-rules", nil, "Only report rules matching these codes, globs or category:<name>")
+	reportConvertCmd.Flags().StringSliceVar(&reportSkipRules, "skip-rules", nil, "Ignore rules matching these codes, globs or category:<name>")
+}
+
+func runReportConvert(cmd *cobra.Command, args []string) error {
+	reports, err := resolveReports(cmd, reportFormat, reportOutput, reportReports)
+	if err != nil {
+		return err
+	}
+
+	if err := output.ValidateCSVColumns(reportColumns); err != nil {
+		return err
+	}
+
+	result, err := loadScanResult(args[0])
+	if err != nil {
+		return err
+	}
+
+	projectCfg, err := loadProjectConfig(reportProject, ".")
+	if err != nil {
+		return err
+	}
+
+	applyPolicy(result, projectCfg, reportOnlyRules, reportSkipRules)
+	if err := applyBaseline(result, reportBaseline); err != nil {
+		return err
+	}
+
+	opts := output.Options{ToolVersion: version, Columns: reportColumns}
+	return outputResults(result, reports, opts, resolveGate(cmd, reportThreshold, projectCfg))
+}
+
+func loadScanResult(path string) (*api.ScanResult, error) {
+	var r io.Reader
+	if path == output.Stdout {
+		r = os.Stdin
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("open results file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var result api.ScanResult
+	if err := json.NewDecoder(r).Decode(&result); err != nil {
+		return nil, fmt.Errorf("parse results file %s: %w", path, err)
+	}
+
+	if result.ID == "" && result.Findings == nil && result.Summary == nil {
+		return nil, fmt.Errorf("%s does not look like a Canopy JSON result", path)
+	}
+
+	result.RecomputeSummary()
+	return &result, nil
+}
+
+func fetchScanResult(id string) (*api.ScanResult, error) {
+	key, err := requireAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	client := api.NewClient(GetAPIURL(), key)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := client.GetScan(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get scan %s: %w", id, err)
+	}
+
+	result.RecomputeSummary()
+	return result, nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/fatih/color"
+	"github.com/hha-nguyen/canopy-cli/internal/config"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -79,6 +81,10 @@ func initConfig() {
 			fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 		}
 	}
+
+	if IsNoColor() {
+		color.NoColor = true
+	}
 }
 
 func GetAPIKey() string {
@@ -88,6 +94,18 @@ func GetAPIKey() string {
 	return viper.GetString("api_key")
 }
 
+func requireAPIKey() (string, error) {
+	key := config.GetAPIKey()
+	if key == "" {
+		key = GetAPIKey()
+	}
+
+	if key == "" {
+		return "", fmt.Errorf("authentication required. Run: canopy auth login")
+	}
+	return key, nil
+}
+
 func GetAPIURL() string {
 	if apiURL != "" {
 		return apiURL
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/sbom"
+	"github.com/spf13/cobra"
+)
+
+var sbomCmd = &cobra.Command{
+	Use:   "sbom [path]",
+	Short: "List the project's third-party SDKs",
+	Long: `Build an inventory of the SDKs the project depends on from its dependency
+manifests and lockfiles:
+
+  - Podfile.lock (CocoaPods)
+  - Package.resolved (Swift Package Manager)
+  - Cartfile.resolved (Carthage)
+  - gradle.lockfile / *.lockfile and gradle/libs.versions.toml (Gradle)
+  - pubspec.lock (Flutter)
+  - package-lock.json (npm, production dependencies only)
+
+The inventory is printed as CycloneDX 1.5 JSON or as a table. canopy scan
+attaches the same inventory to the upload unless --no-sbom is set.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSBOM,
+}
+
+var (
+	sbomFormat string
+	sbomOutput string
+)
+
+func init() {
+	rootCmd.AddCommand(sbomCmd)
+
+	sbomCmd.Flags().StringVarP(&sbomFormat, "format", "f", "cyclonedx", "Output format: cyclonedx, text")
+	sbomCmd.Flags().StringVarP(&sbomOutput, "output", "o", "", "Write output to file instead of stdout")
+}
+
+func runSBOM(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolve path: %w", err)
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a project directory", path)
+	}
+
+	inv, err := sbom.Collect(root)
+	if err != nil {
+		return fmt.Errorf("collect dependencies: %w", err)
+	}
+	for _, problem := range inv.Problems {
+		fmt.Fprintln(os.Stderr, color.YellowString("Warning: cannot parse %s", problem))
+	}
+
+	var formatted []byte
+	switch strings.ToLower(sbomFormat) {
+	case "cyclonedx", "json":
+		formatted, err = inv.CycloneDX(filepath.Base(root), version)
+		if err != nil {
+			return fmt.Errorf("encode sbom: %w", err)
+		}
+	case "text":
+		formatted = []byte(formatInventory(inv))
+	default:
+		return fmt.Errorf("unsupported format %q (supported: cyclonedx, text)", sbomFormat)
+	}
+
+	if sbomOutput != "" {
+		if err := os.WriteFile(sbomOutput, formatted, 0644); err != nil {
+			return fmt.Errorf("write output file: %w", err)
+		}
+		if !IsQuiet() {
+			color.Green("✓ %d components written to %s", len(inv.Components), sbomOutput)
+		}
+		return nil
+	}
+	fmt.Print(string(formatted))
+	return nil
+}
+
+func formatInventory(inv *sbom.Inventory) string {
+	if len(inv.Components) == 0 {
+		return "No dependency lockfiles found\n"
+	}
+
+	rows := [][]string{{"ECOSYSTEM", "NAME", "VERSION", "DIRECT", "FILE"}}
+	for _, c := range inv.Components {
+		name := c.Name
+		if c.Group != "" {
+			name = c.Group + ":" + c.Name
+		}
+		direct := ""
+		if c.Direct {
+			direct = "yes"
+		}
+		rows = append(rows, []string{c.Ecosystem, name, c.Version, direct, c.File})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+
+	var sb strings.Builder
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(row)-1 {
+				sb.WriteString(cell)
+			} else {
+				sb.WriteString(fmt.Sprintf("%-*s  ", widths[i], cell))
+			}
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(fmt.Sprintf("\n%d components from %d files\n", len(inv.Components), len(inv.Files)))
+	return sb.String()
+}
+
+// localInventory collects the dependency inventory of a directory before
+// upload and encodes it as CycloneDX. Failures only produce a warning.
+func localInventory(dir string, opts inputOptions, logf func(string, ...interface{})) (*sbom.Inventory, []byte) {
+	if opts.noSBOM {
+		return nil, nil
+	}
+
+	inv, err := sbom.Collect(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, color.YellowString("Warning: dependency inventory failed: %v", err))
+		return nil, nil
+	}
+	if len(inv.Components) == 0 {
+		return inv, nil
+	}
+
+	data, err := inv.CycloneDX(filepath.Base(dir), version)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, color.YellowString("Warning: encode sbom: %v", err))
+		return inv, nil
+	}
+	logf("Found %d SDKs in %d dependency files\n", len(inv.Components), len(inv.Files))
+	return inv, data
+}
+
+// attributeSDKs records the SDK of findings located in vendored dependency
+// directories, unless the server already did.
+func attributeSDKs(result *api.ScanResult, inv *sbom.Inventory) {
+	if inv == nil {
+		return
+	}
+	for i := range result.Findings {
+		f := &result.Findings[i]
+		if _, ok := f.Evidence["sdk"]; ok || f.FilePath == "" {
+			continue
+		}
+		c, ok := inv.Locate(f.FilePath)
+		if !ok {
+			continue
+		}
+		if f.Evidence == nil {
+			f.Evidence = make(map[string]interface{})
+		}
+		f.Evidence["sdk"] = c.Name
+		if c.Version != "" {
+			f.Evidence["sdk_version"] = c.Version
+		}
+	}
+}
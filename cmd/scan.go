@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -13,7 +14,12 @@ import (
 	"github.com/hha-nguyen/canopy-cli/internal/archive"
 	"github.com/hha-nguyen/canopy-cli/internal/config"
 	"github.com/hha-nguyen/canopy-cli/internal/exit"
+	"github.com/hha-nguyen/canopy-cli/internal/fingerprint"
+	"github.com/hha-nguyen/canopy-cli/internal/gate"
 	"github.com/hha-nguyen/canopy-cli/internal/output"
+	"github.com/hha-nguyen/canopy-cli/internal/plugins"
+	"github.com/hha-nguyen/canopy-cli/internal/sbom"
+	"github.com/hha-nguyen/canopy-cli/internal/vcs"
 	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
 )
@@ -24,33 +30,76 @@ var scanCmd = &cobra.Command{
 	Long: `Scan a project directory or archive file for mobile app policy violations.
 
 If a directory is provided, it will be compressed before upload.
-Supported archive formats: .zip, .tar.gz, .tgz`,
+Supported archive formats: .zip, .tar.gz, .tgz
+
+Built apps (.ipa, .apk, .aab) are scanned as shipped; the platform is inferred
+from the artifact. For Flutter and React Native directories it is picked from
+the ios/ and android/ folders, and native plugin metadata is attached.
+
+fastlane/metadata and fastlane/screenshots are left out of the upload unless
+--metadata is set, which also checks them locally (see canopy metadata check).`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runScan,
 }
 
 var (
-	scanPlatform   string
-	scanFormat     string
-	scanOutput     string
-	scanThreshold  string
-	scanProjectID  string
-	scanTimeout    time.Duration
-	scanNoProgress bool
-	scanFailOnErr  bool
+	scanPlatform     string
+	scanFormat       string
+	scanOutput       string
+	scanThreshold    string
+	scanProjectID    string
+	scanTimeout      time.Duration
+	scanNoProgress   bool
+	scanFailOnErr    bool
+	scanReports      []string
+	scanColumns      []string
+	scanBaseline     string
+	scanProjectCfg   string
+	scanOnlyRules    []string
+	scanSkipRules    []string
+	scanChangedSince string
+	scanRef          string
+	scanNoGit        bool
+	scanAllApps      bool
+	scanApps         []string
+	scanConcurrency  int
+	scanNoPreflight  bool
+	scanNoSBOM       bool
+	scanMetadata     bool
+	scanGit          api.GitMetadata
 )
 
 func init() {
 	rootCmd.AddCommand(scanCmd)
 
 	scanCmd.Flags().StringVarP(&scanPlatform, "platform", "p", "both", "Target platform: apple, google, both")
-	scanCmd.Flags().StringVarP(&scanFormat, "format", "f", "text", "Output format: text, json, sarif")
+	scanCmd.Flags().StringVarP(&scanFormat, "format", "f", "text", "Output format: text, json, sarif, junit, csv, tsv, checkstyle, sonarqube")
 	scanCmd.Flags().StringVarP(&scanOutput, "output", "o", "", "Write output to file instead of stdout")
 	scanCmd.Flags().StringVarP(&scanThreshold, "threshold", "t", "blocker", "Minimum severity to fail: blocker, high, medium, low")
 	scanCmd.Flags().StringVar(&scanProjectID, "project", "", "Associate scan with existing project ID")
 	scanCmd.Flags().DurationVar(&scanTimeout, "timeout", 5*time.Minute, "Scan timeout")
 	scanCmd.Flags().BoolVar(&scanNoProgress, "no-progress", false, "Disable progress updates")
 	scanCmd.Flags().BoolVar(&scanFailOnErr, "fail-on-error", true, "Exit with error if scan fails")
+	scanCmd.Flags().StringArrayVar(&scanReports, "report", nil, "Write a report as format=path (repeatable, path - for stdout)")
+	scanCmd.Flags().StringSliceVar(&scanColumns, "columns", nil, "Columns for csv/tsv output (comma-separated)")
+	scanCmd.Flags().StringVar(&scanBaseline, "baseline", "", "Baseline file of accepted findings; only new findings fail the scan")
+	scanCmd.Flags().StringVar(&scanProjectCfg, "project-config", "", "Project config file (default .canopy.yml in the scanned directory)")
+	scanCmd.Flags().StringSliceVar(&scanOnlyRules, "only-rules", nil, "Only report rules matching these codes, globs or category:<name>")
+	scanCmd.Flags().StringSliceVar(&scanSkipRules, "skip-rules", nil, "Ignore rules matching these codes, globs or category:<name>")
+	scanCmd.Flags().StringVar(&scanChangedSince, "changed-since", "", "Only scan files changed since this git ref (e.g. origin/main)")
+	scanCmd.Flags().StringVar(&scanRef, "ref", "", "Scan the directory as of this git revision instead of the working tree")
+	scanCmd.Flags().BoolVar(&scanNoGit, "no-git-metadata", false, "Do not detect or send git metadata")
+	scanCmd.Flags().StringVar(&scanGit.RepositoryURL, "git-repo", "", "Override the detected repository URL")
+	scanCmd.Flags().StringVar(&scanGit.Branch, "git-branch", "", "Override the detected branch")
+	scanCmd.Flags().StringVar(&scanGit.Commit, "git-commit", "", "Override the detected commit SHA")
+	scanCmd.Flags().StringVar(&scanGit.Author, "git-author", "", "Override the detected commit author")
+	scanCmd.Flags().StringVar(&scanGit.Tag, "git-tag", "", "Override the detected tag")
+	scanCmd.Flags().BoolVar(&scanAllApps, "all-apps", false, "Discover every app under the path and scan each one")
+	scanCmd.Flags().StringSliceVar(&scanApps, "app", nil, "Only scan these apps (names or paths) in multi-app mode")
+	scanCmd.Flags().BoolVar(&scanNoPreflight, "no-preflight", false, "Skip local preflight checks before upload")
+	scanCmd.Flags().BoolVar(&scanNoSBOM, "no-sbom", false, "Do not attach the dependency inventory to the upload")
+	scanCmd.Flags().BoolVar(&scanMetadata, "metadata", false, "Check and upload fastlane store metadata and screenshots")
+	scanCmd.Flags().IntVar(&scanConcurrency, "concurrency", 4, "Number of apps scanned in parallel in multi-app mode")
 }
 
 func runScan(cmd *cobra.Command, args []string) error {
@@ -64,15 +113,18 @@ func runScan(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("resolve path: %w", err)
 	}
 
-	apiKey := config.GetAPIKey()
-	if apiKey == "" {
-		if key := GetAPIKey(); key != "" {
-			apiKey = key
-		}
+	reports, err := resolveReports(cmd, scanFormat, scanOutput, scanReports)
+	if err != nil {
+		return err
 	}
 
-	if apiKey == "" {
-		return fmt.Errorf("authentication required. Run: canopy auth login")
+	if err := output.ValidateCSVColumns(scanColumns); err != nil {
+		return err
+	}
+
+	apiKey, err := requireAPIKey()
+	if err != nil {
+		return err
 	}
 
 	info, err := os.Stat(absPath)
@@ -80,68 +132,282 @@ func runScan(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("access path: %w", err)
 	}
 
-	var archivePath string
-	var cleanup func()
+	if scanRef != "" {
+		if !info.IsDir() {
+			return fmt.Errorf("--ref requires a directory path")
+		}
+		if scanChangedSince != "" {
+			return fmt.Errorf("--ref cannot be combined with --changed-since")
+		}
+	}
+	if scanChangedSince != "" && !info.IsDir() {
+		return fmt.Errorf("--changed-since requires a directory path")
+	}
+
+	vcsDir := absPath
+	if !info.IsDir() {
+		vcsDir = filepath.Dir(absPath)
+	}
+
+	projectCfg, err := loadProjectConfig(scanProjectCfg, vcsDir)
+	if err != nil {
+		return err
+	}
+	gatePolicy := resolveGate(cmd, scanThreshold, projectCfg)
+
+	formatOpts := output.Options{ToolVersion: version, Columns: scanColumns}
+	gitMeta, err := collectGitMetadata(vcsDir, scanGitOptions())
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(GetAPIURL(), apiKey)
 
-	if info.IsDir() {
+	ctx, cancel := context.WithTimeout(context.Background(), scanTimeout)
+	defer cancel()
+
+	multiApp := scanAllApps || len(scanApps) > 0 || (projectCfg != nil && len(projectCfg.Apps) > 0)
+	if multiApp && info.IsDir() {
+		apps, err := resolveApps(absPath, projectCfg, scanApps, cmd.Flags().Changed("platform"))
+		if err != nil {
+			return err
+		}
+		return runAppScans(ctx, client, absPath, apps, scanInputOptions(), gitMeta, projectCfg, reports, formatOpts, gatePolicy)
+	}
+
+	logf := func(format string, args ...interface{}) {
 		if !IsQuiet() {
-			fmt.Printf("Compressing %s...\n", absPath)
+			fmt.Printf(format, args...)
 		}
+	}
+
+	input, err := prepareScanInput(absPath, info, scanInputOptions(), logf)
+	if err != nil || input == nil {
+		return err
+	}
+	defer input.cleanup()
+
+	platform, err := resolvePlatform(scanPlatform, cmd.Flags().Changed("platform"), input.archiveType)
+	if err != nil {
+		return err
+	}
+	if input.plugins != nil && !cmd.Flags().Changed("platform") {
+		platform = parsePlatform(input.plugins.Platform)
+	}
 
-		tmpFile, err := os.CreateTemp("", "canopy-*.tar.gz")
+	result, err := runRemoteScan(ctx, client, input.archivePath, platform, !scanNoProgress && !IsQuiet(), logf,
+		api.WithProjectID(scanProjectID),
+		api.WithArtifactType(input.artifactType()),
+		api.WithGitMetadata(gitMeta),
+		api.WithSBOM(input.sbom),
+		api.WithFramework(input.framework(), input.pluginData),
+	)
+	if err != nil {
+		return err
+	}
+	if result.Git == nil {
+		result.Git = gitMeta
+	}
+
+	if result.Status == "FAILED" {
+		if scanFailOnErr {
+			return fmt.Errorf("scan failed")
+		}
+		return outputResults(result, reports, formatOpts, gatePolicy)
+	}
+
+	input.apply(result)
+	applyPolicy(result, projectCfg, scanOnlyRules, scanSkipRules)
+	if err := applyBaseline(result, scanBaseline); err != nil {
+		return err
+	}
+	return outputResults(result, reports, formatOpts, gatePolicy)
+}
+
+type scanInput struct {
+	archivePath  string
+	archiveType  archive.ArchiveType
+	cleanup      func()
+	changed      map[string]bool
+	local        []api.Finding
+	entitlements []api.TargetEntitlements
+	inventory    *sbom.Inventory
+	sbom         []byte
+	plugins      *plugins.Project
+	pluginData   []byte
+}
+
+// framework names the cross-platform framework of a directory, or "".
+func (in *scanInput) framework() string {
+	if in.plugins == nil {
+		return ""
+	}
+	return in.plugins.Framework
+}
+
+// apply adds what was collected locally to the server result: preflight
+// findings, entitlements and SDK and plugin attribution. With
+// --changed-since, findings in unchanged files are dropped.
+func (in *scanInput) apply(result *api.ScanResult) {
+	mergeLocalFindings(result, in.local)
+	mergeEntitlements(result, in.entitlements)
+	attributeSDKs(result, in.inventory)
+	attributePlugins(result, in.plugins)
+	if in.changed != nil {
+		filterChangedFindings(result, in.changed)
+	}
+}
+
+// inputOptions control how a directory is prepared for upload. canopy scan
+// sets them from its flags; scan-batch and baseline create use the defaults.
+type inputOptions struct {
+	changedSince string
+	ref          string
+	noPreflight  bool
+	noSBOM       bool
+	metadata     bool
+}
+
+func scanInputOptions() inputOptions {
+	return inputOptions{
+		changedSince: scanChangedSince,
+		ref:          scanRef,
+		noPreflight:  scanNoPreflight,
+		noSBOM:       scanNoSBOM,
+		metadata:     scanMetadata,
+	}
+}
+
+// collectLocal runs the local checks and dependency and plugin detection
+// on a directory. Nothing is collected for a ref.
+func collectLocal(dir string, opts inputOptions, logf func(string, ...interface{})) *scanInput {
+	input := &scanInput{archiveType: archive.ArchiveTypeTarGz}
+	if opts.ref != "" {
+		// The checks read the working tree, which need not match the ref.
+		logf("Skipping local preflight checks, dependency inventory and plugin detection with --ref\n")
+		return input
+	}
+	input.local, input.entitlements = localFindings(dir, opts, logf)
+	input.inventory, input.sbom = localInventory(dir, opts, logf)
+	input.plugins, input.pluginData = localPlugins(dir, logf)
+	return input
+}
+
+func (in *scanInput) artifactType() string {
+	if in.archiveType.IsBinary() {
+		return string(in.archiveType)
+	}
+	return "source"
+}
+
+// resolvePlatform infers the platform of binary artifacts. An explicit
+// --platform naming the other store is rejected.
+func resolvePlatform(requested string, explicit bool, archiveType archive.ArchiveType) (api.Platform, error) {
+	platform := parsePlatform(requested)
+
+	inferred := archiveType.Platform()
+	if inferred == "" {
+		return platform, nil
+	}
+
+	if explicit && platform != api.PlatformBoth && platform != parsePlatform(inferred) {
+		return "", fmt.Errorf("platform %s does not match the .%s artifact (built for %s)", requested, archiveType, inferred)
+	}
+	return parsePlatform(inferred), nil
+}
+
+// prepareScanInput compresses a directory (honoring --ref and
+// --changed-since) or validates an archive. It returns nil when
+// --changed-since found nothing to scan.
+func prepareScanInput(absPath string, info os.FileInfo, opts inputOptions, logf func(string, ...interface{})) (*scanInput, error) {
+	if !info.IsDir() {
+		if !archive.IsArchive(absPath) {
+			return nil, fmt.Errorf("unsupported file type. Use .zip, .tar.gz, .ipa, .apk or .aab, or provide a directory path")
+		}
+
+		archiveType, err := archive.ValidateArchive(absPath)
 		if err != nil {
-			return fmt.Errorf("create temp file: %w", err)
+			return nil, err
 		}
-		tmpFile.Close()
 
-		archivePath = tmpFile.Name()
-		cleanup = func() { os.Remove(archivePath) }
+		return &scanInput{archivePath: absPath, archiveType: archiveType, cleanup: func() {}}, nil
+	}
+
+	input := collectLocal(absPath, opts, logf)
 
-		if err := archive.CompressDirectory(absPath, archivePath, nil); err != nil {
-			cleanup()
-			return fmt.Errorf("compress directory: %w", err)
+	compressOpts := archive.DefaultCompressOptions()
+	if !opts.metadata {
+		compressOpts.IgnorePatterns = append(compressOpts.IgnorePatterns, storeMetadataDirs...)
+	}
+	if opts.changedSince != "" {
+		files, err := vcs.ChangedFiles(absPath, opts.changedSince)
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		if !archive.IsArchive(absPath) {
-			return fmt.Errorf("unsupported file type. Use .zip or .tar.gz, or provide a directory path")
+		if len(files) == 0 {
+			logf("No files changed since %s, nothing to scan\n", opts.changedSince)
+			return nil, nil
 		}
 
-		if _, err := archive.ValidateArchive(absPath); err != nil {
-			return err
+		changed := make(map[string]bool, len(files))
+		for _, f := range files {
+			changed[fingerprint.NormalizePath(f)] = true
 		}
+		compressOpts.Include = func(rel string) bool {
+			return changed[rel] || archive.IsContextFile(rel)
+		}
+		input.changed = changed
 
-		archivePath = absPath
-		cleanup = func() {}
+		logf("%d files changed since %s\n", len(files), opts.changedSince)
 	}
-	defer cleanup()
 
-	client := api.NewClient(GetAPIURL(), apiKey)
+	if opts.ref != "" {
+		logf("Compressing %s at %s...\n", absPath, opts.ref)
+	} else {
+		logf("Compressing %s...\n", absPath)
+	}
 
-	platform := parsePlatform(scanPlatform)
+	tmpFile, err := os.CreateTemp("", "canopy-*.tar.gz")
+	if err != nil {
+		return nil, fmt.Errorf("create temp file: %w", err)
+	}
+	tmpFile.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), scanTimeout)
-	defer cancel()
+	input.archivePath = tmpFile.Name()
+	input.cleanup = func() { os.Remove(input.archivePath) }
 
-	if !IsQuiet() {
-		fmt.Printf("Uploading to Canopy...\n")
+	if opts.ref != "" {
+		err = compressRef(absPath, opts.ref, input.archivePath, compressOpts)
+	} else {
+		err = archive.CompressDirectory(absPath, input.archivePath, compressOpts)
 	}
+	if err != nil {
+		input.cleanup()
+		return nil, fmt.Errorf("compress directory: %w", err)
+	}
+
+	return input, nil
+}
+
+// runRemoteScan uploads an archive and polls until the scan completes or
+// fails. Post-processing of the result is left to the caller.
+func runRemoteScan(ctx context.Context, client *api.Client, archivePath string, platform api.Platform, showProgress bool, logf func(string, ...interface{}), opts ...api.ScanOption) (*api.ScanResult, error) {
+	logf("Uploading to Canopy...\n")
 
-	scanResp, err := client.CreateScan(ctx, archivePath, platform)
+	scanResp, err := client.CreateScan(ctx, archivePath, platform, opts...)
 	if err != nil {
 		if apiErr, ok := err.(*api.APIError); ok {
 			if apiErr.StatusCode == 401 || apiErr.StatusCode == 403 {
-				return fmt.Errorf("authentication failed: %s", apiErr.Message)
+				return nil, fmt.Errorf("authentication failed: %s", apiErr.Message)
 			}
 		}
-		return fmt.Errorf("create scan: %w", err)
+		return nil, fmt.Errorf("create scan: %w", err)
 	}
 
-	if !IsQuiet() {
-		fmt.Printf("Scan started: %s\n", scanResp.ID)
-	}
+	logf("Scan started: %s\n", scanResp.ID)
 
 	var bar *progressbar.ProgressBar
-	if !scanNoProgress && !IsQuiet() {
+	if showProgress {
 		bar = progressbar.NewOptions(100,
 			progressbar.OptionSetDescription("Scanning"),
 			progressbar.OptionSetWidth(40),
@@ -156,18 +422,17 @@ func runScan(cmd *cobra.Command, args []string) error {
 		)
 	}
 
-	var result *api.ScanResult
 	pollTicker := time.NewTicker(2 * time.Second)
 	defer pollTicker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("scan timeout")
+			return nil, fmt.Errorf("scan timeout")
 		case <-pollTicker.C:
-			result, err = client.GetScan(ctx, scanResp.ID)
+			result, err := client.GetScan(ctx, scanResp.ID)
 			if err != nil {
-				return fmt.Errorf("get scan status: %w", err)
+				return nil, fmt.Errorf("get scan status: %w", err)
 			}
 
 			switch result.Status {
@@ -176,16 +441,13 @@ func runScan(cmd *cobra.Command, args []string) error {
 					bar.Set(100)
 					fmt.Println()
 				}
-				return outputResults(result)
+				return result, nil
 
 			case "FAILED":
 				if bar != nil {
 					fmt.Println()
 				}
-				if scanFailOnErr {
-					return fmt.Errorf("scan failed")
-				}
-				return outputResults(result)
+				return result, nil
 
 			case "PROCESSING":
 				if bar != nil && result.Summary != nil {
@@ -202,36 +464,179 @@ func runScan(cmd *cobra.Command, args []string) error {
 	}
 }
 
-func outputResults(result *api.ScanResult) error {
-	formatter := output.NewFormatter(scanFormat, IsNoColor())
+// compressRef archives dir as it exists at ref, read from the git object
+// database so the working tree is left untouched. The .canopyignore file is
+// also read at ref.
+func compressRef(dir, ref, dest string, opts *archive.CompressOptions) error {
+	if data, err := vcs.ShowFile(dir, ref, ".canopyignore"); err == nil {
+		patterns, err := archive.ParseIgnorePatterns(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("read .canopyignore at %s: %w", ref, err)
+		}
+		opts.IgnorePatterns = append(opts.IgnorePatterns, patterns...)
+	}
 
-	formatted, err := formatter.Format(result)
+	src, err := vcs.ArchiveRef(dir, ref)
 	if err != nil {
-		return fmt.Errorf("format output: %w", err)
+		return err
 	}
 
-	if scanOutput != "" {
-		if err := os.WriteFile(scanOutput, formatted, 0644); err != nil {
-			return fmt.Errorf("write output file: %w", err)
+	if err := archive.CompressTar(src, dest, opts); err != nil {
+		src.Close()
+		return err
+	}
+	return src.Close()
+}
+
+// gitOptions control git metadata detection. canopy scan sets them from
+// --ref, --no-git-metadata and the --git-* overrides.
+type gitOptions struct {
+	ref       string
+	disabled  bool
+	overrides api.GitMetadata
+}
+
+func scanGitOptions() gitOptions {
+	return gitOptions{ref: scanRef, disabled: scanNoGit, overrides: scanGit}
+}
+
+// collectGitMetadata detects git metadata from the repository and CI
+// environment; explicit overrides take precedence. With a ref, the
+// metadata describes that revision.
+func collectGitMetadata(dir string, opts gitOptions) (*api.GitMetadata, error) {
+	info := &vcs.Info{}
+	if opts.ref != "" && !opts.disabled {
+		refInfo, err := vcs.CollectRef(dir, opts.ref)
+		if err != nil {
+			return nil, err
 		}
-		if !IsQuiet() {
-			color.Green("✓ Results written to %s", scanOutput)
+		info = refInfo
+	} else if !opts.disabled {
+		info = vcs.Collect(dir)
+	}
+
+	info.Merge(&vcs.Info{
+		RepositoryURL: opts.overrides.RepositoryURL,
+		Branch:        opts.overrides.Branch,
+		Commit:        opts.overrides.Commit,
+		Author:        opts.overrides.Author,
+		Tag:           opts.overrides.Tag,
+	})
+
+	if info.IsEmpty() {
+		return nil, nil
+	}
+
+	if IsDebug() {
+		fmt.Fprintf(os.Stderr, "Git metadata: %s@%s (branch %q, tag %q, dirty %t)\n",
+			info.RepositoryURL, info.Commit, info.Branch, info.Tag, info.Dirty)
+	}
+
+	return &api.GitMetadata{
+		RepositoryURL: info.RepositoryURL,
+		Branch:        info.Branch,
+		Commit:        info.Commit,
+		Author:        info.Author,
+		Tag:           info.Tag,
+		Dirty:         info.Dirty,
+		CIProvider:    info.CIProvider,
+	}, nil
+}
+
+// filterChangedFindings drops findings in files outside the change set.
+// Findings without a file path apply to the whole project and are kept.
+func filterChangedFindings(result *api.ScanResult, changed map[string]bool) {
+	kept := result.Findings[:0]
+	for _, finding := range result.Findings {
+		if finding.FilePath == "" || changed[fingerprint.NormalizePath(finding.FilePath)] {
+			kept = append(kept, finding)
 		}
-	} else {
-		fmt.Print(string(formatted))
+	}
+	result.Findings = kept
+	result.RecomputeSummary()
+}
+
+func resolveReports(cmd *cobra.Command, format, outputPath string, specs []string) ([]output.Report, error) {
+	if len(specs) == 0 {
+		if !output.IsSupportedFormat(format) {
+			return nil, fmt.Errorf("unsupported format %q (supported: %s)", format, strings.Join(output.SupportedFormats, ", "))
+		}
+		return []output.Report{{Format: format, Path: outputPath}}, nil
+	}
+
+	if cmd.Flags().Changed("format") || cmd.Flags().Changed("output") {
+		return nil, fmt.Errorf("--report cannot be combined with --format or --output")
+	}
+
+	reports := make([]output.Report, 0, len(specs))
+	stdout := ""
+	for _, spec := range specs {
+		report, err := output.ParseReport(spec)
+		if err != nil {
+			return nil, err
+		}
+		if report.IsStdout() {
+			if stdout != "" {
+				return nil, fmt.Errorf("--report %s and --report %s both write to stdout; only one report can use -", stdout, spec)
+			}
+			stdout = spec
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+func outputResults(result *api.ScanResult, reports []output.Report, opts output.Options, policy config.GateConfig) error {
+	result.Gate = gate.Evaluate(result, policy)
+
+	for _, report := range reports {
+		if err := writeReport(result, report, opts); err != nil {
+			return err
+		}
+	}
+
+	if !result.Gate.Passed {
+		for _, violation := range result.Gate.Violations {
+			fmt.Fprintln(os.Stderr, color.RedString("✗ Gate failed: %s", violation))
+		}
+		os.Exit(exit.IssuesFound)
+	}
+
+	return nil
+}
+
+func resolveGate(cmd *cobra.Command, threshold string, projectCfg *config.ProjectConfig) config.GateConfig {
+	thresholdGate := gate.FromThreshold(exit.ParseThreshold(threshold))
+
+	if projectCfg == nil || projectCfg.Gate == nil {
+		return thresholdGate
+	}
+	if cmd.Flags().Changed("threshold") {
+		return gate.Merge(*projectCfg.Gate, thresholdGate)
+	}
+	return gate.WithDefault(*projectCfg.Gate)
+}
+
+func writeReport(result *api.ScanResult, report output.Report, opts output.Options) error {
+	opts.NoColor = IsNoColor() || !report.IsStdout()
+	formatter := output.NewFormatter(report.Format, opts)
+
+	formatted, err := formatter.Format(result)
+	if err != nil {
+		return fmt.Errorf("format %s output: %w", report.Format, err)
 	}
 
-	summary := exit.ScanSummary{}
-	if result.Summary != nil {
-		summary.Blocker = result.Summary.Blocker
-		summary.High = result.Summary.High
-		summary.Medium = result.Summary.Medium
-		summary.Low = result.Summary.Low
+	if report.IsStdout() {
+		fmt.Print(string(formatted))
+		return nil
 	}
 
-	exitCode := exit.DetermineExitCode(summary, exit.ParseThreshold(scanThreshold))
-	if exitCode != exit.Success {
-		os.Exit(exitCode)
+	if err := os.WriteFile(report.Path, formatted, 0644); err != nil {
+		return fmt.Errorf("write output file: %w", err)
+	}
+	if !IsQuiet() {
+		color.Green("✓ %s results written to %s", strings.ToUpper(report.Format), report.Path)
 	}
 
 	return nil
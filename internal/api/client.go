@@ -15,6 +15,7 @@ type Client struct {
 	apiKey     string
 	httpClient *http.Client
 	debug      bool
+	limiter    *RateLimiter
 }
 
 type ClientOption func(*Client)
@@ -31,6 +32,12 @@ func WithDebug(debug bool) ClientOption {
 	}
 }
 
+func WithRateLimiter(l *RateLimiter) ClientOption {
+	return func(client *Client) {
+		client.limiter = l
+	}
+}
+
 func NewClient(baseURL, apiKey string, opts ...ClientOption) *Client {
 	c := &Client{
 		baseURL: baseURL,
@@ -78,6 +85,10 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	}
 
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("send request: %w", err)
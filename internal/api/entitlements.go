@@ -0,0 +1,19 @@
+package api
+
+// TargetEntitlements lists the capabilities an iOS target declares in its
+// entitlements file and Info.plist.
+type TargetEntitlements struct {
+	Target       string       `json:"target"`
+	BundleID     string       `json:"bundle_id,omitempty"`
+	File         string       `json:"file,omitempty"`
+	App          string       `json:"app,omitempty"`
+	Capabilities []Capability `json:"capabilities"`
+}
+
+type Capability struct {
+	Name   string   `json:"name"`
+	Key    string   `json:"key"`
+	Values []string `json:"values,omitempty"`
+	// Source is "entitlements" or "Info.plist".
+	Source string `json:"source"`
+}
@@ -0,0 +1,130 @@
+package api
+
+import "strings"
+
+const (
+	BaselineNew       = "new"
+	BaselineUnchanged = "unchanged"
+	BaselineAbsent    = "absent"
+)
+
+type BaselineResult struct {
+	Path          string    `json:"path,omitempty"`
+	New           int       `json:"new"`
+	Unchanged     int       `json:"unchanged"`
+	Fixed         int       `json:"fixed"`
+	FixedFindings []Finding `json:"fixed_findings,omitempty"`
+}
+
+type GateResult struct {
+	Passed     bool     `json:"passed"`
+	Violations []string `json:"violations,omitempty"`
+}
+
+// AppResult describes one app of a multi-app scan. Its findings are merged
+// into the parent result with paths relative to the repository root.
+type AppResult struct {
+	Name     string       `json:"name"`
+	Path     string       `json:"path"`
+	Platform string       `json:"platform"`
+	ScanID   string       `json:"scan_id,omitempty"`
+	Status   string       `json:"status"`
+	Error    string       `json:"error,omitempty"`
+	Summary  *ScanSummary `json:"summary,omitempty"`
+}
+
+// ScanIDFor returns the ID of the scan that produced f, which differs from
+// the result ID for multi-app scans.
+func (r *ScanResult) ScanIDFor(f Finding) string {
+	if f.App != "" {
+		for _, app := range r.Apps {
+			if app.Name == f.App {
+				return app.ScanID
+			}
+		}
+	}
+	return r.ID
+}
+
+type Suppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification"`
+	Owner         string `json:"owner,omitempty"`
+	Expires       string `json:"expires,omitempty"`
+	Source        string `json:"source,omitempty"`
+}
+
+// Accepted reports whether the finding has been accepted client-side and
+// should not count towards the summary or exit code.
+func (f Finding) Accepted() bool {
+	return f.BaselineState == BaselineUnchanged || f.Suppressed()
+}
+
+func (f Finding) Suppressed() bool {
+	return len(f.Suppressions) > 0
+}
+
+// RuleCategory returns the finding category, falling back to the rule code
+// without its numeric suffix (APL-PRIV-012 -> APL-PRIV).
+func (f Finding) RuleCategory() string {
+	if f.Category != "" {
+		return f.Category
+	}
+	if i := strings.LastIndex(f.RuleCode, "-"); i > 0 {
+		return f.RuleCode[:i]
+	}
+	return f.RuleCode
+}
+
+func IsValidSeverity(severity string) bool {
+	return SeverityRank(severity) > 0 || strings.EqualFold(severity, "INFO")
+}
+
+func SeverityRank(severity string) int {
+	switch strings.ToUpper(severity) {
+	case "BLOCKER":
+		return 4
+	case "HIGH":
+		return 3
+	case "MEDIUM":
+		return 2
+	case "LOW":
+		return 1
+	default:
+		return 0
+	}
+}
+
+func Summarize(findings []Finding, passed int) *ScanSummary {
+	summary := &ScanSummary{Passed: passed}
+
+	for _, f := range findings {
+		if f.Accepted() {
+			continue
+		}
+
+		switch strings.ToUpper(f.Severity) {
+		case "BLOCKER":
+			summary.Blocker++
+		case "HIGH":
+			summary.High++
+		case "MEDIUM":
+			summary.Medium++
+		case "LOW":
+			summary.Low++
+		default:
+			summary.Info++
+		}
+		summary.Total++
+	}
+
+	return summary
+}
+
+func (r *ScanResult) RecomputeSummary() {
+	passed := 0
+	if r.Summary != nil {
+		passed = r.Summary.Passed
+	}
+	r.Summary = Summarize(r.Findings, passed)
+}
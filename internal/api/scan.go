@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 )
 
@@ -41,6 +42,12 @@ type ScanResult struct {
 	Errors         []string        `json:"errors,omitempty"`
 	CreatedAt      time.Time       `json:"created_at"`
 	CompletedAt    *time.Time      `json:"completed_at,omitempty"`
+	Git            *GitMetadata    `json:"git,omitempty"`
+	Baseline       *BaselineResult `json:"baseline,omitempty"`
+	Gate           *GateResult     `json:"gate,omitempty"`
+	Apps           []AppResult     `json:"apps,omitempty"`
+
+	Entitlements []TargetEntitlements `json:"entitlements,omitempty"`
 }
 
 type RiskAssessment struct {
@@ -69,6 +76,15 @@ type Finding struct {
 	Evidence    map[string]interface{} `json:"evidence,omitempty"`
 	Remediation *Remediation           `json:"remediation,omitempty"`
 	DocsURL     string                 `json:"docs_url,omitempty"`
+	Category    string                 `json:"category,omitempty"`
+	Platform    string                 `json:"platform,omitempty"`
+	App         string                 `json:"app,omitempty"`
+	Source      string                 `json:"source,omitempty"`
+
+	Fingerprint      string        `json:"fingerprint,omitempty"`
+	OriginalSeverity string        `json:"original_severity,omitempty"`
+	BaselineState    string        `json:"baseline_state,omitempty"`
+	Suppressions     []Suppression `json:"suppressions,omitempty"`
 }
 
 type Remediation struct {
@@ -76,14 +92,99 @@ type Remediation struct {
 	Template string `json:"template,omitempty"`
 }
 
-func (c *Client) CreateScan(ctx context.Context, filePath string, platform Platform) (*CreateScanResponse, error) {
+type GitMetadata struct {
+	RepositoryURL string `json:"repository_url,omitempty"`
+	Branch        string `json:"branch,omitempty"`
+	Commit        string `json:"commit,omitempty"`
+	Author        string `json:"author,omitempty"`
+	Tag           string `json:"tag,omitempty"`
+	Dirty         bool   `json:"dirty,omitempty"`
+	CIProvider    string `json:"ci_provider,omitempty"`
+}
+
+type scanForm struct {
+	fields      [][2]string
+	attachments []attachment
+}
+
+type attachment struct {
+	field    string
+	filename string
+	data     []byte
+}
+
+func (f *scanForm) set(name, value string) {
+	if value != "" {
+		f.fields = append(f.fields, [2]string{name, value})
+	}
+}
+
+type ScanOption func(*scanForm)
+
+func WithProjectID(id string) ScanOption {
+	return func(f *scanForm) {
+		f.set("project_id", id)
+	}
+}
+
+// WithArtifactType tells the server whether the upload holds sources
+// ("source") or a built app ("ipa", "apk", "aab").
+func WithArtifactType(t string) ScanOption {
+	return func(f *scanForm) {
+		f.set("artifact_type", t)
+	}
+}
+
+func WithGitMetadata(m *GitMetadata) ScanOption {
+	return func(f *scanForm) {
+		if m == nil {
+			return
+		}
+		f.set("git_repository_url", m.RepositoryURL)
+		f.set("git_branch", m.Branch)
+		f.set("git_commit", m.Commit)
+		f.set("git_author", m.Author)
+		f.set("git_tag", m.Tag)
+		f.set("git_dirty", strconv.FormatBool(m.Dirty))
+		f.set("ci_provider", m.CIProvider)
+	}
+}
+
+// WithSBOM attaches a CycloneDX inventory of the project's dependencies so
+// findings can be attributed to SDKs.
+func WithSBOM(data []byte) ScanOption {
+	return func(f *scanForm) {
+		if len(data) > 0 {
+			f.attachments = append(f.attachments, attachment{"sbom", "sbom.cdx.json", data})
+		}
+	}
+}
+
+// WithFramework names the cross-platform framework of the project (flutter,
+// react-native) and attaches its native plugin metadata as JSON.
+func WithFramework(framework string, plugins []byte) ScanOption {
+	return func(f *scanForm) {
+		f.set("framework", framework)
+		if len(plugins) > 0 {
+			f.attachments = append(f.attachments, attachment{"plugins", "plugins.json", plugins})
+		}
+	}
+}
+
+func (c *Client) CreateScan(ctx context.Context, filePath string, platform Platform, opts ...ScanOption) (*CreateScanResponse, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("open file: %w", err)
 	}
 	defer file.Close()
 
-	body, contentType, err := createMultipartForm(file, filepath.Base(filePath), string(platform))
+	form := &scanForm{}
+	form.set("platform", string(platform))
+	for _, opt := range opts {
+		opt(form)
+	}
+
+	body, contentType, err := createMultipartForm(file, filepath.Base(filePath), form)
 	if err != nil {
 		return nil, fmt.Errorf("create multipart form: %w", err)
 	}
@@ -98,6 +199,10 @@ func (c *Client) CreateScan(ctx context.Context, filePath string, platform Platf
 		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	}
 
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
@@ -136,7 +241,7 @@ func (c *Client) CancelScan(ctx context.Context, id string) error {
 	return c.Post(ctx, "/api/v1/scans/"+id+"/cancel", nil, nil)
 }
 
-func createMultipartForm(file *os.File, filename, platform string) (io.Reader, string, error) {
+func createMultipartForm(file *os.File, filename string, form *scanForm) (io.Reader, string, error) {
 	pr, pw := io.Pipe()
 	writer := multipart.NewWriter(pw)
 
@@ -144,9 +249,23 @@ func createMultipartForm(file *os.File, filename, platform string) (io.Reader, s
 		defer pw.Close()
 		defer writer.Close()
 
-		if err := writer.WriteField("platform", platform); err != nil {
-			pw.CloseWithError(err)
-			return
+		for _, field := range form.fields {
+			if err := writer.WriteField(field[0], field[1]); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+
+		for _, a := range form.attachments {
+			part, err := writer.CreateFormFile(a.field, a.filename)
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if _, err := part.Write(a.data); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
 		}
 
 		part, err := writer.CreateFormFile("file", filename)
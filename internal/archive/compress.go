@@ -7,29 +7,33 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 )
 
+// Patterns ending in "/" name directories and match whole path segments, so
+// "build/" skips build/ directories but not build.gradle. Other patterns
+// match file names, "*" suffixes or any part of the path.
 var defaultIgnorePatterns = []string{
-	".git",
-	".svn",
-	".hg",
-	"node_modules",
-	".gradle",
-	"build",
-	"DerivedData",
-	"Pods",
-	".idea",
-	".vscode",
+	".git/",
+	".svn/",
+	".hg/",
+	"node_modules/",
+	".gradle/",
+	"build/",
+	"DerivedData/",
+	"Pods/",
+	".idea/",
+	".vscode/",
 	"*.xcworkspace",
 	".DS_Store",
 	"*.log",
-	"__pycache__",
+	"__pycache__/",
 	"*.pyc",
 	"*.class",
-	"target",
-	"dist",
+	"target/",
+	"dist/",
 	"*.tmp",
 	"*.temp",
 }
@@ -38,6 +42,30 @@ type CompressOptions struct {
 	IgnorePatterns []string
 	MaxSize        int64
 	ShowProgress   bool
+	// Include restricts the archive to files for which it returns true.
+	// Directories are always traversed and only written implicitly.
+	Include func(relPath string) bool
+}
+
+var contextFileNames = map[string]bool{
+	"Info.plist":          true,
+	"AndroidManifest.xml": true,
+	"build.gradle":        true,
+	"build.gradle.kts":    true,
+	"settings.gradle":     true,
+	"settings.gradle.kts": true,
+	"Podfile":             true,
+	"Podfile.lock":        true,
+	"pubspec.yaml":        true,
+	"package.json":        true,
+	".canopyignore":       true,
+}
+
+// IsContextFile reports whether a file is needed to interpret a project
+// even when it has not changed.
+func IsContextFile(relPath string) bool {
+	name := filepath.Base(relPath)
+	return contextFileNames[name] || strings.HasSuffix(name, ".entitlements")
 }
 
 func DefaultCompressOptions() *CompressOptions {
@@ -85,13 +113,17 @@ func CompressDirectory(srcDir, destFile string, opts *CompressOptions) error {
 			return nil
 		}
 
-		if shouldIgnore(relPath, info, ignorePatterns) {
+		if shouldIgnore(relPath, info.Name(), ignorePatterns) {
 			if info.IsDir() {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
+		if opts.Include != nil && (info.IsDir() || !opts.Include(filepath.ToSlash(relPath))) {
+			return nil
+		}
+
 		header, err := tar.FileInfoHeader(info, "")
 		if err != nil {
 			return fmt.Errorf("create tar header: %w", err)
@@ -133,18 +165,58 @@ func CompressDirectory(srcDir, destFile string, opts *CompressOptions) error {
 	})
 }
 
-func shouldIgnore(path string, info os.FileInfo, patterns []string) bool {
-	name := info.Name()
+// ListFiles returns the regular files CompressDirectory would include,
+// as slash-separated paths relative to srcDir.
+func ListFiles(srcDir string, opts *CompressOptions) ([]string, error) {
+	if opts == nil {
+		opts = DefaultCompressOptions()
+	}
+
+	ignorePatterns := append([]string{}, opts.IgnorePatterns...)
+	if userPatterns, err := loadIgnoreFile(filepath.Join(srcDir, ".canopyignore")); err == nil {
+		ignorePatterns = append(ignorePatterns, userPatterns...)
+	}
+
+	var files []string
+	err := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil || relPath == "." {
+			return err
+		}
+
+		if shouldIgnore(relPath, info.Name(), ignorePatterns) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath = filepath.ToSlash(relPath)
+		if info.Mode().IsRegular() && (opts.Include == nil || opts.Include(relPath)) {
+			files = append(files, relPath)
+		}
+		return nil
+	})
 
+	return files, err
+}
+
+func shouldIgnore(path, name string, patterns []string) bool {
 	for _, pattern := range patterns {
-		if strings.HasPrefix(pattern, "*") {
-			ext := strings.TrimPrefix(pattern, "*")
-			if strings.HasSuffix(name, ext) {
+		switch {
+		case strings.HasPrefix(pattern, "*"):
+			if strings.HasSuffix(name, strings.TrimPrefix(pattern, "*")) {
 				return true
 			}
-		} else if name == pattern {
-			return true
-		} else if strings.Contains(path, pattern) {
+		case strings.HasSuffix(pattern, "/"):
+			if strings.Contains("/"+filepath.ToSlash(path)+"/", "/"+strings.Trim(pattern, "/")+"/") {
+				return true
+			}
+		case name == pattern, strings.Contains(path, pattern):
 			return true
 		}
 	}
@@ -159,8 +231,12 @@ func loadIgnoreFile(path string) ([]string, error) {
 	}
 	defer file.Close()
 
+	return ParseIgnorePatterns(file)
+}
+
+func ParseIgnorePatterns(r io.Reader) ([]string, error) {
 	var patterns []string
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if line != "" && !strings.HasPrefix(line, "#") {
@@ -170,3 +246,87 @@ func loadIgnoreFile(path string) ([]string, error) {
 
 	return patterns, scanner.Err()
 }
+
+// CompressTar re-packs an uncompressed tar stream, such as the output of
+// git archive, into a gzipped tarball, applying the same ignore rules and
+// include filter as CompressDirectory. Unlike CompressDirectory it does not
+// read .canopyignore; callers pass its patterns in opts.
+func CompressTar(src io.Reader, destFile string, opts *CompressOptions) error {
+	if opts == nil {
+		opts = DefaultCompressOptions()
+	}
+
+	file, err := os.Create(destFile)
+	if err != nil {
+		return fmt.Errorf("create archive file: %w", err)
+	}
+	defer file.Close()
+
+	gzWriter := gzip.NewWriter(file)
+	defer gzWriter.Close()
+
+	tarWriter := tar.NewWriter(gzWriter)
+	defer tarWriter.Close()
+
+	tarReader := tar.NewReader(src)
+	var ignoredDirs []string
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read tar entry: %w", err)
+		}
+
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+
+		relPath := strings.TrimSuffix(strings.TrimPrefix(header.Name, "./"), "/")
+		if relPath == "" || relPath == "." {
+			continue
+		}
+
+		if underAny(relPath, ignoredDirs) {
+			continue
+		}
+
+		isDir := header.Typeflag == tar.TypeDir
+		if shouldIgnore(relPath, path.Base(relPath), opts.IgnorePatterns) {
+			if isDir {
+				ignoredDirs = append(ignoredDirs, relPath+"/")
+			}
+			continue
+		}
+
+		if opts.Include != nil && (isDir || !opts.Include(relPath)) {
+			continue
+		}
+
+		header.Name = relPath
+		if isDir {
+			header.Name += "/"
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return fmt.Errorf("write tar header: %w", err)
+		}
+
+		if header.Typeflag == tar.TypeReg {
+			if _, err := io.Copy(tarWriter, tarReader); err != nil {
+				return fmt.Errorf("write file to tar: %w", err)
+			}
+		}
+	}
+}
+
+func underAny(relPath string, dirs []string) bool {
+	for _, dir := range dirs {
+		if strings.HasPrefix(relPath, dir) {
+			return true
+		}
+	}
+	return false
+}
@@ -20,8 +20,29 @@ type ArchiveType string
 const (
 	ArchiveTypeZip   ArchiveType = "zip"
 	ArchiveTypeTarGz ArchiveType = "tar.gz"
+	ArchiveTypeIPA   ArchiveType = "ipa"
+	ArchiveTypeAPK   ArchiveType = "apk"
+	ArchiveTypeAAB   ArchiveType = "aab"
 )
 
+// IsBinary reports whether the archive is a built app rather than sources.
+func (t ArchiveType) IsBinary() bool {
+	return t == ArchiveTypeIPA || t == ArchiveTypeAPK || t == ArchiveTypeAAB
+}
+
+// Platform returns the store a binary is built for ("apple" or "google"),
+// or "" for source archives.
+func (t ArchiveType) Platform() string {
+	switch t {
+	case ArchiveTypeIPA:
+		return "apple"
+	case ArchiveTypeAPK, ArchiveTypeAAB:
+		return "google"
+	default:
+		return ""
+	}
+}
+
 func ValidateArchive(path string) (ArchiveType, error) {
 	info, err := os.Stat(path)
 	if err != nil {
@@ -35,11 +56,27 @@ func ValidateArchive(path string) (ArchiveType, error) {
 	ext := strings.ToLower(filepath.Ext(path))
 	name := strings.ToLower(filepath.Base(path))
 
-	if ext == ".zip" {
+	switch ext {
+	case ".zip":
 		if err := validateZip(path); err != nil {
 			return "", fmt.Errorf("invalid zip file: %w", err)
 		}
 		return ArchiveTypeZip, nil
+	case ".ipa":
+		if err := validateIPA(path); err != nil {
+			return "", fmt.Errorf("invalid ipa file: %w", err)
+		}
+		return ArchiveTypeIPA, nil
+	case ".apk":
+		if err := validateAPK(path); err != nil {
+			return "", fmt.Errorf("invalid apk file: %w", err)
+		}
+		return ArchiveTypeAPK, nil
+	case ".aab":
+		if err := validateAAB(path); err != nil {
+			return "", fmt.Errorf("invalid aab file: %w", err)
+		}
+		return ArchiveTypeAAB, nil
 	}
 
 	if ext == ".gz" || strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz") {
@@ -49,7 +86,7 @@ func ValidateArchive(path string) (ArchiveType, error) {
 		return ArchiveTypeTarGz, nil
 	}
 
-	return "", fmt.Errorf("unsupported file type: %s (supported: .zip, .tar.gz, .tgz)", ext)
+	return "", fmt.Errorf("unsupported file type: %s (supported: .zip, .tar.gz, .tgz, .ipa, .apk, .aab)", ext)
 }
 
 func validateZip(path string) error {
@@ -66,6 +103,48 @@ func validateZip(path string) error {
 	return nil
 }
 
+// validateIPA requires an app bundle with an Info.plist under Payload/.
+func validateIPA(path string) error {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for _, f := range reader.File {
+		parts := strings.Split(f.Name, "/")
+		if len(parts) == 3 && parts[0] == "Payload" && strings.HasSuffix(parts[1], ".app") && parts[2] == "Info.plist" {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no Payload/*.app/Info.plist found")
+}
+
+func validateAPK(path string) error {
+	return requireZipEntry(path, "AndroidManifest.xml")
+}
+
+func validateAAB(path string) error {
+	return requireZipEntry(path, "base/manifest/AndroidManifest.xml")
+}
+
+func requireZipEntry(path, name string) error {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for _, f := range reader.File {
+		if f.Name == name {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s not found", name)
+}
+
 func validateTarGz(path string) error {
 	file, err := os.Open(path)
 	if err != nil {
@@ -97,6 +176,9 @@ func IsArchive(path string) bool {
 	name := strings.ToLower(filepath.Base(path))
 
 	return ext == ".zip" ||
+		ext == ".ipa" ||
+		ext == ".apk" ||
+		ext == ".aab" ||
 		ext == ".gz" ||
 		strings.HasSuffix(name, ".tar.gz") ||
 		strings.HasSuffix(name, ".tgz")
@@ -0,0 +1,152 @@
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/fingerprint"
+)
+
+const (
+	DefaultPath   = ".canopy-baseline.json"
+	formatVersion = 1
+)
+
+type File struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	ScanID    string    `json:"scan_id,omitempty"`
+	Findings  []Entry   `json:"findings"`
+}
+
+type Entry struct {
+	Fingerprint string                 `json:"fingerprint"`
+	RuleCode    string                 `json:"rule_code"`
+	RuleName    string                 `json:"rule_name,omitempty"`
+	Severity    string                 `json:"severity"`
+	FilePath    string                 `json:"file_path,omitempty"`
+	Message     string                 `json:"message,omitempty"`
+	Evidence    map[string]interface{} `json:"evidence,omitempty"`
+	// Count is the number of findings sharing the fingerprint; files
+	// written before it was added hold one per entry.
+	Count int `json:"count,omitempty"`
+}
+
+func (e Entry) occurrences() int {
+	if e.Count < 1 {
+		return 1
+	}
+	return e.Count
+}
+
+func Create(result *api.ScanResult) *File {
+	file := &File{
+		Version:   formatVersion,
+		CreatedAt: time.Now().UTC(),
+		ScanID:    result.ID,
+		Findings:  []Entry{},
+	}
+
+	index := make(map[string]int)
+	for _, finding := range result.Findings {
+		fp := fingerprint.Compute(finding)
+		if i, ok := index[fp]; ok {
+			file.Findings[i].Count++
+			continue
+		}
+		index[fp] = len(file.Findings)
+
+		file.Findings = append(file.Findings, Entry{
+			Fingerprint: fp,
+			RuleCode:    finding.RuleCode,
+			RuleName:    finding.RuleName,
+			Severity:    finding.Severity,
+			FilePath:    finding.FilePath,
+			Message:     finding.Message,
+			Evidence:    finding.Evidence,
+			Count:       1,
+		})
+	}
+
+	return file
+}
+
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read baseline file: %w", err)
+	}
+
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse baseline file: %w", err)
+	}
+
+	if file.Version > formatVersion {
+		return nil, fmt.Errorf("baseline file version %d is newer than supported version %d", file.Version, formatVersion)
+	}
+
+	return &file, nil
+}
+
+func Save(file *File, path string) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal baseline: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("write baseline file: %w", err)
+	}
+
+	return nil
+}
+
+// Apply marks every finding in result as new or unchanged relative to the
+// baseline, records baseline entries that no longer occur as fixed, and
+// recomputes the summary so that only new findings count. Findings sharing
+// a fingerprint are matched by count, so an additional occurrence is new.
+func Apply(result *api.ScanResult, file *File, path string) {
+	remaining := make(map[string]int, len(file.Findings))
+	for _, entry := range file.Findings {
+		remaining[entry.Fingerprint] += entry.occurrences()
+	}
+
+	comparison := &api.BaselineResult{Path: path}
+
+	for i := range result.Findings {
+		finding := &result.Findings[i]
+		finding.Fingerprint = fingerprint.Compute(*finding)
+
+		if remaining[finding.Fingerprint] > 0 {
+			remaining[finding.Fingerprint]--
+			finding.BaselineState = api.BaselineUnchanged
+			comparison.Unchanged++
+		} else {
+			finding.BaselineState = api.BaselineNew
+			comparison.New++
+		}
+	}
+
+	for _, entry := range file.Findings {
+		for ; remaining[entry.Fingerprint] > 0; remaining[entry.Fingerprint]-- {
+			comparison.Fixed++
+			comparison.FixedFindings = append(comparison.FixedFindings, api.Finding{
+				RuleCode:      entry.RuleCode,
+				RuleName:      entry.RuleName,
+				Severity:      entry.Severity,
+				Message:       entry.Message,
+				FilePath:      entry.FilePath,
+				Evidence:      entry.Evidence,
+				Fingerprint:   entry.Fingerprint,
+				BaselineState: api.BaselineAbsent,
+			})
+		}
+	}
+
+	result.Baseline = comparison
+	result.RecomputeSummary()
+}
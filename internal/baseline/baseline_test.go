@@ -0,0 +1,155 @@
+package baseline
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+func finding(rule, path, severity string) api.Finding {
+	return api.Finding{RuleCode: rule, FilePath: path, Severity: severity, Message: rule + " in " + path}
+}
+
+func TestApply(t *testing.T) {
+	plist := finding("APL-PLIST-001", "Info.plist", "HIGH")
+	gradle := finding("GPL-SDK-001", "app/build.gradle", "BLOCKER")
+	manifest := finding("GPL-MAN-002", "app/src/main/AndroidManifest.xml", "MEDIUM")
+
+	moved := plist
+	moved.FilePath = "./Info.plist"
+	moved.Evidence = map[string]interface{}{"line": 40}
+
+	tests := []struct {
+		name          string
+		baseline      []api.Finding
+		findings      []api.Finding
+		wantStates    []string
+		wantNew       int
+		wantUnchanged int
+		wantFixed     []string
+		wantSummary   api.ScanSummary
+	}{
+		{
+			name:          "new, unchanged and fixed",
+			baseline:      []api.Finding{plist, gradle},
+			findings:      []api.Finding{plist, manifest},
+			wantStates:    []string{api.BaselineUnchanged, api.BaselineNew},
+			wantNew:       1,
+			wantUnchanged: 1,
+			wantFixed:     []string{"GPL-SDK-001"},
+			wantSummary:   api.ScanSummary{Total: 1, Medium: 1},
+		},
+		{
+			name:          "location changes keep a finding unchanged",
+			baseline:      []api.Finding{plist},
+			findings:      []api.Finding{moved},
+			wantStates:    []string{api.BaselineUnchanged},
+			wantUnchanged: 1,
+		},
+		{
+			name:          "another occurrence is new",
+			baseline:      []api.Finding{gradle},
+			findings:      []api.Finding{gradle, gradle},
+			wantStates:    []string{api.BaselineUnchanged, api.BaselineNew},
+			wantNew:       1,
+			wantUnchanged: 1,
+			wantSummary:   api.ScanSummary{Total: 1, Blocker: 1},
+		},
+		{
+			name:          "fewer occurrences are fixed",
+			baseline:      []api.Finding{gradle, gradle, gradle},
+			findings:      []api.Finding{gradle},
+			wantStates:    []string{api.BaselineUnchanged},
+			wantUnchanged: 1,
+			wantFixed:     []string{"GPL-SDK-001", "GPL-SDK-001"},
+		},
+		{
+			name:        "empty baseline",
+			findings:    []api.Finding{manifest},
+			wantStates:  []string{api.BaselineNew},
+			wantNew:     1,
+			wantSummary: api.ScanSummary{Total: 1, Medium: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := Create(&api.ScanResult{Findings: tt.baseline})
+			result := &api.ScanResult{Findings: append([]api.Finding{}, tt.findings...)}
+
+			Apply(result, file, DefaultPath)
+
+			var states []string
+			for _, f := range result.Findings {
+				states = append(states, f.BaselineState)
+				if f.Fingerprint == "" {
+					t.Errorf("finding %s has no fingerprint", f.RuleCode)
+				}
+			}
+			if !reflect.DeepEqual(states, tt.wantStates) {
+				t.Errorf("states = %v, want %v", states, tt.wantStates)
+			}
+
+			b := result.Baseline
+			if b == nil || b.Path != DefaultPath {
+				t.Fatalf("Baseline = %+v, want path %s", b, DefaultPath)
+			}
+			if b.New != tt.wantNew || b.Unchanged != tt.wantUnchanged || b.Fixed != len(tt.wantFixed) {
+				t.Errorf("new=%d unchanged=%d fixed=%d, want %d, %d, %d",
+					b.New, b.Unchanged, b.Fixed, tt.wantNew, tt.wantUnchanged, len(tt.wantFixed))
+			}
+			var fixed []string
+			for _, f := range b.FixedFindings {
+				fixed = append(fixed, f.RuleCode)
+				if f.BaselineState != api.BaselineAbsent {
+					t.Errorf("fixed finding %s state = %q", f.RuleCode, f.BaselineState)
+				}
+			}
+			if !reflect.DeepEqual(fixed, tt.wantFixed) {
+				t.Errorf("fixed = %v, want %v", fixed, tt.wantFixed)
+			}
+			if result.Summary == nil || *result.Summary != tt.wantSummary {
+				t.Errorf("summary = %+v, want %+v", result.Summary, tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	gradle := finding("GPL-SDK-001", "app/build.gradle", "BLOCKER")
+	file := Create(&api.ScanResult{ID: "scan-1", Findings: []api.Finding{gradle, gradle, finding("APL-PLIST-001", "Info.plist", "HIGH")}})
+
+	if file.Version != formatVersion || file.ScanID != "scan-1" {
+		t.Errorf("version=%d scanID=%q", file.Version, file.ScanID)
+	}
+	if len(file.Findings) != 2 || file.Findings[0].Count != 2 || file.Findings[1].Count != 1 {
+		t.Errorf("findings = %+v, want two entries counted 2 and 1", file.Findings)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultPath)
+	file := Create(&api.ScanResult{Findings: []api.Finding{finding("GPL-SDK-001", "app/build.gradle", "BLOCKER")}})
+
+	if err := Save(file, path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Findings, file.Findings) {
+		t.Errorf("Load() findings = %+v, want %+v", got.Findings, file.Findings)
+	}
+
+	file.Version = formatVersion + 1
+	if err := Save(file, path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "newer than supported") {
+		t.Errorf("Load() error = %v, want version error", err)
+	}
+}
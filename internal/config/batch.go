@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type BatchManifest struct {
+	Concurrency int      `yaml:"concurrency,omitempty"`
+	RateLimit   float64  `yaml:"rate_limit,omitempty"`
+	OutputDir   string   `yaml:"output_dir,omitempty"`
+	Formats     []string `yaml:"formats,omitempty"`
+	Threshold   string   `yaml:"threshold,omitempty"`
+
+	Entries []BatchEntry `yaml:"entries"`
+
+	path string
+}
+
+type BatchEntry struct {
+	Name      string `yaml:"name,omitempty"`
+	Path      string `yaml:"path"`
+	Platform  string `yaml:"platform,omitempty"`
+	Project   string `yaml:"project,omitempty"`
+	Threshold string `yaml:"threshold,omitempty"`
+}
+
+func (e BatchEntry) DisplayName() string {
+	if e.Name != "" {
+		return e.Name
+	}
+	name := filepath.Base(e.Path)
+	for _, ext := range []string{".tar.gz", ".tgz", ".zip", ".ipa", ".apk", ".aab"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name
+}
+
+func (m *BatchManifest) Path() string {
+	return m.path
+}
+
+// ResolvePath returns an entry path relative to the manifest directory.
+func (m *BatchManifest) ResolvePath(path string) string {
+	return resolveRelative(filepath.Dir(m.path), path)
+}
+
+func LoadBatchManifest(path string) (*BatchManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+
+	m := &BatchManifest{path: path}
+	if err := yaml.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("parse manifest %s: %w", path, err)
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+
+	return m, nil
+}
+
+func (m *BatchManifest) Validate() error {
+	if len(m.Entries) == 0 {
+		return fmt.Errorf("entries must not be empty")
+	}
+	if m.Concurrency < 0 {
+		return fmt.Errorf("concurrency must not be negative")
+	}
+	if m.RateLimit < 0 {
+		return fmt.Errorf("rate_limit must not be negative")
+	}
+	if m.Threshold != "" && !isThreshold(m.Threshold) {
+		return fmt.Errorf("unknown threshold %q", m.Threshold)
+	}
+
+	names := make(map[string]bool, len(m.Entries))
+	for i, e := range m.Entries {
+		if e.Path == "" {
+			return fmt.Errorf("entry %d: path is required", i+1)
+		}
+		switch strings.ToLower(e.Platform) {
+		case "", "apple", "ios", "google", "android", "both":
+		default:
+			return fmt.Errorf("entry %d (%s): unknown platform %q", i+1, e.Path, e.Platform)
+		}
+		if e.Threshold != "" && !isThreshold(e.Threshold) {
+			return fmt.Errorf("entry %d (%s): unknown threshold %q", i+1, e.Path, e.Threshold)
+		}
+
+		name := e.DisplayName()
+		if names[name] {
+			return fmt.Errorf("entry %d: duplicate name %q, set name explicitly", i+1, name)
+		}
+		names[name] = true
+	}
+
+	return nil
+}
+
+func isThreshold(s string) bool {
+	return isSeverity(s) && !strings.EqualFold(s, "info")
+}
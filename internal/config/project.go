@@ -0,0 +1,318 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ProjectConfigNames = []string{".canopy.yml", ".canopy.yaml"}
+
+const (
+	ExpiryWarn = "warn"
+	ExpiryFail = "fail"
+)
+
+const (
+	SuppressionAcceptedRisk  = "accepted_risk"
+	SuppressionFalsePositive = "false_positive"
+)
+
+type ProjectConfig struct {
+	SuppressionsFile  string        `yaml:"suppressions_file,omitempty"`
+	SuppressionExpiry string        `yaml:"suppression_expiry,omitempty"`
+	Suppressions      []Suppression `yaml:"suppressions,omitempty"`
+
+	Rules     map[string]RuleConfig `yaml:"rules,omitempty"`
+	OnlyRules []string              `yaml:"only_rules,omitempty"`
+	SkipRules []string              `yaml:"skip_rules,omitempty"`
+
+	Gate *GateConfig `yaml:"gate,omitempty"`
+
+	Apps []AppConfig `yaml:"apps,omitempty"`
+
+	path string
+	root string
+}
+
+type AppConfig struct {
+	Name     string `yaml:"name,omitempty"`
+	Path     string `yaml:"path"`
+	Platform string `yaml:"platform,omitempty"`
+}
+
+type Suppression struct {
+	Rule     string            `yaml:"rule"`
+	Path     string            `yaml:"path,omitempty"`
+	Evidence map[string]string `yaml:"evidence,omitempty"`
+	Kind     string            `yaml:"kind,omitempty"`
+	Reason   string            `yaml:"reason"`
+	Owner    string            `yaml:"owner"`
+	Expires  string            `yaml:"expires"`
+}
+
+type RuleConfig struct {
+	Severity string `yaml:"severity,omitempty"`
+	Enabled  *bool  `yaml:"enabled,omitempty"`
+}
+
+type GateConfig struct {
+	Max          map[string]int `yaml:"max,omitempty"`
+	MaxRiskScore *int           `yaml:"max_risk_score,omitempty"`
+	// MinRiskConfidence only qualifies MaxRiskScore: the risk score fails
+	// the gate when the server's confidence in it is at least this level.
+	// Findings have no confidence and are never filtered by it.
+	MinRiskConfidence string                `yaml:"min_risk_confidence,omitempty"`
+	FailOnCategories  []string              `yaml:"fail_on_categories,omitempty"`
+	Platforms         map[string]GateConfig `yaml:"platforms,omitempty"`
+}
+
+func (s Suppression) ExpiresAt() (time.Time, bool) {
+	if s.Expires == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02", s.Expires)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func (s Suppression) Expired(now time.Time) bool {
+	expires, ok := s.ExpiresAt()
+	if !ok {
+		return false
+	}
+	// The expiry date itself is still valid.
+	return !now.Before(expires.AddDate(0, 0, 1))
+}
+
+func (a AppConfig) DisplayName() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.Path
+}
+
+func (p *ProjectConfig) Path() string {
+	return p.path
+}
+
+// SetRoot records the project directory the config applies to, so that
+// Source can report the config path relative to it.
+func (p *ProjectConfig) SetRoot(root string) {
+	p.root = root
+}
+
+// Source returns the config path relative to the project root with forward
+// slashes. It is what suppressions record as their source, so a result
+// saved on one machine and converted on another still refers to the same
+// file. Without a root, or for a config outside of it, the file name is
+// used.
+func (p *ProjectConfig) Source() string {
+	if p.path == "" {
+		return ""
+	}
+	if p.root != "" {
+		root, rootErr := filepath.Abs(p.root)
+		path, pathErr := filepath.Abs(p.path)
+		if rootErr == nil && pathErr == nil {
+			if rel, err := filepath.Rel(root, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return filepath.ToSlash(rel)
+			}
+		}
+	}
+	return filepath.Base(p.path)
+}
+
+func (p *ProjectConfig) Dir() string {
+	if p.path == "" {
+		return ""
+	}
+	return filepath.Dir(p.path)
+}
+
+func FindProjectConfig(dir string) string {
+	for _, name := range ProjectConfigNames {
+		candidate := filepath.Join(dir, name)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func LoadProject(path string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read project config: %w", err)
+	}
+
+	cfg := &ProjectConfig{path: path}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse project config %s: %w", path, err)
+	}
+
+	if cfg.SuppressionsFile != "" {
+		extra, err := loadSuppressionsFile(resolveRelative(cfg.Dir(), cfg.SuppressionsFile))
+		if err != nil {
+			return nil, err
+		}
+		cfg.Suppressions = append(cfg.Suppressions, extra...)
+	}
+
+	if cfg.Gate != nil {
+		cfg.Gate.normalize()
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid project config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+func (p *ProjectConfig) Validate() error {
+	switch p.SuppressionExpiry {
+	case "", ExpiryWarn, ExpiryFail:
+	default:
+		return fmt.Errorf("suppression_expiry must be %q or %q", ExpiryWarn, ExpiryFail)
+	}
+
+	for pattern, rule := range p.Rules {
+		if rule.Severity != "" && !isSeverity(rule.Severity) {
+			return fmt.Errorf("rules.%s: unknown severity %q", pattern, rule.Severity)
+		}
+	}
+
+	if p.Gate != nil {
+		if err := p.Gate.validate("gate", true); err != nil {
+			return err
+		}
+	}
+
+	names := make(map[string]bool, len(p.Apps))
+	for i, app := range p.Apps {
+		if app.Path == "" {
+			return fmt.Errorf("app %d: path is required", i+1)
+		}
+		switch strings.ToLower(app.Platform) {
+		case "", "apple", "ios", "google", "android", "both":
+		default:
+			return fmt.Errorf("app %d (%s): unknown platform %q", i+1, app.Path, app.Platform)
+		}
+		name := app.DisplayName()
+		if names[name] {
+			return fmt.Errorf("app %d: duplicate app name %q", i+1, name)
+		}
+		names[name] = true
+	}
+
+	for i, s := range p.Suppressions {
+		if s.Rule == "" {
+			return fmt.Errorf("suppression %d: rule is required", i+1)
+		}
+		if s.Reason == "" {
+			return fmt.Errorf("suppression %d (%s): reason is required", i+1, s.Rule)
+		}
+		switch s.Kind {
+		case "", SuppressionAcceptedRisk, SuppressionFalsePositive:
+		default:
+			return fmt.Errorf("suppression %d (%s): kind must be %q or %q", i+1, s.Rule, SuppressionAcceptedRisk, SuppressionFalsePositive)
+		}
+		if s.Owner == "" {
+			return fmt.Errorf("suppression %d (%s): owner is required", i+1, s.Rule)
+		}
+		if _, ok := s.ExpiresAt(); !ok {
+			return fmt.Errorf("suppression %d (%s): expires must be a date (YYYY-MM-DD)", i+1, s.Rule)
+		}
+	}
+
+	return nil
+}
+
+// normalize lowercases severity keys, which the gate engine looks up in
+// lowercase; when a key is repeated in different case the lower limit wins.
+func (g *GateConfig) normalize() {
+	if g.Max != nil {
+		max := make(map[string]int, len(g.Max))
+		for severity, limit := range g.Max {
+			key := strings.ToLower(severity)
+			if cur, ok := max[key]; !ok || limit < cur {
+				max[key] = limit
+			}
+		}
+		g.Max = max
+	}
+	for platform, pg := range g.Platforms {
+		pg.normalize()
+		g.Platforms[platform] = pg
+	}
+}
+
+func (g GateConfig) validate(prefix string, allowPlatforms bool) error {
+	for severity, max := range g.Max {
+		if !isSeverity(severity) && severity != "total" {
+			return fmt.Errorf("%s.max: unknown severity %q", prefix, severity)
+		}
+		if max < 0 {
+			return fmt.Errorf("%s.max.%s: must not be negative", prefix, severity)
+		}
+	}
+
+	switch strings.ToLower(g.MinRiskConfidence) {
+	case "", "low", "medium", "high":
+	default:
+		return fmt.Errorf("%s.min_risk_confidence must be low, medium or high", prefix)
+	}
+
+	if len(g.Platforms) > 0 && !allowPlatforms {
+		return fmt.Errorf("%s: platform gates cannot be nested", prefix)
+	}
+	for platform, pg := range g.Platforms {
+		switch strings.ToLower(platform) {
+		case "apple", "ios", "google", "android":
+		default:
+			return fmt.Errorf("%s.platforms: unknown platform %q", prefix, platform)
+		}
+		if err := pg.validate(prefix+".platforms."+platform, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isSeverity(s string) bool {
+	switch strings.ToLower(s) {
+	case "blocker", "high", "medium", "low", "info":
+		return true
+	}
+	return false
+}
+
+func loadSuppressionsFile(path string) ([]Suppression, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read suppressions file: %w", err)
+	}
+
+	var file struct {
+		Suppressions []Suppression `yaml:"suppressions"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse suppressions file %s: %w", path, err)
+	}
+
+	return file.Suppressions, nil
+}
+
+func resolveRelative(dir, path string) string {
+	if filepath.IsAbs(path) || dir == "" {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestProjectConfigSource(t *testing.T) {
+	root := t.TempDir()
+
+	tests := []struct {
+		name string
+		path string
+		root string
+		want string
+	}{
+		{
+			name: "config at the project root",
+			path: filepath.Join(root, ".canopy.yml"),
+			root: root,
+			want: ".canopy.yml",
+		},
+		{
+			name: "config in a subdirectory",
+			path: filepath.Join(root, "ci", "canopy.yml"),
+			root: root,
+			want: "ci/canopy.yml",
+		},
+		{
+			name: "config outside the project",
+			path: filepath.Join(filepath.Dir(root), "shared.yml"),
+			root: root,
+			want: "shared.yml",
+		},
+		{
+			name: "no root",
+			path: filepath.Join(root, "ci", "canopy.yml"),
+			want: "canopy.yml",
+		},
+		{
+			name: "no path",
+			root: root,
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &ProjectConfig{path: tt.path}
+			cfg.SetRoot(tt.root)
+			if got := cfg.Source(); got != tt.want {
+				t.Errorf("Source() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
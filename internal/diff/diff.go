@@ -0,0 +1,149 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/fingerprint"
+)
+
+type ScanRef struct {
+	ID            string `json:"id"`
+	Platform      string `json:"platform"`
+	PolicyVersion string `json:"policy_version"`
+	RiskScore     *int   `json:"risk_score,omitempty"`
+}
+
+type SeverityChange struct {
+	Finding api.Finding `json:"finding"`
+	From    string      `json:"from"`
+	To      string      `json:"to"`
+}
+
+type Result struct {
+	Base                 ScanRef          `json:"base"`
+	Head                 ScanRef          `json:"head"`
+	Introduced           []api.Finding    `json:"introduced"`
+	Resolved             []api.Finding    `json:"resolved"`
+	Unchanged            []api.Finding    `json:"unchanged"`
+	SeverityChanges      []SeverityChange `json:"severity_changes"`
+	RiskScoreDelta       *int             `json:"risk_score_delta,omitempty"`
+	PolicyVersionChanged bool             `json:"policy_version_changed"`
+}
+
+func Compare(base, head *api.ScanResult) *Result {
+	result := &Result{
+		Base:                 scanRef(base),
+		Head:                 scanRef(head),
+		Introduced:           []api.Finding{},
+		Resolved:             []api.Finding{},
+		Unchanged:            []api.Finding{},
+		SeverityChanges:      []SeverityChange{},
+		PolicyVersionChanged: base.PolicyVersion != head.PolicyVersion,
+	}
+
+	if result.Base.RiskScore != nil && result.Head.RiskScore != nil {
+		delta := *result.Head.RiskScore - *result.Base.RiskScore
+		result.RiskScoreDelta = &delta
+	}
+
+	baseIndex := indexFindings(base.Findings)
+	headIndex := indexFindings(head.Findings)
+
+	for _, fp := range sortedKeys(headIndex) {
+		finding := headIndex[fp]
+		previous, existed := baseIndex[fp]
+		if !existed {
+			result.Introduced = append(result.Introduced, finding)
+			continue
+		}
+
+		result.Unchanged = append(result.Unchanged, finding)
+		if api.SeverityRank(previous.Severity) != api.SeverityRank(finding.Severity) {
+			result.SeverityChanges = append(result.SeverityChanges, SeverityChange{
+				Finding: finding,
+				From:    previous.Severity,
+				To:      finding.Severity,
+			})
+		}
+	}
+
+	for _, fp := range sortedKeys(baseIndex) {
+		if _, exists := headIndex[fp]; !exists {
+			result.Resolved = append(result.Resolved, baseIndex[fp])
+		}
+	}
+
+	return result
+}
+
+// HasRegressions reports whether the head scan introduced findings or
+// raised the severity of existing ones.
+func (r *Result) HasRegressions() bool {
+	if len(r.Introduced) > 0 {
+		return true
+	}
+	for _, change := range r.SeverityChanges {
+		if change.Escalated() {
+			return true
+		}
+	}
+	return false
+}
+
+func (c SeverityChange) Escalated() bool {
+	return api.SeverityRank(c.To) > api.SeverityRank(c.From)
+}
+
+func scanRef(result *api.ScanResult) ScanRef {
+	ref := ScanRef{
+		ID:            result.ID,
+		Platform:      result.Platform,
+		PolicyVersion: result.PolicyVersion,
+	}
+	if result.RiskAssessment != nil {
+		score := result.RiskAssessment.Score
+		ref.RiskScore = &score
+	}
+	return ref
+}
+
+// indexFindings keys findings by fingerprint and occurrence, so that a
+// second finding with the same fingerprint is introduced or resolved on its
+// own instead of collapsing into the first.
+func indexFindings(findings []api.Finding) map[string]api.Finding {
+	index := make(map[string]api.Finding, len(findings))
+	occurrences := make(map[string]int)
+	for _, finding := range findings {
+		fp := finding.Fingerprint
+		if fp == "" {
+			fp = fingerprint.Compute(finding)
+			finding.Fingerprint = fp
+		}
+		index[fmt.Sprintf("%s#%d", fp, occurrences[fp])] = finding
+		occurrences[fp]++
+	}
+	return index
+}
+
+func sortedKeys(index map[string]api.Finding) []string {
+	keys := make([]string, 0, len(index))
+	for k := range index {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := index[keys[i]], index[keys[j]]
+		if ra, rb := api.SeverityRank(a.Severity), api.SeverityRank(b.Severity); ra != rb {
+			return ra > rb
+		}
+		if a.RuleCode != b.RuleCode {
+			return a.RuleCode < b.RuleCode
+		}
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
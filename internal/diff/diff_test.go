@@ -0,0 +1,134 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+func finding(rule, path, severity string) api.Finding {
+	return api.Finding{RuleCode: rule, FilePath: path, Severity: severity}
+}
+
+func codes(findings []api.Finding) []string {
+	var out []string
+	for _, f := range findings {
+		out = append(out, f.RuleCode)
+	}
+	return out
+}
+
+func TestCompare(t *testing.T) {
+	plist := finding("APL-PLIST-001", "Info.plist", "HIGH")
+	gradle := finding("GPL-SDK-001", "app/build.gradle", "BLOCKER")
+	manifest := finding("GPL-MAN-002", "app/src/main/AndroidManifest.xml", "MEDIUM")
+
+	lowered := gradle
+	lowered.Severity = "MEDIUM"
+	moved := plist
+	moved.FilePath = `.\Info.plist`
+	moved.Evidence = map[string]interface{}{"line": 9}
+
+	tests := []struct {
+		name           string
+		base           []api.Finding
+		head           []api.Finding
+		wantIntroduced []string
+		wantResolved   []string
+		wantUnchanged  []string
+		wantChanges    []SeverityChange
+		wantRegression bool
+	}{
+		{
+			name:           "introduced and resolved",
+			base:           []api.Finding{plist, gradle},
+			head:           []api.Finding{plist, manifest},
+			wantIntroduced: []string{"GPL-MAN-002"},
+			wantResolved:   []string{"GPL-SDK-001"},
+			wantUnchanged:  []string{"APL-PLIST-001"},
+			wantRegression: true,
+		},
+		{
+			name:          "moved finding is unchanged",
+			base:          []api.Finding{plist},
+			head:          []api.Finding{moved},
+			wantUnchanged: []string{"APL-PLIST-001"},
+		},
+		{
+			name:           "second occurrence is introduced",
+			base:           []api.Finding{gradle},
+			head:           []api.Finding{gradle, gradle},
+			wantIntroduced: []string{"GPL-SDK-001"},
+			wantUnchanged:  []string{"GPL-SDK-001"},
+			wantRegression: true,
+		},
+		{
+			name:          "lowered severity is not a regression",
+			base:          []api.Finding{gradle},
+			head:          []api.Finding{lowered},
+			wantUnchanged: []string{"GPL-SDK-001"},
+			wantChanges:   []SeverityChange{{Finding: lowered, From: "BLOCKER", To: "MEDIUM"}},
+		},
+		{
+			name:           "raised severity is a regression",
+			base:           []api.Finding{lowered},
+			head:           []api.Finding{gradle},
+			wantUnchanged:  []string{"GPL-SDK-001"},
+			wantChanges:    []SeverityChange{{Finding: gradle, From: "MEDIUM", To: "BLOCKER"}},
+			wantRegression: true,
+		},
+		{
+			name:           "sorted by severity",
+			head:           []api.Finding{manifest, plist, gradle},
+			wantIntroduced: []string{"GPL-SDK-001", "APL-PLIST-001", "GPL-MAN-002"},
+			wantRegression: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(&api.ScanResult{Findings: tt.base}, &api.ScanResult{Findings: tt.head})
+
+			if c := codes(got.Introduced); !reflect.DeepEqual(c, tt.wantIntroduced) {
+				t.Errorf("introduced = %v, want %v", c, tt.wantIntroduced)
+			}
+			if c := codes(got.Resolved); !reflect.DeepEqual(c, tt.wantResolved) {
+				t.Errorf("resolved = %v, want %v", c, tt.wantResolved)
+			}
+			if c := codes(got.Unchanged); !reflect.DeepEqual(c, tt.wantUnchanged) {
+				t.Errorf("unchanged = %v, want %v", c, tt.wantUnchanged)
+			}
+			if len(got.SeverityChanges) != len(tt.wantChanges) {
+				t.Fatalf("severity changes = %+v, want %+v", got.SeverityChanges, tt.wantChanges)
+			}
+			for i, c := range got.SeverityChanges {
+				want := tt.wantChanges[i]
+				if c.From != want.From || c.To != want.To || c.Finding.RuleCode != want.Finding.RuleCode {
+					t.Errorf("severity change = %+v, want %+v", c, want)
+				}
+			}
+			if r := got.HasRegressions(); r != tt.wantRegression {
+				t.Errorf("HasRegressions() = %v, want %v", r, tt.wantRegression)
+			}
+		})
+	}
+}
+
+func TestCompareScanRefs(t *testing.T) {
+	base := &api.ScanResult{ID: "a", PolicyVersion: "2025.1", RiskAssessment: &api.RiskAssessment{Score: 40}}
+	head := &api.ScanResult{ID: "b", PolicyVersion: "2025.2", RiskAssessment: &api.RiskAssessment{Score: 55}}
+
+	got := Compare(base, head)
+	if got.Base.ID != "a" || got.Head.ID != "b" || !got.PolicyVersionChanged {
+		t.Errorf("refs = %+v / %+v, policy changed = %v", got.Base, got.Head, got.PolicyVersionChanged)
+	}
+	if got.RiskScoreDelta == nil || *got.RiskScoreDelta != 15 {
+		t.Errorf("RiskScoreDelta = %v, want 15", got.RiskScoreDelta)
+	}
+
+	head.RiskAssessment = nil
+	if got := Compare(base, head); got.RiskScoreDelta != nil {
+		t.Errorf("RiskScoreDelta = %d, want nil without a head score", *got.RiskScoreDelta)
+	}
+}
@@ -0,0 +1,163 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+var Formats = []string{"text", "json", "markdown"}
+
+func Format(r *Result, format string, noColor bool) ([]byte, error) {
+	switch format {
+	case "json":
+		return json.MarshalIndent(r, "", "  ")
+	case "markdown", "md":
+		return []byte(formatMarkdown(r)), nil
+	case "text":
+		return []byte(formatText(r, noColor)), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+func formatText(r *Result, noColor bool) string {
+	paint := func(code, s string) string {
+		if noColor {
+			return s
+		}
+		return code + s + "\033[0m"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("Canopy Scan Diff\n")
+	sb.WriteString("================\n\n")
+	sb.WriteString(fmt.Sprintf("Base: %s (policy %s)\n", r.Base.ID, r.Base.PolicyVersion))
+	sb.WriteString(fmt.Sprintf("Head: %s (policy %s)\n", r.Head.ID, r.Head.PolicyVersion))
+	if r.PolicyVersionChanged {
+		sb.WriteString(paint("\033[33m", fmt.Sprintf("Policy version changed: %s -> %s", r.Base.PolicyVersion, r.Head.PolicyVersion)) + "\n")
+	}
+	if r.RiskScoreDelta != nil {
+		sb.WriteString(fmt.Sprintf("Risk Score: %d -> %d (%s)\n", *r.Base.RiskScore, *r.Head.RiskScore, signed(*r.RiskScoreDelta)))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(fmt.Sprintf("Introduced: %d\n", len(r.Introduced)))
+	sb.WriteString(fmt.Sprintf("Resolved: %d\n", len(r.Resolved)))
+	sb.WriteString(fmt.Sprintf("Unchanged: %d\n", len(r.Unchanged)))
+	sb.WriteString(fmt.Sprintf("Severity Changes: %d\n", len(r.SeverityChanges)))
+	sb.WriteString("\n")
+
+	if len(r.Introduced) > 0 {
+		sb.WriteString("Introduced\n")
+		sb.WriteString("----------\n")
+		for _, f := range r.Introduced {
+			sb.WriteString(paint("\033[31m", "+ "+findingLine(f)) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(r.Resolved) > 0 {
+		sb.WriteString("Resolved\n")
+		sb.WriteString("--------\n")
+		for _, f := range r.Resolved {
+			sb.WriteString(paint("\033[32m", "- "+findingLine(f)) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(r.SeverityChanges) > 0 {
+		sb.WriteString("Severity Changes\n")
+		sb.WriteString("----------------\n")
+		for _, c := range r.SeverityChanges {
+			line := fmt.Sprintf("~ %s -> %s: %s", strings.ToUpper(c.From), strings.ToUpper(c.To), findingRef(c.Finding))
+			if c.Escalated() {
+				line = paint("\033[31m", line)
+			}
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if r.HasRegressions() {
+		sb.WriteString(paint("\033[31m", "Regressions found.") + "\n")
+	} else {
+		sb.WriteString(paint("\033[32m", "No regressions.") + "\n")
+	}
+
+	return sb.String()
+}
+
+func formatMarkdown(r *Result) string {
+	var sb strings.Builder
+
+	sb.WriteString("## Canopy Scan Diff\n\n")
+	sb.WriteString("| | Base | Head |\n")
+	sb.WriteString("|---|---|---|\n")
+	sb.WriteString(fmt.Sprintf("| Scan | `%s` | `%s` |\n", r.Base.ID, r.Head.ID))
+	sb.WriteString(fmt.Sprintf("| Policy version | %s | %s |\n", r.Base.PolicyVersion, r.Head.PolicyVersion))
+	if r.RiskScoreDelta != nil {
+		sb.WriteString(fmt.Sprintf("| Risk score | %d | %d (%s) |\n", *r.Base.RiskScore, *r.Head.RiskScore, signed(*r.RiskScoreDelta)))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(fmt.Sprintf("**%d introduced**, %d resolved, %d unchanged, %d severity changes\n\n",
+		len(r.Introduced), len(r.Resolved), len(r.Unchanged), len(r.SeverityChanges)))
+
+	writeTable := func(title string, findings []api.Finding) {
+		if len(findings) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("### %s\n\n", title))
+		sb.WriteString("| Severity | Rule | File | Message |\n")
+		sb.WriteString("|---|---|---|---|\n")
+		for _, f := range findings {
+			sb.WriteString(fmt.Sprintf("| %s | `%s` %s | %s | %s |\n",
+				strings.ToUpper(f.Severity), f.RuleCode, mdEscape(f.RuleName), mdEscape(f.FilePath), mdEscape(f.Message)))
+		}
+		sb.WriteString("\n")
+	}
+
+	writeTable("Introduced", r.Introduced)
+	writeTable("Resolved", r.Resolved)
+
+	if len(r.SeverityChanges) > 0 {
+		sb.WriteString("### Severity Changes\n\n")
+		sb.WriteString("| From | To | Rule | File |\n")
+		sb.WriteString("|---|---|---|---|\n")
+		for _, c := range r.SeverityChanges {
+			sb.WriteString(fmt.Sprintf("| %s | %s | `%s` %s | %s |\n",
+				strings.ToUpper(c.From), strings.ToUpper(c.To), c.Finding.RuleCode, mdEscape(c.Finding.RuleName), mdEscape(c.Finding.FilePath)))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func findingLine(f api.Finding) string {
+	return fmt.Sprintf("%s: %s", strings.ToUpper(f.Severity), findingRef(f))
+}
+
+func findingRef(f api.Finding) string {
+	s := fmt.Sprintf("%s - %s", f.RuleCode, f.RuleName)
+	if f.FilePath != "" {
+		s += fmt.Sprintf(" (%s)", f.FilePath)
+	}
+	return s
+}
+
+func signed(n int) string {
+	if n > 0 {
+		return fmt.Sprintf("+%d", n)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func mdEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
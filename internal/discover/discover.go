@@ -0,0 +1,234 @@
+package discover
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	KindIOS         = "ios"
+	KindAndroid     = "android"
+	KindFlutter     = "flutter"
+	KindReactNative = "react-native"
+)
+
+type App struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Kind     string `json:"kind,omitempty"`
+	Platform string `json:"platform"`
+}
+
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"Pods":         true,
+	"Carthage":     true,
+	"DerivedData":  true,
+	"build":        true,
+	"vendor":       true,
+}
+
+// Discover walks root looking for app roots: Flutter and React Native
+// projects, directories containing an Xcode project or workspace, and
+// Gradle modules applying the Android application plugin. Cross-platform
+// projects are detected first so their ios/ and android/ folders are not
+// reported as separate apps. An Android app is rooted at the Gradle build
+// containing its module (the directory with settings.gradle), so the
+// module's siblings and build settings are scanned with it. Paths are
+// relative to root and slash-separated.
+func Discover(root string) ([]App, error) {
+	var apps []App
+	seen := make(map[string]bool)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		name := d.Name()
+		if path != root && (strings.HasPrefix(name, ".") || skipDirs[name] || isXcodeBundle(name)) {
+			return filepath.SkipDir
+		}
+
+		kind := detect(path)
+		if kind == "" {
+			return nil
+		}
+
+		appDir := path
+		if kind == KindAndroid {
+			appDir = gradleRoot(root, path)
+		}
+		rel, err := filepath.Rel(root, appDir)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if seen[rel] {
+			return filepath.SkipDir
+		}
+		seen[rel] = true
+
+		apps = append(apps, App{
+			Name:     appName(root, rel),
+			Path:     rel,
+			Kind:     kind,
+			Platform: platformFor(appDir, kind),
+		})
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Path < apps[j].Path
+	})
+
+	return apps, nil
+}
+
+// DetectApp reports the app rooted at dir itself, without walking
+// subdirectories. The app's Path is ".".
+func DetectApp(dir string) (App, bool) {
+	kind := detect(dir)
+	if kind == "" {
+		return App{}, false
+	}
+	return App{
+		Name:     appName(dir, "."),
+		Path:     ".",
+		Kind:     kind,
+		Platform: platformFor(dir, kind),
+	}, true
+}
+
+func detect(dir string) string {
+	if isFlutter(dir) {
+		return KindFlutter
+	}
+	if isReactNative(dir) {
+		return KindReactNative
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, e := range entries {
+		if e.IsDir() && isXcodeBundle(e.Name()) && !strings.HasPrefix(e.Name(), "Pods") {
+			return KindIOS
+		}
+	}
+
+	if isAndroidApp(dir) {
+		return KindAndroid
+	}
+	return ""
+}
+
+// gradleRoot returns the closest directory from module up to root that has
+// a Gradle settings file, or module itself.
+func gradleRoot(root, module string) string {
+	root = filepath.Clean(root)
+	for dir := module; ; dir = filepath.Dir(dir) {
+		for _, name := range []string{"settings.gradle", "settings.gradle.kts"} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				return dir
+			}
+		}
+		if dir == root || dir == filepath.Dir(dir) {
+			return module
+		}
+	}
+}
+
+func isXcodeBundle(name string) bool {
+	return strings.HasSuffix(name, ".xcodeproj") || strings.HasSuffix(name, ".xcworkspace")
+}
+
+func isFlutter(dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, "pubspec.yaml"))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(data), "sdk: flutter")
+}
+
+func isReactNative(dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return false
+	}
+
+	// Libraries list react-native as a peer or dev dependency; only apps
+	// depend on it directly.
+	var pkg struct {
+		Dependencies map[string]string `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return false
+	}
+
+	_, ok := pkg.Dependencies["react-native"]
+	return ok
+}
+
+func isAndroidApp(dir string) bool {
+	for _, name := range []string{"build.gradle", "build.gradle.kts"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		content := string(data)
+		if strings.Contains(content, "com.android.application") || strings.Contains(content, "plugins.android.application") {
+			return true
+		}
+	}
+	return false
+}
+
+// platformFor returns the scan platform for an app. Cross-platform projects
+// are only scanned for the platforms they have native folders for.
+func platformFor(dir, kind string) string {
+	switch kind {
+	case KindIOS:
+		return "apple"
+	case KindAndroid:
+		return "google"
+	}
+
+	ios := isDir(filepath.Join(dir, "ios"))
+	android := isDir(filepath.Join(dir, "android"))
+	switch {
+	case ios && !android:
+		return "apple"
+	case android && !ios:
+		return "google"
+	default:
+		return "both"
+	}
+}
+
+func appName(root, rel string) string {
+	if rel == "." {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return rel
+		}
+		return filepath.Base(abs)
+	}
+	return rel
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
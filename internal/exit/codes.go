@@ -36,32 +36,16 @@ func ParseThreshold(s string) Threshold {
 	}
 }
 
-type ScanSummary struct {
-	Blocker int
-	High    int
-	Medium  int
-	Low     int
-}
-
-func DetermineExitCode(summary ScanSummary, threshold Threshold) int {
-	switch threshold {
-	case ThresholdBlocker:
-		if summary.Blocker > 0 {
-			return IssuesFound
-		}
-	case ThresholdHigh:
-		if summary.Blocker > 0 || summary.High > 0 {
-			return IssuesFound
-		}
-	case ThresholdMedium:
-		if summary.Blocker > 0 || summary.High > 0 || summary.Medium > 0 {
-			return IssuesFound
-		}
+// Severities returns the severities that fail the build at the threshold.
+func (t Threshold) Severities() []string {
+	switch t {
 	case ThresholdLow:
-		if summary.Blocker > 0 || summary.High > 0 || summary.Medium > 0 || summary.Low > 0 {
-			return IssuesFound
-		}
+		return []string{"blocker", "high", "medium", "low"}
+	case ThresholdMedium:
+		return []string{"blocker", "high", "medium"}
+	case ThresholdHigh:
+		return []string{"blocker", "high"}
+	default:
+		return []string{"blocker"}
 	}
-
-	return Success
 }
@@ -0,0 +1,93 @@
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+const Version = "canopy/v1"
+
+// Location keys move whenever unrelated code is edited, so they are left
+// out of the fingerprint to keep it stable across commits.
+var locationKeys = map[string]bool{
+	"line":         true,
+	"line_number":  true,
+	"start_line":   true,
+	"end_line":     true,
+	"column":       true,
+	"start_column": true,
+	"end_column":   true,
+	"offset":       true,
+}
+
+// Attribution keys name the dependency or plugin a finding comes from.
+// They are added after the scan and depend on which lockfiles were found,
+// so they are left out as well.
+var attributionKeys = map[string]bool{
+	"sdk":            true,
+	"sdk_version":    true,
+	"plugin":         true,
+	"plugin_version": true,
+	"framework":      true,
+}
+
+// IsLocationKey reports whether an evidence key holds a line, column or
+// offset.
+func IsLocationKey(key string) bool {
+	return locationKeys[strings.ToLower(key)]
+}
+
+// IsAttributionKey reports whether an evidence key names the SDK or
+// plugin a finding is attributed to.
+func IsAttributionKey(key string) bool {
+	return attributionKeys[strings.ToLower(key)]
+}
+
+func Compute(finding api.Finding) string {
+	h := sha256.New()
+	h.Write([]byte(strings.ToUpper(finding.RuleCode)))
+	h.Write([]byte{0})
+	h.Write([]byte(NormalizePath(finding.FilePath)))
+	h.Write([]byte{0})
+	h.Write(canonicalEvidence(finding.Evidence))
+
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+func NormalizePath(p string) string {
+	if p == "" {
+		return ""
+	}
+	p = strings.ReplaceAll(p, "\\", "/")
+	p = path.Clean(p)
+	return strings.TrimPrefix(p, "./")
+}
+
+func canonicalEvidence(evidence map[string]interface{}) []byte {
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	stable := make(map[string]interface{}, len(evidence))
+	for k, v := range evidence {
+		if IsLocationKey(k) || IsAttributionKey(k) {
+			continue
+		}
+		stable[k] = v
+	}
+	if len(stable) == 0 {
+		return nil
+	}
+
+	// encoding/json sorts map keys, which makes the encoding canonical.
+	data, err := json.Marshal(stable)
+	if err != nil {
+		return nil
+	}
+	return data
+}
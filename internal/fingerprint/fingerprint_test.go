@@ -0,0 +1,142 @@
+package fingerprint
+
+import (
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+func TestCompute(t *testing.T) {
+	base := api.Finding{
+		RuleCode: "APL-PRIV-001",
+		FilePath: "ios/Runner/PrivacyInfo.xcprivacy",
+		Evidence: map[string]interface{}{"api_type": "NSPrivacyAccessedAPICategoryUserDefaults"},
+	}
+
+	tests := []struct {
+		name     string
+		edit     func(f *api.Finding)
+		wantSame bool
+	}{
+		{
+			name:     "identical finding",
+			edit:     func(f *api.Finding) {},
+			wantSame: true,
+		},
+		{
+			name: "location keys",
+			edit: func(f *api.Finding) {
+				f.Evidence["line"] = 12
+				f.Evidence["Start_Line"] = 12
+				f.Evidence["end_column"] = 4
+				f.Evidence["offset"] = 300
+			},
+			wantSame: true,
+		},
+		{
+			name: "attribution keys",
+			edit: func(f *api.Finding) {
+				f.Evidence["sdk"] = "firebase_core"
+				f.Evidence["sdk_version"] = "2.24.0"
+				f.Evidence["plugin"] = "shared_preferences"
+				f.Evidence["plugin_version"] = "2.2.2"
+				f.Evidence["framework"] = "flutter"
+			},
+			wantSame: true,
+		},
+		{
+			name:     "windows path separators",
+			edit:     func(f *api.Finding) { f.FilePath = `ios\Runner\PrivacyInfo.xcprivacy` },
+			wantSame: true,
+		},
+		{
+			name:     "dot slash prefix",
+			edit:     func(f *api.Finding) { f.FilePath = "./ios/Runner/PrivacyInfo.xcprivacy" },
+			wantSame: true,
+		},
+		{
+			name:     "redundant path elements",
+			edit:     func(f *api.Finding) { f.FilePath = "ios//Runner/../Runner/PrivacyInfo.xcprivacy" },
+			wantSame: true,
+		},
+		{
+			name:     "rule code case",
+			edit:     func(f *api.Finding) { f.RuleCode = "apl-priv-001" },
+			wantSame: true,
+		},
+		{
+			name: "message and severity",
+			edit: func(f *api.Finding) {
+				f.Message = "reworded"
+				f.Severity = "LOW"
+				f.ID = "server-id"
+			},
+			wantSame: true,
+		},
+		{
+			name: "rule code",
+			edit: func(f *api.Finding) { f.RuleCode = "APL-PRIV-002" },
+		},
+		{
+			name: "file path",
+			edit: func(f *api.Finding) { f.FilePath = "ios/Other/PrivacyInfo.xcprivacy" },
+		},
+		{
+			name: "evidence value",
+			edit: func(f *api.Finding) { f.Evidence["api_type"] = "NSPrivacyAccessedAPICategoryDiskSpace" },
+		},
+		{
+			name: "additional evidence key",
+			edit: func(f *api.Finding) { f.Evidence["reason"] = "CA92.1" },
+		},
+	}
+
+	want := Compute(base)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := base
+			f.Evidence = make(map[string]interface{}, len(base.Evidence))
+			for k, v := range base.Evidence {
+				f.Evidence[k] = v
+			}
+			tt.edit(&f)
+
+			got := Compute(f)
+			if len(got) != 32 {
+				t.Errorf("Compute() = %q, want 32 hex characters", got)
+			}
+			if same := got == want; same != tt.wantSame {
+				t.Errorf("Compute() = %s, base %s, want same = %v", got, want, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestComputeWithoutEvidence(t *testing.T) {
+	bare := api.Finding{RuleCode: "APL-PLIST-001", FilePath: "Info.plist"}
+	located := bare
+	located.Evidence = map[string]interface{}{"line": 12, "plugin": "camera"}
+
+	if Compute(bare) != Compute(located) {
+		t.Errorf("Compute() differs when evidence holds only location and attribution keys")
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"", ""},
+		{"app/src/main/AndroidManifest.xml", "app/src/main/AndroidManifest.xml"},
+		{`app\src\main\AndroidManifest.xml`, "app/src/main/AndroidManifest.xml"},
+		{"./Info.plist", "Info.plist"},
+		{"a/./b/../c", "a/c"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizePath(tt.path); got != tt.want {
+			t.Errorf("NormalizePath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
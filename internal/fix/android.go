@@ -0,0 +1,152 @@
+package fix
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+var (
+	attributeRe      = regexp.MustCompile(`^[\w:]+="[^"]*"$`)
+	gradleSettingRe  = regexp.MustCompile(`^(targetSdk|minSdk|compileSdk)\s*=\s*(\d+)$`)
+	androidNameRe    = regexp.MustCompile(`android:name="([^"]+)"`)
+	indentRe         = regexp.MustCompile(`(?m)^([ \t]*)<(?:uses-permission|application)\b`)
+	manifestAnchorRe = regexp.MustCompile(`(?m)^[ \t]*<(?:application|/manifest)\b`)
+	permissionLineRe = regexp.MustCompile(`(?m)^[ \t]*<uses-permission\b[^>]*>[^\n]*\n`)
+)
+
+// setManifestPermission adds a <uses-permission> element, or replaces the
+// existing one for the same permission when the template changes its
+// attributes (e.g. android:maxSdkVersion).
+func setManifestPermission(f api.Finding, content []byte) ([]byte, string, error) {
+	template := strings.TrimSpace(f.Remediation.Template)
+	m := androidNameRe.FindStringSubmatch(template)
+	if m == nil {
+		return nil, "", ErrUnsupported
+	}
+	name := m[1]
+
+	existingRe := regexp.MustCompile(`<uses-permission(?:-sdk-23)?\b[^>]*android:name="` + regexp.QuoteMeta(name) + `"[^>]*?(?:/>|>\s*</uses-permission(?:-sdk-23)?>)`)
+	if loc := existingRe.FindIndex(content); loc != nil {
+		if normalizeTag(string(content[loc[0]:loc[1]])) == normalizeTag(template) {
+			return content, "", nil
+		}
+		return splice(content, loc[0], loc[1], []byte(template)), fmt.Sprintf("Update %s in %s", name, f.FilePath), nil
+	}
+
+	indent := "    "
+	if im := indentRe.FindSubmatch(content); im != nil && len(im[1]) > 0 {
+		indent = string(im[1])
+	}
+	desc := fmt.Sprintf("Declare %s in %s", name, f.FilePath)
+
+	// Keep permissions together: insert after the last one when present.
+	if all := permissionLineRe.FindAllIndex(content, -1); len(all) > 0 {
+		at := all[len(all)-1][1]
+		return splice(content, at, at, []byte(indent+template+"\n")), desc, nil
+	}
+
+	loc := manifestAnchorRe.FindIndex(content)
+	if loc == nil {
+		return nil, "", fmt.Errorf("%s has no <application> or </manifest> element", f.FilePath)
+	}
+	insert := indent + template + "\n"
+	if strings.HasPrefix(strings.TrimSpace(string(content[loc[0]:loc[1]])), "<application") {
+		insert += "\n"
+	}
+	return splice(content, loc[0], loc[0], []byte(insert)), desc, nil
+}
+
+// setComponentAttribute adds an attribute such as android:exported to the
+// component named in the finding's evidence.
+func setComponentAttribute(f api.Finding, content []byte) ([]byte, string, error) {
+	component, _ := f.Evidence["component"].(string)
+	kind, _ := f.Evidence["kind"].(string)
+	if component == "" || kind == "" {
+		return nil, "", ErrUnsupported
+	}
+	attr := strings.TrimSpace(f.Remediation.Template)
+	attrName := attr[:strings.Index(attr, "=")]
+
+	tagRe := regexp.MustCompile(`<` + regexp.QuoteMeta(kind) + `\b[^>]*?android:name="` + regexp.QuoteMeta(component) + `"[^>]*>`)
+	loc := tagRe.FindIndex(content)
+	if loc == nil {
+		return nil, "", fmt.Errorf("<%s android:name=%q> not found in %s", kind, component, f.FilePath)
+	}
+	tag := string(content[loc[0]:loc[1]])
+	if strings.Contains(tag, attrName+"=") {
+		return content, "", nil
+	}
+	// Exporting makes the component reachable from any app; only the
+	// launcher entry point needs that.
+	if attr == `android:exported="true"` && !isLauncher(componentElement(content, loc, kind)) {
+		attr = `android:exported="false"`
+	}
+
+	nameAttr := `android:name="` + component + `"`
+	at := loc[0] + strings.Index(tag, nameAttr) + len(nameAttr)
+
+	insert := " " + attr
+	if strings.Contains(tag, "\n") {
+		lineStart := strings.LastIndex(string(content[:at]), "\n") + 1
+		line := string(content[lineStart:at])
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		insert = "\n" + indent + attr
+	}
+
+	return splice(content, at, at, []byte(insert)), fmt.Sprintf("Set %s on %s in %s", attr, component, f.FilePath), nil
+}
+
+// setGradleSdk raises an SDK level that is a literal in the Gradle script.
+func setGradleSdk(f api.Finding, content []byte) ([]byte, string, error) {
+	m := gradleSettingRe.FindStringSubmatch(strings.TrimSpace(f.Remediation.Template))
+	setting, level := m[1], m[2]
+
+	re := regexp.MustCompile(`(?m)^([ \t]*` + setting + `(?:Version)?(?:\s*=\s*|\s+|\(\s*))([^\s)]+)`)
+	loc := re.FindSubmatchIndex(content)
+	if loc == nil {
+		return nil, "", fmt.Errorf("%s is not set in %s", setting, f.FilePath)
+	}
+
+	current := string(content[loc[4]:loc[5]])
+	literal := strings.Trim(current, `"'`)
+	n, err := strconv.Atoi(literal)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s in %s is %s; update it where that value is defined", setting, f.FilePath, current)
+	}
+	want, _ := strconv.Atoi(level)
+	if n >= want {
+		return content, "", nil
+	}
+
+	raised := strings.Replace(current, literal, level, 1)
+	return splice(content, loc[4], loc[5], []byte(raised)), fmt.Sprintf("Raise %s from %d to %d in %s", setting, n, want, f.FilePath), nil
+}
+
+// componentElement returns a component from its start tag through its end
+// tag, or just the start tag when the element is empty.
+func componentElement(content []byte, loc []int, kind string) string {
+	tag := string(content[loc[0]:loc[1]])
+	if strings.HasSuffix(tag, "/>") {
+		return tag
+	}
+	end := strings.Index(string(content[loc[1]:]), "</"+kind+">")
+	if end < 0 {
+		return tag
+	}
+	return string(content[loc[0] : loc[1]+end])
+}
+
+func isLauncher(element string) bool {
+	return strings.Contains(element, `"android.intent.action.MAIN"`) &&
+		strings.Contains(element, `"android.intent.category.LAUNCHER"`)
+}
+
+func normalizeTag(tag string) string {
+	tag = strings.Join(strings.Fields(tag), " ")
+	tag = strings.Replace(tag, " />", "/>", 1)
+	return tag
+}
@@ -0,0 +1,182 @@
+package fix
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+func componentFinding(kind, component, template string) api.Finding {
+	return api.Finding{
+		FilePath:    "app/src/main/AndroidManifest.xml",
+		Evidence:    map[string]interface{}{"component": component, "kind": kind},
+		Remediation: &api.Remediation{Template: template},
+	}
+}
+
+func TestSetComponentAttribute(t *testing.T) {
+	const launcher = `<intent-filter>
+                <action android:name="android.intent.action.MAIN" />
+                <category android:name="android.intent.category.LAUNCHER" />
+            </intent-filter>`
+	const view = `<intent-filter>
+                <action android:name="android.intent.action.VIEW" />
+            </intent-filter>`
+
+	tests := []struct {
+		name    string
+		finding api.Finding
+		content string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "single line tag",
+			finding: componentFinding("service", ".SyncService", `android:exported="false"`),
+			content: `<service android:name=".SyncService" android:enabled="true">` + view + `</service>`,
+			want:    `<service android:name=".SyncService" android:exported="false" android:enabled="true">` + view + `</service>`,
+		},
+		{
+			name:    "multi-line tag keeps indentation",
+			finding: componentFinding("activity", ".MainActivity", `android:exported="true"`),
+			content: "        <activity\n            android:name=\".MainActivity\"\n            android:theme=\"@style/App\">\n            " + launcher + "\n        </activity>",
+			want:    "        <activity\n            android:name=\".MainActivity\"\n            android:exported=\"true\"\n            android:theme=\"@style/App\">\n            " + launcher + "\n        </activity>",
+		},
+		{
+			name:    "true is kept private without a launcher filter",
+			finding: componentFinding("activity", ".ShareActivity", `android:exported="true"`),
+			content: `<activity android:name=".ShareActivity">` + view + `</activity>`,
+			want:    `<activity android:name=".ShareActivity" android:exported="false">` + view + `</activity>`,
+		},
+		{
+			name:    "launcher filter of another component is ignored",
+			finding: componentFinding("activity", ".ShareActivity", `android:exported="true"`),
+			content: `<activity android:name=".ShareActivity">` + view + `</activity><activity android:name=".Main">` + launcher + `</activity>`,
+			want:    `<activity android:name=".ShareActivity" android:exported="false">` + view + `</activity><activity android:name=".Main">` + launcher + `</activity>`,
+		},
+		{
+			name:    "matches kind and name",
+			finding: componentFinding("receiver", ".Boot", `android:exported="false"`),
+			content: `<service android:name=".Boot"/><receiver android:name=".Boot"/>`,
+			want:    `<service android:name=".Boot"/><receiver android:name=".Boot" android:exported="false"/>`,
+		},
+		{
+			name:    "already set",
+			finding: componentFinding("service", ".SyncService", `android:exported="false"`),
+			content: `<service android:name=".SyncService" android:exported="true"/>`,
+			want:    `<service android:name=".SyncService" android:exported="true"/>`,
+		},
+		{
+			name:    "component missing",
+			finding: componentFinding("service", ".Gone", `android:exported="false"`),
+			content: `<service android:name=".SyncService"/>`,
+			wantErr: "not found",
+		},
+		{
+			name:    "no component evidence",
+			finding: componentFinding("", "", `android:exported="false"`),
+			content: `<service android:name=".SyncService"/>`,
+			wantErr: ErrUnsupported.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := setComponentAttribute(tt.finding, []byte(tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("setComponentAttribute() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("setComponentAttribute() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("setComponentAttribute() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetGradleSdk(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		content  string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "groovy",
+			template: "targetSdk = 35",
+			content:  "android {\n    defaultConfig {\n        targetSdkVersion 33\n    }\n}\n",
+			want:     "android {\n    defaultConfig {\n        targetSdkVersion 35\n    }\n}\n",
+		},
+		{
+			name:     "kotlin",
+			template: "targetSdk = 35",
+			content:  "defaultConfig {\n    minSdk = 24\n    targetSdk = 33\n}\n",
+			want:     "defaultConfig {\n    minSdk = 24\n    targetSdk = 35\n}\n",
+		},
+		{
+			name:     "call syntax",
+			template: "targetSdk = 35",
+			content:  "    targetSdkVersion(34)\n",
+			want:     "    targetSdkVersion(35)\n",
+		},
+		{
+			name:     "keeps trailing comment",
+			template: "minSdk = 23",
+			content:  "    minSdk = 21 // lowest supported\n",
+			want:     "    minSdk = 23 // lowest supported\n",
+		},
+		{
+			name:     "does not lower",
+			template: "targetSdk = 34",
+			content:  "    targetSdk = 35\n",
+			want:     "    targetSdk = 35\n",
+		},
+		{
+			name:     "quoted literal",
+			template: "targetSdk = 35",
+			content:  "    targetSdkVersion \"33\"\n",
+			want:     "    targetSdkVersion \"35\"\n",
+		},
+		{
+			name:     "defined elsewhere",
+			template: "targetSdk = 35",
+			content:  "    targetSdk = libs.versions.targetSdk.get().toInt()\n",
+			wantErr:  "update it where that value is defined",
+		},
+		{
+			name:     "not set",
+			template: "targetSdk = 35",
+			content:  "    compileSdk = 35\n",
+			wantErr:  "targetSdk is not set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := api.Finding{
+				FilePath:    "app/build.gradle",
+				Remediation: &api.Remediation{Template: tt.template},
+			}
+			got, _, err := setGradleSdk(f, []byte(tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("setGradleSdk() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("setGradleSdk() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("setGradleSdk() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// Package fix applies finding remediation templates to project files.
+//
+// Changes are planned against an in-memory copy of each file, so several
+// fixes to the same file build on each other and nothing is written until
+// Write is called.
+package fix
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+var (
+	ErrUnsupported    = errors.New("no automatic fix for this finding")
+	ErrAlreadyApplied = errors.New("fix already applied")
+	ErrPlaceholder    = errors.New("remediation template is placeholder text")
+)
+
+type Change struct {
+	Finding     api.Finding
+	File        string
+	Description string
+	Before      []byte
+	After       []byte
+}
+
+func (c *Change) Diff() string {
+	return Unified(c.File, c.Before, c.After)
+}
+
+// applier rewrites content for a finding and describes the edit.
+type applier func(f api.Finding, content []byte) ([]byte, string, error)
+
+type Fixer struct {
+	root     string
+	original map[string][]byte
+	current  map[string][]byte
+}
+
+func New(root string) *Fixer {
+	return &Fixer{
+		root:     root,
+		original: make(map[string][]byte),
+		current:  make(map[string][]byte),
+	}
+}
+
+// Supported reports whether a finding has a remediation this package can
+// apply, without reading the target file.
+func Supported(f api.Finding) bool {
+	return lookup(f) != nil
+}
+
+// Plan computes the change for a finding against the file as modified by
+// previously accepted changes.
+func (fx *Fixer) Plan(f api.Finding) (*Change, error) {
+	apply := lookup(f)
+	if apply == nil {
+		return nil, ErrUnsupported
+	}
+
+	rel, err := fx.clean(f.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	before, err := fx.read(rel)
+	if err != nil {
+		return nil, err
+	}
+
+	after, desc, err := apply(f, before)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(before, after) {
+		return nil, ErrAlreadyApplied
+	}
+
+	return &Change{Finding: f, File: rel, Description: desc, Before: before, After: after}, nil
+}
+
+func (fx *Fixer) Accept(c *Change) {
+	fx.current[c.File] = c.After
+}
+
+// Modified returns the files changed by accepted fixes.
+func (fx *Fixer) Modified() []string {
+	var files []string
+	for rel, content := range fx.current {
+		if !bytes.Equal(content, fx.original[rel]) {
+			files = append(files, rel)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Write saves the modified files. Each file is written to a temporary file
+// next to it and renamed over the original, so an interrupted write never
+// leaves a truncated file behind.
+func (fx *Fixer) Write() error {
+	for _, rel := range fx.Modified() {
+		full := filepath.Join(fx.root, filepath.FromSlash(rel))
+		if err := WriteFile(full, fx.current[rel]); err != nil {
+			return fmt.Errorf("write %s: %w", rel, err)
+		}
+	}
+	return nil
+}
+
+// WriteFile replaces path with data through a temporary file in the same
+// directory. An existing file keeps its permissions; a new one is created
+// with mode 0644.
+func WriteFile(path string, data []byte) error {
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (fx *Fixer) read(rel string) ([]byte, error) {
+	if content, ok := fx.current[rel]; ok {
+		return content, nil
+	}
+	content, err := os.ReadFile(filepath.Join(fx.root, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil, err
+	}
+	fx.original[rel] = content
+	fx.current[rel] = content
+	return content, nil
+}
+
+// generatedDirs hold build outputs and vendored dependencies. Edits there
+// are overwritten by the next build or dependency install.
+var generatedDirs = map[string]bool{
+	"build":         true,
+	"intermediates": true,
+	"DerivedData":   true,
+	"Pods":          true,
+	"Carthage":      true,
+	"node_modules":  true,
+}
+
+// clean rejects paths that leave the project root or point into build
+// outputs or vendored dependencies.
+func (fx *Fixer) clean(p string) (string, error) {
+	if p == "" {
+		return "", ErrUnsupported
+	}
+	rel := filepath.ToSlash(filepath.Clean(filepath.FromSlash(p)))
+	if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("%s is outside the project", p)
+	}
+	for _, dir := range strings.Split(path.Dir(rel), "/") {
+		if generatedDirs[dir] {
+			return "", fmt.Errorf("%s is under %s/, which is generated or vendored; fix the source file instead", p, dir)
+		}
+	}
+	return rel, nil
+}
+
+func lookup(f api.Finding) applier {
+	if f.Remediation == nil || f.Remediation.Template == "" || f.FilePath == "" {
+		return nil
+	}
+	template := strings.TrimSpace(f.Remediation.Template)
+	base := filepath.Base(f.FilePath)
+
+	switch {
+	case strings.HasSuffix(base, ".xcprivacy") && strings.HasPrefix(template, "<dict>"):
+		return addPrivacyAPIType
+	case strings.HasSuffix(base, ".plist") && strings.HasPrefix(template, "<key>"):
+		return addPlistKeys
+	case base == "AndroidManifest.xml" && strings.HasPrefix(template, "<uses-permission"):
+		return setManifestPermission
+	case base == "AndroidManifest.xml" && attributeRe.MatchString(template):
+		return setComponentAttribute
+	case (base == "build.gradle" || base == "build.gradle.kts") && gradleSettingRe.MatchString(template):
+		return setGradleSdk
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package fix
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+
+	created := filepath.Join(dir, "new.xml")
+	if err := WriteFile(created, []byte("new")); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	info, err := os.Stat(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("new file mode = %v, want 0644", info.Mode().Perm())
+	}
+
+	existing := filepath.Join(dir, "existing.xml")
+	if err := os.WriteFile(existing, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(existing, []byte("replaced")); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	data, err := os.ReadFile(existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "replaced" {
+		t.Errorf("content = %q, want %q", data, "replaced")
+	}
+	info, err = os.Stat(existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("existing file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("directory has %d entries, want 2 (temporary file left behind)", len(entries))
+	}
+}
+
+func TestPlanRejectsGeneratedFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{"merged manifest", "app/build/intermediates/merged_manifests/release/AndroidManifest.xml", "under build/"},
+		{"intermediates", "intermediates/AndroidManifest.xml", "under intermediates/"},
+		{"pods", "Pods/Firebase/Info.plist", "under Pods/"},
+		{"carthage", "Carthage/Checkouts/SDK/Info.plist", "under Carthage/"},
+		{"node modules", "node_modules/react-native/android/src/main/AndroidManifest.xml", "under node_modules/"},
+		{"derived data", "DerivedData/App/Info.plist", "under DerivedData/"},
+		{"outside", "../other/AndroidManifest.xml", "outside the project"},
+	}
+
+	fixer := New(t.TempDir())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := api.Finding{
+				FilePath:    tt.file,
+				Remediation: &api.Remediation{Template: `<uses-permission android:name="android.permission.CAMERA" />`},
+			}
+			if strings.HasSuffix(tt.file, ".plist") {
+				f.Remediation.Template = "<key>NSCameraUsageDescription</key>\n<string>Scan receipts to attach them to expenses.</string>"
+			}
+			_, err := fixer.Plan(f)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Plan(%s) error = %v, want %q", tt.file, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package fix
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/plist"
+	"github.com/hha-nguyen/canopy-cli/internal/preflight"
+)
+
+var (
+	templateKeyRe    = regexp.MustCompile(`<key>([^<]+)</key>`)
+	firstKeyRe       = regexp.MustCompile(`\n([ \t]*)<key>`)
+	privacyTypesRe   = regexp.MustCompile(`<key>NSPrivacyAccessedAPITypes</key>\s*(<array/?>)`)
+	privacyReasonsRe = regexp.MustCompile(`<key>NSPrivacyAccessedAPITypeReasons</key>\s*(<array/>|<array>\s*</array>)`)
+)
+
+// addPlistKeys inserts the key/value pairs of a template at the end of the
+// root dictionary, keeping the file's formatting and indentation.
+func addPlistKeys(f api.Finding, content []byte) ([]byte, string, error) {
+	if bytes.HasPrefix(content, []byte("bplist")) {
+		return nil, "", fmt.Errorf("%s is a binary property list; convert it with plutil -convert xml1 first", f.FilePath)
+	}
+
+	dict, err := plist.DecodeDict(content)
+	if err != nil {
+		return nil, "", fmt.Errorf("parse %s: %w", f.FilePath, err)
+	}
+
+	template := strings.TrimSpace(f.Remediation.Template)
+	var keys []string
+	for _, m := range templateKeyRe.FindAllStringSubmatch(template, -1) {
+		keys = append(keys, m[1])
+	}
+	missing := false
+	for _, key := range keys {
+		if _, ok := dict[key]; !ok {
+			missing = true
+		}
+	}
+	if !missing {
+		return content, "", nil
+	}
+	values, err := plist.DecodeDict([]byte("<plist><dict>" + template + "</dict></plist>"))
+	if err != nil {
+		return nil, "", fmt.Errorf("parse remediation template: %w", err)
+	}
+	for key, v := range values {
+		if s, ok := v.(string); ok && preflight.IsPlaceholder(key, s) {
+			return nil, "", fmt.Errorf("%w: write a purpose string for %s that explains the access", ErrPlaceholder, key)
+		}
+	}
+	if len(keys) > 1 {
+		return nil, "", fmt.Errorf("template sets %d keys, some of which already exist in %s", len(keys), f.FilePath)
+	}
+
+	closeDict, ok := rootDictEnd(content)
+	if !ok {
+		return nil, "", fmt.Errorf("%s has no root dictionary", f.FilePath)
+	}
+
+	indent := "\t"
+	if m := firstKeyRe.FindSubmatch(content); m != nil {
+		indent = string(m[1])
+	}
+
+	var insert bytes.Buffer
+	lineStart := bytes.LastIndexByte(content[:closeDict], '\n') + 1
+	at := lineStart
+	if strings.TrimSpace(string(content[lineStart:closeDict])) != "" {
+		at = closeDict
+		insert.WriteByte('\n')
+	}
+	for _, line := range strings.Split(template, "\n") {
+		insert.WriteString(indent + strings.TrimSpace(line) + "\n")
+	}
+
+	out := splice(content, at, at, insert.Bytes())
+	if _, err := plist.DecodeDict(out); err != nil {
+		return nil, "", fmt.Errorf("template does not produce a valid property list: %w", err)
+	}
+
+	return out, fmt.Sprintf("Add %s to %s", strings.Join(keys, ", "), f.FilePath), nil
+}
+
+// PurposeKey returns the Info.plist key a finding's template adds, or "" when
+// the finding is not fixed by adding a single key.
+func PurposeKey(f api.Finding) string {
+	if f.Remediation == nil {
+		return ""
+	}
+	keys := templateKeyRe.FindAllStringSubmatch(f.Remediation.Template, -1)
+	if len(keys) != 1 {
+		return ""
+	}
+	return keys[0][1]
+}
+
+// WithPurpose returns a copy of f whose template sets its key to the given
+// purpose string, for templates holding placeholder text.
+func WithPurpose(f api.Finding, purpose string) (api.Finding, error) {
+	key := PurposeKey(f)
+	if key == "" {
+		return f, ErrUnsupported
+	}
+	purpose = strings.TrimSpace(purpose)
+	if purpose == "" || preflight.IsPlaceholder(key, purpose) {
+		return f, fmt.Errorf("%w: %q does not explain the access", ErrPlaceholder, purpose)
+	}
+
+	var value bytes.Buffer
+	if err := xml.EscapeText(&value, []byte(purpose)); err != nil {
+		return f, err
+	}
+	remediation := *f.Remediation
+	remediation.Template = fmt.Sprintf("<key>%s</key>\n<string>%s</string>", key, value.String())
+	f.Remediation = &remediation
+	return f, nil
+}
+
+// addPrivacyAPIType adds an NSPrivacyAccessedAPITypes entry to a privacy
+// manifest, or reasons to an entry that declares none. The XML is edited
+// in place so the rest of the file keeps its formatting.
+func addPrivacyAPIType(f api.Finding, content []byte) ([]byte, string, error) {
+	if bytes.HasPrefix(content, []byte("bplist")) {
+		return nil, "", fmt.Errorf("%s is a binary property list; convert it with plutil -convert xml1 first", f.FilePath)
+	}
+	dict, err := plist.DecodeDict(content)
+	if err != nil {
+		return nil, "", fmt.Errorf("parse %s: %w", f.FilePath, err)
+	}
+
+	template := strings.TrimSpace(f.Remediation.Template)
+	entry, err := plist.DecodeDict([]byte("<plist>" + template + "</plist>"))
+	if err != nil {
+		return nil, "", fmt.Errorf("parse remediation template: %w", err)
+	}
+	category, _ := entry["NSPrivacyAccessedAPIType"].(string)
+	reasons, _ := entry["NSPrivacyAccessedAPITypeReasons"].([]interface{})
+	if category == "" || len(reasons) == 0 {
+		return nil, "", ErrUnsupported
+	}
+
+	unit := "\t"
+	if m := firstKeyRe.FindSubmatch(content); m != nil && len(m[1]) > 0 {
+		unit = string(m[1])
+	}
+
+	var out []byte
+	types, hasTypes := dict["NSPrivacyAccessedAPITypes"].([]interface{})
+	declared := false
+	for _, item := range types {
+		existing, _ := item.(map[string]interface{})
+		if existing["NSPrivacyAccessedAPIType"] != category {
+			continue
+		}
+		if r, _ := existing["NSPrivacyAccessedAPITypeReasons"].([]interface{}); len(r) > 0 {
+			return content, "", nil
+		}
+		declared = true
+	}
+
+	switch {
+	case declared:
+		out, err = addPrivacyReasons(content, category, reasons, unit)
+	case hasTypes:
+		out, err = appendPrivacyEntry(content, template, unit)
+	default:
+		closeDict, ok := rootDictEnd(content)
+		if !ok {
+			return nil, "", fmt.Errorf("%s has no root dictionary", f.FilePath)
+		}
+		lines := []string{"\t<key>NSPrivacyAccessedAPITypes</key>", "\t<array>"}
+		for _, line := range strings.Split(template, "\n") {
+			lines = append(lines, "\t\t"+line)
+		}
+		out = insertLines(content, closeDict, append(lines, "\t</array>"), unit)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", f.FilePath, err)
+	}
+	if _, err := plist.DecodeDict(out); err != nil {
+		return nil, "", fmt.Errorf("template does not produce a valid property list: %w", err)
+	}
+
+	return out, fmt.Sprintf("Declare %s in %s (review the reason code)", category, f.FilePath), nil
+}
+
+// appendPrivacyEntry adds an entry at the end of the NSPrivacyAccessedAPITypes
+// array.
+func appendPrivacyEntry(content []byte, template, unit string) ([]byte, error) {
+	m := privacyTypesRe.FindSubmatchIndex(content)
+	if m == nil {
+		return nil, fmt.Errorf("NSPrivacyAccessedAPITypes is not an array")
+	}
+	content, closeAt := openArray(content, m[2], m[3])
+	if closeAt < 0 {
+		return nil, fmt.Errorf("NSPrivacyAccessedAPITypes has no closing </array>")
+	}
+
+	var lines []string
+	for _, line := range strings.Split(template, "\n") {
+		lines = append(lines, "\t"+line)
+	}
+	return insertLines(content, closeAt, lines, unit), nil
+}
+
+// addPrivacyReasons fills in the reasons of an entry declared without any.
+// Entries only hold strings and string arrays, so the nearest <dict>
+// before the category and </dict> after it delimit the entry.
+func addPrivacyReasons(content []byte, category string, reasons []interface{}, unit string) ([]byte, error) {
+	re := regexp.MustCompile(`<key>NSPrivacyAccessedAPIType</key>\s*<string>` + regexp.QuoteMeta(category) + `</string>`)
+	loc := re.FindIndex(content)
+	if loc == nil {
+		return nil, fmt.Errorf("cannot locate the %s entry", category)
+	}
+	open := bytes.LastIndex(content[:loc[0]], []byte("<dict>"))
+	end := bytes.Index(content[loc[1]:], []byte("</dict>"))
+	if open < 0 || end < 0 {
+		return nil, fmt.Errorf("cannot locate the %s entry", category)
+	}
+	end += loc[1]
+
+	var strs []string
+	for _, r := range reasons {
+		s, _ := r.(string)
+		strs = append(strs, "<string>"+escapeText(s)+"</string>")
+	}
+
+	if m := privacyReasonsRe.FindSubmatchIndex(content[open:end]); m != nil {
+		content, closeAt := openArray(content, open+m[2], open+m[3])
+		var lines []string
+		for _, s := range strs {
+			lines = append(lines, "\t"+s)
+		}
+		return insertLines(content, closeAt, lines, unit), nil
+	}
+	if bytes.Contains(content[open:end], []byte("<key>NSPrivacyAccessedAPITypeReasons</key>")) {
+		return nil, fmt.Errorf("NSPrivacyAccessedAPITypeReasons of %s is not an empty array", category)
+	}
+
+	lines := []string{"\t<key>NSPrivacyAccessedAPITypeReasons</key>", "\t<array>"}
+	for _, s := range strs {
+		lines = append(lines, "\t\t"+s)
+	}
+	return insertLines(content, end, append(lines, "\t</array>"), unit), nil
+}
+
+// openArray rewrites an empty <array/> at [start, end) as <array></array>
+// and returns the position of the array's closing tag.
+func openArray(content []byte, start, end int) ([]byte, int) {
+	if string(content[start:end]) == "<array/>" {
+		content = splice(content, start, end, []byte("<array></array>"))
+		return content, start + len("<array>")
+	}
+
+	depth := 0
+	for i := start; i < len(content); i++ {
+		switch {
+		case bytes.HasPrefix(content[i:], []byte("<array>")):
+			depth++
+		case bytes.HasPrefix(content[i:], []byte("</array>")):
+			depth--
+			if depth == 0 {
+				return content, i
+			}
+		}
+	}
+	return content, -1
+}
+
+// rootDictEnd returns the position of the root dictionary's </dict>.
+func rootDictEnd(content []byte) (int, bool) {
+	end := bytes.LastIndex(content, []byte("</plist>"))
+	if end < 0 {
+		end = len(content)
+	}
+	closeDict := bytes.LastIndex(content[:end], []byte("</dict>"))
+	return closeDict, closeDict >= 0
+}
+
+// insertLines inserts lines before the closing tag at closeAt. Leading tabs
+// in a line give its depth below the closing tag's line and are replaced
+// with the file's indent unit.
+func insertLines(content []byte, closeAt int, lines []string, unit string) []byte {
+	lineStart := bytes.LastIndexByte(content[:closeAt], '\n') + 1
+	prefix := string(content[lineStart:closeAt])
+	indent := prefix[:len(prefix)-len(strings.TrimLeft(prefix, " \t"))]
+
+	var insert bytes.Buffer
+	at := lineStart
+	if strings.TrimSpace(prefix) != "" {
+		at = closeAt
+		insert.WriteByte('\n')
+	}
+	for _, line := range lines {
+		depth := len(line) - len(strings.TrimLeft(line, "\t"))
+		insert.WriteString(indent + strings.Repeat(unit, depth) + strings.TrimSpace(line) + "\n")
+	}
+	if at == closeAt {
+		insert.WriteString(indent)
+	}
+	return splice(content, at, at, insert.Bytes())
+}
+
+func escapeText(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func splice(content []byte, start, end int, insert []byte) []byte {
+	out := make([]byte, 0, len(content)+len(insert))
+	out = append(out, content[:start]...)
+	out = append(out, insert...)
+	return append(out, content[end:]...)
+}
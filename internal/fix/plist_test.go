@@ -0,0 +1,338 @@
+package fix
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+const privacyEntry = "<dict>\n\t<key>NSPrivacyAccessedAPIType</key>\n\t<string>NSPrivacyAccessedAPICategoryUserDefaults</string>\n\t<key>NSPrivacyAccessedAPITypeReasons</key>\n\t<array>\n\t\t<string>CA92.1</string>\n\t</array>\n</dict>"
+
+func TestAddPrivacyAPIType(t *testing.T) {
+	const header = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+`
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "adds the array",
+			content: header + `<dict>
+  <!-- tracking -->
+  <key>NSPrivacyTracking</key>
+  <false/>
+</dict>
+</plist>
+`,
+			want: header + `<dict>
+  <!-- tracking -->
+  <key>NSPrivacyTracking</key>
+  <false/>
+  <key>NSPrivacyAccessedAPITypes</key>
+  <array>
+    <dict>
+      <key>NSPrivacyAccessedAPIType</key>
+      <string>NSPrivacyAccessedAPICategoryUserDefaults</string>
+      <key>NSPrivacyAccessedAPITypeReasons</key>
+      <array>
+        <string>CA92.1</string>
+      </array>
+    </dict>
+  </array>
+</dict>
+</plist>
+`,
+		},
+		{
+			name: "appends to the array",
+			content: header + `<dict>
+	<key>NSPrivacyAccessedAPITypes</key>
+	<array>
+		<dict>
+			<key>NSPrivacyAccessedAPIType</key>
+			<string>NSPrivacyAccessedAPICategoryFileTimestamp</string>
+			<key>NSPrivacyAccessedAPITypeReasons</key>
+			<array>
+				<string>C617.1</string>
+			</array>
+		</dict>
+	</array>
+</dict>
+</plist>
+`,
+			want: header + `<dict>
+	<key>NSPrivacyAccessedAPITypes</key>
+	<array>
+		<dict>
+			<key>NSPrivacyAccessedAPIType</key>
+			<string>NSPrivacyAccessedAPICategoryFileTimestamp</string>
+			<key>NSPrivacyAccessedAPITypeReasons</key>
+			<array>
+				<string>C617.1</string>
+			</array>
+		</dict>
+		<dict>
+			<key>NSPrivacyAccessedAPIType</key>
+			<string>NSPrivacyAccessedAPICategoryUserDefaults</string>
+			<key>NSPrivacyAccessedAPITypeReasons</key>
+			<array>
+				<string>CA92.1</string>
+			</array>
+		</dict>
+	</array>
+</dict>
+</plist>
+`,
+		},
+		{
+			name: "fills an empty array",
+			content: header + `<dict>
+	<key>NSPrivacyAccessedAPITypes</key>
+	<array/>
+</dict>
+</plist>
+`,
+			want: header + `<dict>
+	<key>NSPrivacyAccessedAPITypes</key>
+	<array>
+		<dict>
+			<key>NSPrivacyAccessedAPIType</key>
+			<string>NSPrivacyAccessedAPICategoryUserDefaults</string>
+			<key>NSPrivacyAccessedAPITypeReasons</key>
+			<array>
+				<string>CA92.1</string>
+			</array>
+		</dict>
+	</array>
+</dict>
+</plist>
+`,
+		},
+		{
+			name: "fills empty reasons",
+			content: header + `<dict>
+	<key>NSPrivacyAccessedAPITypes</key>
+	<array>
+		<dict>
+			<key>NSPrivacyAccessedAPIType</key>
+			<string>NSPrivacyAccessedAPICategoryUserDefaults</string>
+			<key>NSPrivacyAccessedAPITypeReasons</key>
+			<array/>
+		</dict>
+	</array>
+</dict>
+</plist>
+`,
+			want: header + `<dict>
+	<key>NSPrivacyAccessedAPITypes</key>
+	<array>
+		<dict>
+			<key>NSPrivacyAccessedAPIType</key>
+			<string>NSPrivacyAccessedAPICategoryUserDefaults</string>
+			<key>NSPrivacyAccessedAPITypeReasons</key>
+			<array>
+				<string>CA92.1</string>
+			</array>
+		</dict>
+	</array>
+</dict>
+</plist>
+`,
+		},
+		{
+			name: "adds missing reasons",
+			content: header + `<dict>
+	<key>NSPrivacyAccessedAPITypes</key>
+	<array>
+		<dict>
+			<key>NSPrivacyAccessedAPIType</key>
+			<string>NSPrivacyAccessedAPICategoryUserDefaults</string>
+		</dict>
+	</array>
+</dict>
+</plist>
+`,
+			want: header + `<dict>
+	<key>NSPrivacyAccessedAPITypes</key>
+	<array>
+		<dict>
+			<key>NSPrivacyAccessedAPIType</key>
+			<string>NSPrivacyAccessedAPICategoryUserDefaults</string>
+			<key>NSPrivacyAccessedAPITypeReasons</key>
+			<array>
+				<string>CA92.1</string>
+			</array>
+		</dict>
+	</array>
+</dict>
+</plist>
+`,
+		},
+		{
+			name: "already declared",
+			content: header + `<dict>
+	<key>NSPrivacyAccessedAPITypes</key>
+	<array>
+		<dict>
+			<key>NSPrivacyAccessedAPIType</key>
+			<string>NSPrivacyAccessedAPICategoryUserDefaults</string>
+			<key>NSPrivacyAccessedAPITypeReasons</key>
+			<array>
+				<string>1C8F.1</string>
+			</array>
+		</dict>
+	</array>
+</dict>
+</plist>
+`,
+			want: header + `<dict>
+	<key>NSPrivacyAccessedAPITypes</key>
+	<array>
+		<dict>
+			<key>NSPrivacyAccessedAPIType</key>
+			<string>NSPrivacyAccessedAPICategoryUserDefaults</string>
+			<key>NSPrivacyAccessedAPITypeReasons</key>
+			<array>
+				<string>1C8F.1</string>
+			</array>
+		</dict>
+	</array>
+</dict>
+</plist>
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := api.Finding{
+				FilePath:    "App/PrivacyInfo.xcprivacy",
+				Remediation: &api.Remediation{Template: privacyEntry},
+			}
+			got, _, err := addPrivacyAPIType(f, []byte(tt.content))
+			if err != nil {
+				t.Fatalf("addPrivacyAPIType() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("addPrivacyAPIType() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddPlistKeys(t *testing.T) {
+	const content = "<plist version=\"1.0\">\n<dict>\n\t<key>CFBundleName</key>\n\t<string>App</string>\n</dict>\n</plist>\n"
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "adds the key",
+			template: "<key>NSCameraUsageDescription</key>\n<string>Scan receipts to attach them to expenses.</string>",
+			want:     "<plist version=\"1.0\">\n<dict>\n\t<key>CFBundleName</key>\n\t<string>App</string>\n\t<key>NSCameraUsageDescription</key>\n\t<string>Scan receipts to attach them to expenses.</string>\n</dict>\n</plist>\n",
+		},
+		{
+			name:     "already present",
+			template: "<key>CFBundleName</key>\n<string>Other</string>",
+			want:     content,
+		},
+		{
+			name:     "placeholder purpose string",
+			template: "<key>NSCameraUsageDescription</key>\n<string>Describe how the app uses the camera</string>",
+			wantErr:  "placeholder text",
+		},
+		{
+			name:     "key as value",
+			template: "<key>NSCameraUsageDescription</key>\n<string>NSCameraUsageDescription</string>",
+			wantErr:  "placeholder text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := api.Finding{
+				FilePath:    "App/Info.plist",
+				Remediation: &api.Remediation{Template: tt.template},
+			}
+			got, _, err := addPlistKeys(f, []byte(content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("addPlistKeys() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("addPlistKeys() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("addPlistKeys() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithPurpose(t *testing.T) {
+	placeholder := api.Finding{
+		FilePath: "App/Info.plist",
+		Remediation: &api.Remediation{
+			Template: "<key>NSCameraUsageDescription</key>\n<string>Describe how the app uses camera</string>",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		purpose string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "sets the purpose",
+			purpose: "Scan receipts to attach them to expenses.",
+			want:    "<key>NSCameraUsageDescription</key>\n<string>Scan receipts to attach them to expenses.</string>",
+		},
+		{
+			name:    "escapes markup",
+			purpose: "Scan <receipts> & invoices",
+			want:    "<key>NSCameraUsageDescription</key>\n<string>Scan &lt;receipts&gt; &amp; invoices</string>",
+		},
+		{
+			name:    "placeholder",
+			purpose: "TODO",
+			wantErr: "does not explain the access",
+		},
+		{
+			name:    "empty",
+			purpose: "  ",
+			wantErr: "does not explain the access",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WithPurpose(placeholder, tt.purpose)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("WithPurpose() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WithPurpose() error = %v", err)
+			}
+			if got.Remediation.Template != tt.want {
+				t.Errorf("template = %q, want %q", got.Remediation.Template, tt.want)
+			}
+			if placeholder.Remediation.Template == tt.want {
+				t.Error("WithPurpose() modified the original finding")
+			}
+		})
+	}
+}
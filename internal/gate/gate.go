@@ -0,0 +1,231 @@
+package gate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/config"
+	"github.com/hha-nguyen/canopy-cli/internal/exit"
+	"github.com/hha-nguyen/canopy-cli/internal/rules"
+)
+
+var severityOrder = []string{"blocker", "high", "medium", "low", "info", "total"}
+
+func FromThreshold(threshold exit.Threshold) config.GateConfig {
+	max := make(map[string]int)
+	for _, severity := range threshold.Severities() {
+		max[severity] = 0
+	}
+	return config.GateConfig{Max: max}
+}
+
+// WithDefault keeps the default threshold of failing on any blocker for a
+// project gate that sets no blocker limit of its own.
+func WithDefault(g config.GateConfig) config.GateConfig {
+	if _, ok := g.Max["blocker"]; ok {
+		return g
+	}
+	return Merge(g, FromThreshold(exit.ThresholdBlocker))
+}
+
+// Merge combines two gates; the stricter limit wins where both set one.
+func Merge(a, b config.GateConfig) config.GateConfig {
+	merged := config.GateConfig{
+		Max:               make(map[string]int),
+		MaxRiskScore:      a.MaxRiskScore,
+		MinRiskConfidence: a.MinRiskConfidence,
+		FailOnCategories:  append(append([]string{}, a.FailOnCategories...), b.FailOnCategories...),
+		Platforms:         a.Platforms,
+	}
+
+	for k, v := range a.Max {
+		merged.Max[k] = v
+	}
+	for k, v := range b.Max {
+		if cur, ok := merged.Max[k]; !ok || v < cur {
+			merged.Max[k] = v
+		}
+	}
+
+	if b.MaxRiskScore != nil && (merged.MaxRiskScore == nil || *b.MaxRiskScore < *merged.MaxRiskScore) {
+		merged.MaxRiskScore = b.MaxRiskScore
+	}
+	if merged.MinRiskConfidence == "" {
+		merged.MinRiskConfidence = b.MinRiskConfidence
+	}
+	if merged.Platforms == nil {
+		merged.Platforms = b.Platforms
+	}
+
+	return merged
+}
+
+func Evaluate(result *api.ScanResult, policy config.GateConfig) *api.GateResult {
+	var violations []string
+
+	violations = append(violations, evaluate(result, policy, activeFindings(result.Findings), "")...)
+
+	platforms := make([]string, 0, len(policy.Platforms))
+	for p := range policy.Platforms {
+		platforms = append(platforms, p)
+	}
+	sort.Strings(platforms)
+
+	for _, p := range platforms {
+		platform := normalizePlatform(p)
+		if !scanCovers(result.Platform, platform) {
+			continue
+		}
+
+		var findings []api.Finding
+		for _, f := range activeFindings(result.Findings) {
+			if findingPlatform(f, result.Platform) == "" || findingPlatform(f, result.Platform) == platform {
+				findings = append(findings, f)
+			}
+		}
+
+		violations = append(violations, evaluate(result, policy.Platforms[p], findings, strings.ToLower(platform))...)
+	}
+
+	return &api.GateResult{
+		Passed:     len(violations) == 0,
+		Violations: violations,
+	}
+}
+
+func evaluate(result *api.ScanResult, policy config.GateConfig, findings []api.Finding, scope string) []string {
+	var violations []string
+	prefix := ""
+	if scope != "" {
+		prefix = "[" + scope + "] "
+	}
+
+	counts := countSeverities(findings)
+	for _, severity := range severityOrder {
+		max, ok := policy.Max[severity]
+		if !ok {
+			continue
+		}
+		if counts[severity] > max {
+			label := strings.ToUpper(severity)
+			if severity == "total" {
+				label = "total"
+			}
+			violations = append(violations, fmt.Sprintf("%s%d %s findings exceed the maximum of %d", prefix, counts[severity], label, max))
+		}
+	}
+
+	if policy.MaxRiskScore != nil && result.RiskAssessment != nil {
+		score := result.RiskAssessment.Score
+		if score > *policy.MaxRiskScore && meetsConfidence(result.RiskAssessment.Confidence, policy.MinRiskConfidence) {
+			violations = append(violations, fmt.Sprintf("%srisk score %d exceeds the maximum of %d", prefix, score, *policy.MaxRiskScore))
+		}
+	}
+
+	for _, category := range policy.FailOnCategories {
+		pattern := category
+		if !strings.HasPrefix(strings.ToLower(pattern), "category:") {
+			pattern = "category:" + pattern
+		}
+
+		var matched []string
+		for _, f := range findings {
+			if rules.Matches(pattern, f) {
+				matched = append(matched, f.RuleCode)
+			}
+		}
+		if len(matched) > 0 {
+			violations = append(violations, fmt.Sprintf("%s%d findings in category %s (%s)", prefix, len(matched), category, strings.Join(unique(matched), ", ")))
+		}
+	}
+
+	return violations
+}
+
+// countSeverities counts the active findings the gate applies to. Like the
+// summary, it is derived from the findings list.
+func countSeverities(findings []api.Finding) map[string]int {
+	summary := api.Summarize(findings, 0)
+
+	return map[string]int{
+		"blocker": summary.Blocker,
+		"high":    summary.High,
+		"medium":  summary.Medium,
+		"low":     summary.Low,
+		"info":    summary.Info,
+		"total":   summary.Total,
+	}
+}
+
+func activeFindings(findings []api.Finding) []api.Finding {
+	active := make([]api.Finding, 0, len(findings))
+	for _, f := range findings {
+		if !f.Accepted() {
+			active = append(active, f)
+		}
+	}
+	return active
+}
+
+func meetsConfidence(actual, min string) bool {
+	if min == "" {
+		return true
+	}
+	return confidenceRank(actual) >= confidenceRank(min)
+}
+
+func confidenceRank(c string) int {
+	switch strings.ToUpper(c) {
+	case "HIGH":
+		return 3
+	case "MEDIUM":
+		return 2
+	case "LOW":
+		return 1
+	default:
+		return 0
+	}
+}
+
+func normalizePlatform(p string) string {
+	switch strings.ToLower(p) {
+	case "apple", "ios":
+		return string(api.PlatformApple)
+	case "google", "android":
+		return string(api.PlatformGoogle)
+	default:
+		return strings.ToUpper(p)
+	}
+}
+
+func scanCovers(scanPlatform, platform string) bool {
+	scanPlatform = strings.ToUpper(scanPlatform)
+	return scanPlatform == "" || scanPlatform == string(api.PlatformBoth) || scanPlatform == platform
+}
+
+// findingPlatform returns the platform a finding applies to, or "" when it
+// cannot be determined and should count for every platform.
+func findingPlatform(f api.Finding, scanPlatform string) string {
+	if f.Platform != "" {
+		return normalizePlatform(f.Platform)
+	}
+	switch strings.ToUpper(scanPlatform) {
+	case string(api.PlatformApple), string(api.PlatformGoogle):
+		return strings.ToUpper(scanPlatform)
+	}
+	return ""
+}
+
+func unique(values []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,176 @@
+package gate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/config"
+	"github.com/hha-nguyen/canopy-cli/internal/exit"
+)
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestEvaluate(t *testing.T) {
+	findings := []api.Finding{
+		{RuleCode: "APL-PRIV-001", Severity: "BLOCKER", Platform: "APPLE"},
+		{RuleCode: "APL-PLIST-001", Severity: "HIGH", Platform: "APPLE"},
+		{RuleCode: "GPL-SDK-001", Severity: "MEDIUM", Platform: "GOOGLE"},
+		{RuleCode: "GPL-MAN-002", Severity: "MEDIUM"},
+		{RuleCode: "GPL-PERM-001", Severity: "BLOCKER", BaselineState: api.BaselineUnchanged},
+		{RuleCode: "APL-PRIV-002", Severity: "BLOCKER", Suppressions: []api.Suppression{{Kind: "inline"}}},
+	}
+
+	tests := []struct {
+		name     string
+		platform string
+		risk     *api.RiskAssessment
+		policy   config.GateConfig
+		want     []string
+	}{
+		{
+			name:   "threshold",
+			policy: FromThreshold(exit.ThresholdHigh),
+			want: []string{
+				"1 BLOCKER findings exceed the maximum of 0",
+				"1 HIGH findings exceed the maximum of 0",
+			},
+		},
+		{
+			name:   "limits within bounds",
+			policy: config.GateConfig{Max: map[string]int{"blocker": 1, "medium": 2, "total": 4}},
+		},
+		{
+			name:   "total",
+			policy: config.GateConfig{Max: map[string]int{"total": 3}},
+			want:   []string{"4 total findings exceed the maximum of 3"},
+		},
+		{
+			name:   "categories",
+			policy: config.GateConfig{FailOnCategories: []string{"GPL-*", "category:APL-ENT"}},
+			want:   []string{"2 findings in category GPL-* (GPL-SDK-001, GPL-MAN-002)"},
+		},
+		{
+			name:   "risk score",
+			risk:   &api.RiskAssessment{Score: 82, Confidence: "MEDIUM"},
+			policy: config.GateConfig{MaxRiskScore: intPtr(70), MinRiskConfidence: "medium"},
+			want:   []string{"risk score 82 exceeds the maximum of 70"},
+		},
+		{
+			name:   "risk score below the required confidence",
+			risk:   &api.RiskAssessment{Score: 82, Confidence: "LOW"},
+			policy: config.GateConfig{MaxRiskScore: intPtr(70), MinRiskConfidence: "medium"},
+		},
+		{
+			name:   "risk confidence does not filter findings",
+			risk:   &api.RiskAssessment{Score: 10, Confidence: "LOW"},
+			policy: config.GateConfig{Max: map[string]int{"high": 0}, MinRiskConfidence: "high"},
+			want:   []string{"1 HIGH findings exceed the maximum of 0"},
+		},
+		{
+			name:     "platform gates",
+			platform: "BOTH",
+			policy: config.GateConfig{Platforms: map[string]config.GateConfig{
+				"google": {Max: map[string]int{"medium": 1}},
+				"ios":    {Max: map[string]int{"blocker": 0}},
+			}},
+			want: []string{
+				"[google] 2 MEDIUM findings exceed the maximum of 1",
+				"[apple] 1 BLOCKER findings exceed the maximum of 0",
+			},
+		},
+		{
+			name:     "platform gate outside the scan",
+			platform: "GOOGLE",
+			policy: config.GateConfig{Platforms: map[string]config.GateConfig{
+				"apple": {Max: map[string]int{"blocker": 0}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &api.ScanResult{
+				Platform:       tt.platform,
+				RiskAssessment: tt.risk,
+				Findings:       findings,
+			}
+			result.RecomputeSummary()
+
+			got := Evaluate(result, tt.policy)
+			if !reflect.DeepEqual(got.Violations, tt.want) {
+				t.Errorf("Violations = %q, want %q", got.Violations, tt.want)
+			}
+			if got.Passed != (len(tt.want) == 0) {
+				t.Errorf("Passed = %v with violations %q", got.Passed, got.Violations)
+			}
+		})
+	}
+}
+
+func TestEvaluateCountsFindings(t *testing.T) {
+	result := &api.ScanResult{
+		Summary:  &api.ScanSummary{},
+		Findings: []api.Finding{{RuleCode: "APL-PRIV-001", Severity: "BLOCKER"}},
+	}
+
+	got := Evaluate(result, FromThreshold(exit.ThresholdBlocker))
+	want := []string{"1 BLOCKER findings exceed the maximum of 0"}
+	if !reflect.DeepEqual(got.Violations, want) {
+		t.Errorf("Violations = %q, want %q", got.Violations, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := config.GateConfig{
+		Max:              map[string]int{"blocker": 0, "high": 5},
+		MaxRiskScore:     intPtr(80),
+		FailOnCategories: []string{"APL-PRIV"},
+	}
+	b := config.GateConfig{
+		Max:               map[string]int{"high": 2, "medium": 10},
+		MaxRiskScore:      intPtr(60),
+		MinRiskConfidence: "high",
+		FailOnCategories:  []string{"GPL-SDK"},
+	}
+
+	got := Merge(a, b)
+	want := config.GateConfig{
+		Max:               map[string]int{"blocker": 0, "high": 2, "medium": 10},
+		MaxRiskScore:      intPtr(60),
+		MinRiskConfidence: "high",
+		FailOnCategories:  []string{"APL-PRIV", "GPL-SDK"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWithDefault(t *testing.T) {
+	tests := []struct {
+		name string
+		gate config.GateConfig
+		want map[string]int
+	}{
+		{
+			name: "adds the blocker limit",
+			gate: config.GateConfig{Max: map[string]int{"high": 3}},
+			want: map[string]int{"blocker": 0, "high": 3},
+		},
+		{
+			name: "keeps an explicit blocker limit",
+			gate: config.GateConfig{Max: map[string]int{"blocker": 2}},
+			want: map[string]int{"blocker": 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WithDefault(tt.gate).Max; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WithDefault().Max = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package labels
+
+// dataType is one kind of user data with its name in each store form. An
+// empty Play or Apple type means the form has no matching entry.
+type dataType struct {
+	PlayCategory  string
+	PlayType      string
+	AppleCategory string
+	AppleType     string
+	// ManifestType is the NSPrivacyCollectedDataType suffix.
+	ManifestType string
+}
+
+var dataTypes = map[string]dataType{
+	"precise_location": {"Location", "Precise location", "Location", "Precise Location", "PreciseLocation"},
+	"approx_location":  {"Location", "Approximate location", "Location", "Coarse Location", "CoarseLocation"},
+	"name":             {"Personal info", "Name", "Contact Info", "Name", "Name"},
+	"email":            {"Personal info", "Email address", "Contact Info", "Email Address", "EmailAddress"},
+	"phone":            {"Personal info", "Phone number", "Contact Info", "Phone Number", "PhoneNumber"},
+	"user_id":          {"Personal info", "User IDs", "Identifiers", "User ID", "UserID"},
+	"device_id":        {"Device or other IDs", "Device or other IDs", "Identifiers", "Device ID", "DeviceID"},
+	"contacts":         {"Contacts", "Contacts", "Contacts", "Contacts", "Contacts"},
+	"photos":           {"Photos and videos", "Photos", "User Content", "Photos or Videos", "PhotosorVideos"},
+	"videos":           {"Photos and videos", "Videos", "User Content", "Photos or Videos", "PhotosorVideos"},
+	"audio":            {"Audio", "Voice or sound recordings", "User Content", "Audio Data", "AudioData"},
+	"calendar":         {"Calendar", "Calendar events", "User Content", "Other User Content", "OtherUserContent"},
+	"sms":              {"Messages", "SMS or MMS", "User Content", "Emails or Text Messages", "EmailsOrTextMessages"},
+	"health":           {"Health and fitness", "Health info", "Health & Fitness", "Health", "Health"},
+	"fitness":          {"Health and fitness", "Fitness info", "Health & Fitness", "Fitness", "Fitness"},
+	"payment_info":     {"Financial info", "User payment info", "Financial Info", "Payment Info", "PaymentInfo"},
+	"purchase_history": {"Financial info", "Purchase history", "Purchases", "Purchase History", "PurchaseHistory"},
+	"app_interactions": {"App activity", "App interactions", "Usage Data", "Product Interaction", "ProductInteraction"},
+	"advertising_data": {"", "", "Usage Data", "Advertising Data", "AdvertisingData"},
+	"installed_apps":   {"App activity", "Installed apps", "", "", ""},
+	"files":            {"Files and docs", "Files and docs", "User Content", "Other User Content", "OtherUserContent"},
+	"crash_logs":       {"App info and performance", "Crash logs", "Diagnostics", "Crash Data", "CrashData"},
+	"diagnostics":      {"App info and performance", "Diagnostics", "Diagnostics", "Performance Data", "PerformanceData"},
+}
+
+// Purposes use one vocabulary internally and are renamed per form.
+const (
+	purposeFunctionality   = "functionality"
+	purposeAnalytics       = "analytics"
+	purposeAdvertising     = "advertising"
+	purposeAccount         = "account"
+	purposePersonalization = "personalization"
+)
+
+var playPurposes = map[string]string{
+	purposeFunctionality:   "App functionality",
+	purposeAnalytics:       "Analytics",
+	purposeAdvertising:     "Advertising or marketing",
+	purposeAccount:         "Account management",
+	purposePersonalization: "Personalization",
+}
+
+var applePurposes = map[string]string{
+	purposeFunctionality:   "App Functionality",
+	purposeAnalytics:       "Analytics",
+	purposeAdvertising:     "Third-Party Advertising",
+	purposeAccount:         "App Functionality",
+	purposePersonalization: "Product Personalization",
+}
+
+// permissionTypes maps Android permissions to the data they expose.
+var permissionTypes = map[string][]string{
+	"ACCESS_FINE_LOCATION":       {"precise_location"},
+	"ACCESS_COARSE_LOCATION":     {"approx_location"},
+	"ACCESS_BACKGROUND_LOCATION": {"precise_location"},
+	"READ_CONTACTS":              {"contacts"},
+	"GET_ACCOUNTS":               {"email"},
+	"CAMERA":                     {"photos", "videos"},
+	"RECORD_AUDIO":               {"audio"},
+	"READ_CALENDAR":              {"calendar"},
+	"READ_SMS":                   {"sms"},
+	"RECEIVE_SMS":                {"sms"},
+	"BODY_SENSORS":               {"health"},
+	"ACTIVITY_RECOGNITION":       {"fitness"},
+	"READ_MEDIA_IMAGES":          {"photos"},
+	"READ_MEDIA_VIDEO":           {"videos"},
+	"READ_PHONE_NUMBERS":         {"phone"},
+	"QUERY_ALL_PACKAGES":         {"installed_apps"},
+	"READ_EXTERNAL_STORAGE":      {"files"},
+	"MANAGE_EXTERNAL_STORAGE":    {"files"},
+}
+
+// purposeKeyTypes maps Info.plist usage description keys to data types.
+var purposeKeyTypes = map[string][]string{
+	"NSLocationWhenInUseUsageDescription":          {"precise_location"},
+	"NSLocationAlwaysAndWhenInUseUsageDescription": {"precise_location"},
+	"NSContactsUsageDescription":                   {"contacts"},
+	"NSCameraUsageDescription":                     {"photos", "videos"},
+	"NSMicrophoneUsageDescription":                 {"audio"},
+	"NSPhotoLibraryUsageDescription":               {"photos", "videos"},
+	"NSCalendarsUsageDescription":                  {"calendar"},
+	"NSCalendarsFullAccessUsageDescription":        {"calendar"},
+	"NSHealthShareUsageDescription":                {"health"},
+	"NSMotionUsageDescription":                     {"fitness"},
+}
+
+// sdkProfile describes the data a third-party SDK collects. Shared means the
+// data goes to the SDK vendor for its own use, not as a service provider.
+type sdkProfile struct {
+	Name     string
+	Patterns []string
+	Types    []string
+	Purpose  string
+	Shared   bool
+	Tracking bool
+}
+
+var sdkProfiles = []sdkProfile{
+	{Name: "Google Analytics for Firebase", Patterns: []string{"FirebaseAnalytics", "GoogleAppMeasurement", "firebase-analytics", "firebase_analytics", "@react-native-firebase/analytics"},
+		Types: []string{"app_interactions", "device_id"}, Purpose: purposeAnalytics},
+	{Name: "Firebase Crashlytics", Patterns: []string{"FirebaseCrashlytics", "firebase-crashlytics", "firebase_crashlytics", "@react-native-firebase/crashlytics"},
+		Types: []string{"crash_logs", "diagnostics"}, Purpose: purposeAnalytics},
+	{Name: "Sentry", Patterns: []string{"Sentry", "sentry-android", "io.sentry", "@sentry/react-native", "sentry_flutter"},
+		Types: []string{"crash_logs", "diagnostics"}, Purpose: purposeAnalytics},
+	{Name: "Bugsnag", Patterns: []string{"Bugsnag", "bugsnag-android", "@bugsnag/react-native"},
+		Types: []string{"crash_logs", "diagnostics"}, Purpose: purposeAnalytics},
+	{Name: "Mixpanel", Patterns: []string{"Mixpanel", "mixpanel-android", "mixpanel-react-native", "mixpanel_flutter"},
+		Types: []string{"app_interactions", "user_id", "device_id"}, Purpose: purposeAnalytics},
+	{Name: "Amplitude", Patterns: []string{"Amplitude", "amplitude-android", "@amplitude/", "amplitude_flutter"},
+		Types: []string{"app_interactions", "user_id", "device_id"}, Purpose: purposeAnalytics},
+	{Name: "Segment", Patterns: []string{"Segment", "analytics-android", "@segment/analytics-react-native"},
+		Types: []string{"app_interactions", "user_id", "device_id"}, Purpose: purposeAnalytics},
+	{Name: "Facebook SDK", Patterns: []string{"FBSDKCoreKit", "FacebookCore", "facebook-android-sdk", "facebook-core", "react-native-fbsdk", "react-native-fbsdk-next", "flutter_facebook_auth", "facebook_app_events"},
+		Types: []string{"device_id", "app_interactions"}, Purpose: purposeAdvertising, Shared: true, Tracking: true},
+	{Name: "Google Mobile Ads", Patterns: []string{"Google-Mobile-Ads-SDK", "GoogleMobileAds", "play-services-ads", "react-native-google-mobile-ads", "google_mobile_ads"},
+		Types: []string{"device_id", "app_interactions", "advertising_data", "diagnostics"}, Purpose: purposeAdvertising, Shared: true, Tracking: true},
+	{Name: "AppsFlyer", Patterns: []string{"AppsFlyer", "appsflyer", "appsflyer_sdk", "react-native-appsflyer"},
+		Types: []string{"device_id", "app_interactions"}, Purpose: purposeAdvertising, Shared: true, Tracking: true},
+	{Name: "Adjust", Patterns: []string{"Adjust", "com.adjust.sdk", "react-native-adjust", "adjust_sdk"},
+		Types: []string{"device_id", "app_interactions"}, Purpose: purposeAdvertising, Shared: true, Tracking: true},
+	{Name: "Branch", Patterns: []string{"BranchSDK", "io.branch.sdk", "react-native-branch", "flutter_branch_sdk"},
+		Types: []string{"device_id", "app_interactions"}, Purpose: purposeAdvertising, Shared: true, Tracking: true},
+	{Name: "Stripe", Patterns: []string{"Stripe", "stripe-android", "@stripe/stripe-react-native", "flutter_stripe"},
+		Types: []string{"payment_info", "purchase_history"}, Purpose: purposeFunctionality},
+	{Name: "Braintree", Patterns: []string{"Braintree", "braintree", "react-native-braintree"},
+		Types: []string{"payment_info"}, Purpose: purposeFunctionality},
+	{Name: "Google Sign-In", Patterns: []string{"GoogleSignIn", "play-services-auth", "@react-native-google-signin", "google_sign_in"},
+		Types: []string{"email", "name", "user_id"}, Purpose: purposeAccount},
+	{Name: "Firebase Authentication", Patterns: []string{"FirebaseAuth", "firebase-auth", "firebase_auth", "@react-native-firebase/auth"},
+		Types: []string{"email", "user_id"}, Purpose: purposeAccount},
+	{Name: "OneSignal", Patterns: []string{"OneSignal", "onesignal", "react-native-onesignal", "onesignal_flutter"},
+		Types: []string{"device_id"}, Purpose: purposeFunctionality},
+	{Name: "Firebase Cloud Messaging", Patterns: []string{"FirebaseMessaging", "firebase-messaging", "firebase_messaging", "@react-native-firebase/messaging"},
+		Types: []string{"device_id"}, Purpose: purposeFunctionality},
+}
+
+// dependencyManifests declare dependencies without resolving them, so the
+// sbom inventory does not cover them. They are searched for SDK patterns.
+var dependencyManifests = map[string]bool{
+	"build.gradle":     true,
+	"build.gradle.kts": true,
+	"package.json":     true,
+	"pubspec.yaml":     true,
+}
@@ -0,0 +1,92 @@
+package labels
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+var Formats = []string{"markdown", "json"}
+
+func Format(d *Draft, format string) ([]byte, error) {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case "markdown", "md":
+		return []byte(formatMarkdown(d)), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+func formatMarkdown(d *Draft) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# Privacy labels: %s\n\n", d.Project))
+	sb.WriteString("Draft generated from permissions, purpose strings, SDKs and the privacy manifest. ")
+	sb.WriteString("Check every item before copying it into Play Console or App Store Connect.\n\n")
+
+	if len(d.SDKs) > 0 {
+		sb.WriteString(fmt.Sprintf("Detected SDKs: %s\n\n", strings.Join(d.SDKs, ", ")))
+	}
+
+	if len(d.Inconsistencies) > 0 {
+		sb.WriteString("## Inconsistencies\n\n")
+		sb.WriteString("| Severity | Form | Issue |\n")
+		sb.WriteString("|---|---|---|\n")
+		for _, issue := range d.Inconsistencies {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", issue.Severity, issue.Form, mdEscape(issue.Message)))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Google Play Data Safety\n\n")
+	sb.WriteString(fmt.Sprintf("- [ ] Collects data: **%s**\n", yesNo(d.DataSafety.CollectsData)))
+	sb.WriteString(fmt.Sprintf("- [ ] Shares data with third parties: **%s**\n\n", yesNo(d.DataSafety.SharesData)))
+	if len(d.DataSafety.DataTypes) > 0 {
+		sb.WriteString("| | Category | Data type | Shared | Purposes | Evidence |\n")
+		sb.WriteString("|---|---|---|---|---|---|\n")
+		for _, e := range d.DataSafety.DataTypes {
+			sb.WriteString(fmt.Sprintf("| [ ] | %s | %s | %s | %s | %s |\n",
+				e.Category, e.Type, yesNo(e.Shared), strings.Join(e.Purposes, ", "), mdEscape(strings.Join(e.Evidence, "; "))))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## App Store privacy label\n\n")
+	sb.WriteString(fmt.Sprintf("- [ ] Data used to track you: **%s**\n\n", yesNo(d.PrivacyLabel.Tracking)))
+	if len(d.PrivacyLabel.DataTypes) > 0 {
+		sb.WriteString("| | Category | Data type | Linked to user | Tracking | Purposes | Evidence |\n")
+		sb.WriteString("|---|---|---|---|---|---|---|\n")
+		for _, e := range d.PrivacyLabel.DataTypes {
+			sb.WriteString(fmt.Sprintf("| [ ] | %s | %s | %s | %s | %s | %s |\n",
+				e.Category, e.Type, yesNo(e.LinkedToUser), yesNo(e.Tracking), strings.Join(e.Purposes, ", "), mdEscape(strings.Join(e.Evidence, "; "))))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(d.Review) > 0 {
+		sb.WriteString("## Before submitting\n\n")
+		for _, item := range d.Review {
+			sb.WriteString(fmt.Sprintf("- [ ] %s\n", item))
+		}
+	}
+
+	return sb.String()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
+
+func mdEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,443 @@
+// Package labels drafts the Google Play Data Safety form and the App Store
+// privacy label from signals in a project: declared permissions and
+// purpose strings, third-party SDKs, the privacy manifest and scan findings.
+package labels
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/match"
+	"github.com/hha-nguyen/canopy-cli/internal/plist"
+	"github.com/hha-nguyen/canopy-cli/internal/preflight"
+	"github.com/hha-nguyen/canopy-cli/internal/sbom"
+	"github.com/hha-nguyen/canopy-cli/internal/strset"
+)
+
+const (
+	SeverityHigh   = "HIGH"
+	SeverityMedium = "MEDIUM"
+	SeverityLow    = "LOW"
+)
+
+type Draft struct {
+	GeneratedAt     time.Time    `json:"generated_at"`
+	Project         string       `json:"project"`
+	DataSafety      DataSafety   `json:"play_data_safety"`
+	PrivacyLabel    PrivacyLabel `json:"app_store_privacy_label"`
+	SDKs            []string     `json:"sdks,omitempty"`
+	Inconsistencies []Issue      `json:"inconsistencies,omitempty"`
+	Review          []string     `json:"review,omitempty"`
+}
+
+type DataSafety struct {
+	CollectsData bool        `json:"collects_data"`
+	SharesData   bool        `json:"shares_data"`
+	DataTypes    []PlayEntry `json:"data_types"`
+}
+
+type PlayEntry struct {
+	Category string   `json:"category"`
+	Type     string   `json:"type"`
+	Shared   bool     `json:"shared"`
+	Purposes []string `json:"purposes"`
+	Evidence []string `json:"evidence"`
+}
+
+type PrivacyLabel struct {
+	Tracking  bool         `json:"tracking"`
+	DataTypes []AppleEntry `json:"data_types"`
+}
+
+type AppleEntry struct {
+	Category     string   `json:"category"`
+	Type         string   `json:"type"`
+	LinkedToUser bool     `json:"linked_to_user"`
+	Tracking     bool     `json:"used_for_tracking"`
+	Purposes     []string `json:"purposes"`
+	Evidence     []string `json:"evidence"`
+}
+
+type Issue struct {
+	Severity string `json:"severity"`
+	Form     string `json:"form"`
+	Message  string `json:"message"`
+}
+
+// signal is one piece of evidence that the app handles a data type.
+type signal struct {
+	Type     string
+	Purpose  string
+	Shared   bool
+	Tracking bool
+	Evidence string
+}
+
+// Build drafts both forms for a project. Findings from a scan add their
+// permission, purpose key and SDK evidence. declared, when not nil, is a
+// previous draft holding what the store listings currently declare.
+func Build(p *preflight.Project, findings []api.Finding, declared *Draft) *Draft {
+	d := &Draft{GeneratedAt: time.Now().UTC(), Project: path.Base(p.Root)}
+
+	var signals []signal
+	permissions := p.AndroidPermissions()
+	keys := p.PurposeKeys()
+	sdks := detectSDKs(p)
+
+	for _, f := range findings {
+		if perm, _ := f.Evidence["permission"].(string); perm != "" {
+			if _, ok := permissions[perm]; !ok {
+				permissions[perm] = f.FilePath
+			}
+		}
+		if key, _ := f.Evidence["key"].(string); strings.HasSuffix(key, "UsageDescription") {
+			if _, ok := keys[key]; !ok {
+				keys[key] = f.FilePath
+			}
+		}
+		if sdk, _ := f.Evidence["sdk"].(string); sdk != "" {
+			if profile, ok := matchSDK(sdk); ok {
+				if _, seen := sdks[profile.Name]; !seen {
+					sdks[profile.Name] = sdkMatch{Profile: profile, File: f.FilePath}
+				}
+			}
+		}
+	}
+
+	for _, perm := range sortedKeys(permissions) {
+		for _, t := range permissionTypes[perm] {
+			signals = append(signals, signal{Type: t, Purpose: purposeFunctionality,
+				Evidence: fmt.Sprintf("%s declared in %s", perm, permissions[perm])})
+		}
+	}
+	for _, key := range sortedKeys(keys) {
+		for _, t := range purposeKeyTypes[key] {
+			signals = append(signals, signal{Type: t, Purpose: purposeFunctionality,
+				Evidence: fmt.Sprintf("%s in %s", key, keys[key])})
+		}
+	}
+
+	tracking := false
+	for _, name := range sortedKeys(sdks) {
+		m := sdks[name]
+		d.SDKs = append(d.SDKs, name)
+		tracking = tracking || m.Profile.Tracking
+		for _, t := range m.Profile.Types {
+			signals = append(signals, signal{Type: t, Purpose: m.Profile.Purpose, Shared: m.Profile.Shared, Tracking: m.Profile.Tracking,
+				Evidence: fmt.Sprintf("%s SDK (%s)", name, m.File)})
+		}
+	}
+
+	d.DataSafety, d.PrivacyLabel = buildForms(signals)
+	d.PrivacyLabel.Tracking = tracking
+
+	manifest := readPrivacyManifest(p)
+	d.Inconsistencies = append(d.Inconsistencies, manifestIssues(d, manifest, keys, sdks)...)
+	if declared != nil {
+		d.Inconsistencies = append(d.Inconsistencies, declaredIssues(d, declared)...)
+	}
+	sort.SliceStable(d.Inconsistencies, func(i, j int) bool {
+		return api.SeverityRank(d.Inconsistencies[i].Severity) > api.SeverityRank(d.Inconsistencies[j].Severity)
+	})
+
+	if len(permissions)+len(keys) > 0 {
+		d.Review = append(d.Review, "Permissions and purpose strings only show the app can access the data. Remove types that never leave the device; on-device processing is not collection.")
+	}
+	d.Review = append(d.Review,
+		"Answer per data type whether collection is optional and whether data is processed ephemerally.",
+		"Confirm data is encrypted in transit and whether users can request deletion.",
+		"Add data the backend collects directly (account sign-up, support forms, purchases) that no SDK or permission reveals.",
+	)
+	if d.DataSafety.SharesData {
+		d.Review = append(d.Review, "Shared entries come from SDKs that use data for their own purposes; check each vendor's Data Safety guidance.")
+	}
+
+	return d
+}
+
+func buildForms(signals []signal) (DataSafety, PrivacyLabel) {
+	play := make(map[string]*PlayEntry)
+	apple := make(map[string]*AppleEntry)
+	var playOrder, appleOrder []string
+
+	for _, s := range signals {
+		dt := dataTypes[s.Type]
+
+		if dt.PlayType != "" {
+			key := dt.PlayCategory + "/" + dt.PlayType
+			e, ok := play[key]
+			if !ok {
+				e = &PlayEntry{Category: dt.PlayCategory, Type: dt.PlayType}
+				play[key] = e
+				playOrder = append(playOrder, key)
+			}
+			e.Shared = e.Shared || s.Shared
+			e.Purposes = strset.Add(e.Purposes, playPurposes[s.Purpose])
+			e.Evidence = strset.Add(e.Evidence, s.Evidence)
+		}
+
+		if dt.AppleType != "" {
+			key := dt.AppleCategory + "/" + dt.AppleType
+			e, ok := apple[key]
+			if !ok {
+				e = &AppleEntry{Category: dt.AppleCategory, Type: dt.AppleType}
+				apple[key] = e
+				appleOrder = append(appleOrder, key)
+			}
+			e.Tracking = e.Tracking || s.Tracking
+			e.LinkedToUser = e.LinkedToUser || s.Type == "user_id" || s.Type == "email" || s.Type == "name" || s.Purpose == purposeAccount
+			e.Purposes = strset.Add(e.Purposes, applePurposes[s.Purpose])
+			e.Evidence = strset.Add(e.Evidence, s.Evidence)
+		}
+	}
+
+	sort.Strings(playOrder)
+	sort.Strings(appleOrder)
+
+	var ds DataSafety
+	for _, key := range playOrder {
+		ds.DataTypes = append(ds.DataTypes, *play[key])
+		ds.SharesData = ds.SharesData || play[key].Shared
+	}
+	ds.CollectsData = len(ds.DataTypes) > 0
+
+	var pl PrivacyLabel
+	for _, key := range appleOrder {
+		pl.DataTypes = append(pl.DataTypes, *apple[key])
+	}
+	return ds, pl
+}
+
+// privacyManifest is the part of PrivacyInfo.xcprivacy the labels compare
+// against.
+type privacyManifest struct {
+	File     string
+	Tracking bool
+	Types    map[string]bool
+}
+
+func readPrivacyManifest(p *preflight.Project) *privacyManifest {
+	for _, rel := range p.PrivacyManifests() {
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		dict, err := plist.DecodeDict(data)
+		if err != nil {
+			continue
+		}
+
+		m := &privacyManifest{File: rel, Types: make(map[string]bool)}
+		m.Tracking, _ = dict["NSPrivacyTracking"].(bool)
+		items, _ := dict["NSPrivacyCollectedDataTypes"].([]interface{})
+		for _, item := range items {
+			entry, _ := item.(map[string]interface{})
+			if t, _ := entry["NSPrivacyCollectedDataType"].(string); t != "" {
+				m.Types[strings.TrimPrefix(t, "NSPrivacyCollectedDataType")] = true
+			}
+		}
+		return m
+	}
+	return nil
+}
+
+func manifestIssues(d *Draft, m *privacyManifest, keys map[string]string, sdks map[string]sdkMatch) []Issue {
+	var issues []Issue
+	_, attPrompt := keys["NSUserTrackingUsageDescription"]
+
+	var trackers []string
+	for _, name := range sortedKeys(sdks) {
+		if sdks[name].Profile.Tracking {
+			trackers = append(trackers, name)
+		}
+	}
+
+	if d.PrivacyLabel.Tracking && !attPrompt {
+		issues = append(issues, Issue{Severity: SeverityHigh, Form: "App Store",
+			Message: fmt.Sprintf("Tracking SDKs (%s) are present but no Info.plist declares NSUserTrackingUsageDescription, so the app cannot ask for tracking permission.", strings.Join(trackers, ", "))})
+	}
+
+	if m == nil {
+		return issues
+	}
+
+	if m.Tracking && !attPrompt {
+		issues = append(issues, Issue{Severity: SeverityHigh, Form: "App Store",
+			Message: fmt.Sprintf("%s sets NSPrivacyTracking but no Info.plist declares NSUserTrackingUsageDescription.", m.File)})
+	}
+	if !m.Tracking && (len(trackers) > 0 || attPrompt) {
+		reason := "the app requests tracking permission"
+		if len(trackers) > 0 {
+			reason = "tracking SDKs are present (" + strings.Join(trackers, ", ") + ")"
+		}
+		issues = append(issues, Issue{Severity: SeverityHigh, Form: "App Store",
+			Message: fmt.Sprintf("%s declares no tracking but %s.", m.File, reason)})
+	}
+
+	// Only SDKs are known to send data off the device; permissions and
+	// purpose strings merely suggest a type.
+	collected := make(map[string][]string)
+	detected := make(map[string]bool)
+	for _, e := range d.PrivacyLabel.DataTypes {
+		for _, t := range dataTypes {
+			if e.Type == t.AppleType && t.ManifestType != "" {
+				detected[t.ManifestType] = true
+			}
+		}
+	}
+	for _, name := range sortedKeys(sdks) {
+		for _, t := range sdks[name].Profile.Types {
+			if mt := dataTypes[t].ManifestType; mt != "" {
+				collected[mt] = strset.Add(collected[mt], name)
+			}
+		}
+	}
+	for _, t := range sortedKeys(collected) {
+		if !m.Types[t] {
+			issues = append(issues, Issue{Severity: SeverityMedium, Form: "App Store",
+				Message: fmt.Sprintf("%s collects %s but %s does not declare NSPrivacyCollectedDataType%s.", strings.Join(collected[t], ", "), t, m.File, t)})
+		}
+	}
+	for _, t := range sortedKeys(m.Types) {
+		if !detected[t] {
+			issues = append(issues, Issue{Severity: SeverityLow, Form: "App Store",
+				Message: fmt.Sprintf("%s declares NSPrivacyCollectedDataType%s but no permission, purpose string or SDK suggests it. Keep it only if the backend collects it.", m.File, t)})
+		}
+	}
+
+	return issues
+}
+
+// declaredIssues compares the draft with the declarations currently in the
+// store listings.
+func declaredIssues(d, declared *Draft) []Issue {
+	var issues []Issue
+
+	have := make(map[string]PlayEntry)
+	for _, e := range declared.DataSafety.DataTypes {
+		have[e.Category+"/"+e.Type] = e
+	}
+	want := make(map[string]bool)
+	for _, e := range d.DataSafety.DataTypes {
+		key := e.Category + "/" + e.Type
+		want[key] = true
+		prev, ok := have[key]
+		switch {
+		case !ok:
+			issues = append(issues, Issue{Severity: SeverityHigh, Form: "Google Play",
+				Message: fmt.Sprintf("%s is not declared but the app shows signs of collecting it (%s).", key, strings.Join(e.Evidence, "; "))})
+		case e.Shared && !prev.Shared:
+			issues = append(issues, Issue{Severity: SeverityHigh, Form: "Google Play",
+				Message: fmt.Sprintf("%s is declared as not shared but the draft marks it shared (%s).", key, strings.Join(e.Evidence, "; "))})
+		}
+	}
+	for _, key := range sortedKeys(have) {
+		if !want[key] {
+			issues = append(issues, Issue{Severity: SeverityLow, Form: "Google Play",
+				Message: fmt.Sprintf("%s is declared but nothing in the code suggests it. Keep it only if the backend collects it.", key)})
+		}
+	}
+
+	haveApple := make(map[string]AppleEntry)
+	for _, e := range declared.PrivacyLabel.DataTypes {
+		haveApple[e.Category+"/"+e.Type] = e
+	}
+	wantApple := make(map[string]bool)
+	for _, e := range d.PrivacyLabel.DataTypes {
+		key := e.Category + "/" + e.Type
+		wantApple[key] = true
+		prev, ok := haveApple[key]
+		switch {
+		case !ok:
+			issues = append(issues, Issue{Severity: SeverityHigh, Form: "App Store",
+				Message: fmt.Sprintf("%s is not declared but the app shows signs of collecting it (%s).", key, strings.Join(e.Evidence, "; "))})
+		case e.Tracking && !prev.Tracking:
+			issues = append(issues, Issue{Severity: SeverityHigh, Form: "App Store",
+				Message: fmt.Sprintf("%s is declared as not used for tracking but the draft marks it used for tracking (%s).", key, strings.Join(e.Evidence, "; "))})
+		}
+	}
+	for _, key := range sortedKeys(haveApple) {
+		if !wantApple[key] {
+			issues = append(issues, Issue{Severity: SeverityLow, Form: "App Store",
+				Message: fmt.Sprintf("%s is declared but nothing in the code suggests it. Keep it only if the backend collects it.", key)})
+		}
+	}
+	if d.PrivacyLabel.Tracking && !declared.PrivacyLabel.Tracking {
+		issues = append(issues, Issue{Severity: SeverityHigh, Form: "App Store",
+			Message: "The label declares no tracking but tracking SDKs are present."})
+	}
+
+	return issues
+}
+
+type sdkMatch struct {
+	Profile sdkProfile
+	File    string
+}
+
+// detectSDKs looks for known SDKs among the components of the project's
+// dependency inventory and, since build scripts and package manifests are
+// not part of it, by identifier in those files. Results are keyed by SDK
+// name.
+func detectSDKs(p *preflight.Project) map[string]sdkMatch {
+	found := make(map[string]sdkMatch)
+
+	for _, c := range sbom.CollectFiles(p.Files, p.Read).Components {
+		coordinate := c.Name
+		if c.Group != "" {
+			coordinate = c.Group + ":" + c.Name
+		}
+		if profile, ok := matchSDK(coordinate); ok {
+			if _, seen := found[profile.Name]; !seen {
+				found[profile.Name] = sdkMatch{Profile: profile, File: c.File}
+			}
+		}
+	}
+
+	for _, rel := range p.Find(func(rel string) bool { return dependencyManifests[path.Base(rel)] }) {
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		content := string(data)
+		for _, profile := range sdkProfiles {
+			if _, seen := found[profile.Name]; seen {
+				continue
+			}
+			for _, pattern := range profile.Patterns {
+				if match.Identifier(content, pattern) {
+					found[profile.Name] = sdkMatch{Profile: profile, File: rel}
+					break
+				}
+			}
+		}
+	}
+	return found
+}
+
+func matchSDK(name string) (sdkProfile, bool) {
+	for _, profile := range sdkProfiles {
+		if strings.EqualFold(profile.Name, name) {
+			return profile, true
+		}
+		for _, pattern := range profile.Patterns {
+			if match.Identifier(name, pattern) {
+				return profile, true
+			}
+		}
+	}
+	return sdkProfile{}, false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,31 @@
+package match
+
+import "strings"
+
+// Identifier reports whether s mentions id as a whole identifier, so
+// "Adjust" does not match "AdjustableView" or "adjust_sdk". Letters, digits
+// and underscores are identifier characters; an id that starts or ends with
+// another character, such as "@amplitude/", needs no boundary on that side.
+func Identifier(s, id string) bool {
+	if id == "" {
+		return false
+	}
+	for start := 0; ; {
+		i := strings.Index(s[start:], id)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(id)
+		before := i == 0 || !isIdentChar(id[0]) || !isIdentChar(s[i-1])
+		after := end == len(s) || !isIdentChar(id[len(id)-1]) || !isIdentChar(s[end])
+		if before && after {
+			return true
+		}
+		start = i + 1
+	}
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
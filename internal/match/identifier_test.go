@@ -0,0 +1,28 @@
+package match
+
+import "testing"
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		s    string
+		id   string
+		want bool
+	}{
+		{"pod 'Adjust', '~> 4.0'", "Adjust", true},
+		{"AdjustableView", "Adjust", false},
+		{"adjust_sdk: ^4.0.0", "adjust", false},
+		{"adjust_sdk: ^4.0.0", "adjust_sdk", true},
+		{"MyAdjust", "Adjust", false},
+		{"com.adjust.sdk:adjust-android", "com.adjust.sdk", true},
+		{"\"@amplitude/analytics-react-native\": \"1.0.0\"", "@amplitude/", true},
+		{"Sentry Sentry2 Sentry", "Sentry", true},
+		{"Sentry2", "Sentry", false},
+		{"anything", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := Identifier(tt.s, tt.id); got != tt.want {
+			t.Errorf("Identifier(%q, %q) = %v, want %v", tt.s, tt.id, got, tt.want)
+		}
+	}
+}
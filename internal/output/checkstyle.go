@@ -0,0 +1,118 @@
+package output
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/fingerprint"
+)
+
+const projectLevelPath = "."
+
+type CheckstyleFormatter struct{}
+
+func NewCheckstyleFormatter() *CheckstyleFormatter {
+	return &CheckstyleFormatter{}
+}
+
+type CheckstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []CheckstyleFile `xml:"file"`
+}
+
+type CheckstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []CheckstyleError `xml:"error"`
+}
+
+type CheckstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func (f *CheckstyleFormatter) Format(result *api.ScanResult) ([]byte, error) {
+	report := CheckstyleReport{Version: "8.0"}
+	fileIndex := make(map[string]int)
+
+	findings := result.Findings
+	if result.Baseline != nil {
+		findings = append(append([]api.Finding{}, findings...), result.Baseline.FixedFindings...)
+	}
+
+	for _, finding := range findings {
+		path := fingerprint.NormalizePath(finding.FilePath)
+		if path == "" {
+			path = projectLevelPath
+		}
+
+		idx, ok := fileIndex[path]
+		if !ok {
+			idx = len(report.Files)
+			fileIndex[path] = idx
+			report.Files = append(report.Files, CheckstyleFile{Name: path})
+		}
+
+		report.Files[idx].Errors = append(report.Files[idx].Errors, CheckstyleError{
+			Line:     evidenceInt(finding.Evidence, "start_line", "line", "line_number"),
+			Column:   evidenceInt(finding.Evidence, "start_column", "column"),
+			Severity: checkstyleSeverity(finding),
+			Message:  checkstyleMessage(finding),
+			Source:   "canopy." + finding.RuleCode,
+		})
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+func checkstyleMessage(finding api.Finding) string {
+	msg := strings.ToUpper(finding.Severity) + ": " + finding.Message
+	if finding.RuleName != "" {
+		msg = finding.RuleName + " - " + msg
+	}
+	return statusPrefix(finding) + msg
+}
+
+// checkstyleSeverity reports accepted (suppressed or baselined) and fixed
+// findings as "ignore" so that tools failing on errors do not fail on them.
+func checkstyleSeverity(finding api.Finding) string {
+	if finding.Accepted() || finding.BaselineState == api.BaselineAbsent {
+		return "ignore"
+	}
+	return mapSeverityToCheckstyle(finding.Severity)
+}
+
+func mapSeverityToCheckstyle(severity string) string {
+	switch strings.ToUpper(severity) {
+	case "BLOCKER", "HIGH":
+		return "error"
+	case "MEDIUM":
+		return "warning"
+	case "LOW":
+		return "info"
+	default:
+		return "ignore"
+	}
+}
+
+func statusPrefix(finding api.Finding) string {
+	switch {
+	case finding.Suppressed():
+		return "[suppressed] "
+	case finding.BaselineState == api.BaselineUnchanged:
+		return "[baseline] "
+	case finding.BaselineState == api.BaselineAbsent:
+		return "[fixed] "
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,87 @@
+package output
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+func TestCheckstyleFormatter(t *testing.T) {
+	result := &api.ScanResult{
+		Findings: []api.Finding{
+			{
+				RuleCode: "GPL-SDK-001",
+				RuleName: "Target SDK",
+				Severity: "HIGH",
+				Message:  "targetSdk is 33",
+				FilePath: `app\build.gradle`,
+				Evidence: map[string]interface{}{"line": float64(12), "column": float64(9)},
+			},
+			{
+				RuleCode: "GPL-MAN-002",
+				Severity: "MEDIUM",
+				Message:  "exported is not set",
+				FilePath: "./app/build.gradle",
+			},
+			{
+				RuleCode:      "APL-PLIST-004",
+				RuleName:      "Vague purpose string",
+				Severity:      "LOW",
+				Message:       "purpose string is vague",
+				BaselineState: api.BaselineUnchanged,
+			},
+			{
+				RuleCode:     "APL-PRIV-001",
+				Severity:     "BLOCKER",
+				Message:      "missing reason",
+				Suppressions: []api.Suppression{{Kind: "inline", Justification: "reviewed"}},
+			},
+		},
+		Baseline: &api.BaselineResult{
+			FixedFindings: []api.Finding{{
+				RuleCode:      "GPL-SDK-002",
+				RuleName:      "Min SDK",
+				Severity:      "HIGH",
+				Message:       "minSdk is 19",
+				FilePath:      "app/build.gradle",
+				BaselineState: api.BaselineAbsent,
+			}},
+		},
+	}
+
+	data, err := NewCheckstyleFormatter().Format(result)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var got CheckstyleReport
+	if err := xml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("xml.Unmarshal() error = %v", err)
+	}
+
+	want := []CheckstyleFile{
+		{
+			Name: "app/build.gradle",
+			Errors: []CheckstyleError{
+				{Line: 12, Column: 9, Severity: "error", Message: "Target SDK - HIGH: targetSdk is 33", Source: "canopy.GPL-SDK-001"},
+				{Severity: "warning", Message: "MEDIUM: exported is not set", Source: "canopy.GPL-MAN-002"},
+				{Severity: "ignore", Message: "[fixed] Min SDK - HIGH: minSdk is 19", Source: "canopy.GPL-SDK-002"},
+			},
+		},
+		{
+			Name: ".",
+			Errors: []CheckstyleError{
+				{Severity: "ignore", Message: "[baseline] Vague purpose string - LOW: purpose string is vague", Source: "canopy.APL-PLIST-004"},
+				{Severity: "ignore", Message: "[suppressed] BLOCKER: missing reason", Source: "canopy.APL-PRIV-001"},
+			},
+		},
+	}
+	if got.Version != "8.0" {
+		t.Errorf("version = %q, want 8.0", got.Version)
+	}
+	if !reflect.DeepEqual(got.Files, want) {
+		t.Errorf("files =\n%+v\nwant\n%+v", got.Files, want)
+	}
+}
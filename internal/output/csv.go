@@ -0,0 +1,221 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+var CSVColumns = []string{
+	"scan_id",
+	"app",
+	"platform",
+	"severity",
+	"rule_code",
+	"rule_name",
+	"file_path",
+	"message",
+	"remediation_action",
+	"remediation_template",
+	"docs_url",
+	"evidence",
+	"baseline_state",
+	"suppression",
+}
+
+var DefaultCSVColumns = []string{
+	"scan_id",
+	"platform",
+	"severity",
+	"rule_code",
+	"rule_name",
+	"file_path",
+	"message",
+	"remediation_action",
+	"docs_url",
+	"evidence",
+	"baseline_state",
+	"suppression",
+}
+
+type CSVFormatter struct {
+	delimiter rune
+	columns   []string
+}
+
+func NewCSVFormatter(columns []string) *CSVFormatter {
+	return newDelimitedFormatter(',', columns)
+}
+
+func NewTSVFormatter(columns []string) *CSVFormatter {
+	return newDelimitedFormatter('\t', columns)
+}
+
+func newDelimitedFormatter(delimiter rune, columns []string) *CSVFormatter {
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+	return &CSVFormatter{delimiter: delimiter, columns: columns}
+}
+
+func ValidateCSVColumns(columns []string) error {
+	for _, column := range columns {
+		if !isCSVColumn(column) {
+			return fmt.Errorf("unknown column %q (available: %s)", column, strings.Join(CSVColumns, ", "))
+		}
+	}
+	return nil
+}
+
+func isCSVColumn(column string) bool {
+	for _, c := range CSVColumns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *CSVFormatter) Format(result *api.ScanResult) ([]byte, error) {
+	if err := ValidateCSVColumns(f.columns); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = f.delimiter
+	w.UseCRLF = f.delimiter == ','
+
+	if err := w.Write(f.columns); err != nil {
+		return nil, err
+	}
+
+	findings := result.Findings
+	if result.Baseline != nil && f.hasColumn("baseline_state") {
+		findings = append(append([]api.Finding{}, findings...), result.Baseline.FixedFindings...)
+	}
+
+	for _, finding := range findings {
+		row := make([]string, len(f.columns))
+		for i, column := range f.columns {
+			row[i] = escapeFormula(csvValue(result, finding, column))
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (f *CSVFormatter) hasColumn(column string) bool {
+	for _, c := range f.columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+func csvValue(result *api.ScanResult, finding api.Finding, column string) string {
+	switch column {
+	case "scan_id":
+		return result.ScanIDFor(finding)
+	case "app":
+		return finding.App
+	case "platform":
+		if finding.Platform != "" {
+			return finding.Platform
+		}
+		return result.Platform
+	case "severity":
+		return strings.ToUpper(finding.Severity)
+	case "rule_code":
+		return finding.RuleCode
+	case "rule_name":
+		return finding.RuleName
+	case "file_path":
+		return finding.FilePath
+	case "message":
+		return finding.Message
+	case "remediation_action":
+		if finding.Remediation != nil {
+			return finding.Remediation.Action
+		}
+	case "remediation_template":
+		if finding.Remediation != nil {
+			return finding.Remediation.Template
+		}
+	case "docs_url":
+		return finding.DocsURL
+	case "evidence":
+		return flattenEvidence(finding.Evidence)
+	case "baseline_state":
+		return finding.BaselineState
+	case "suppression":
+		parts := make([]string, 0, len(finding.Suppressions))
+		for _, s := range finding.Suppressions {
+			parts = append(parts, s.Kind+": "+s.Justification)
+		}
+		return strings.Join(parts, "; ")
+	}
+	return ""
+}
+
+// escapeFormula keeps spreadsheets from evaluating cells taken from scanned
+// code as formulas by prefixing them with a quote.
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func flattenEvidence(evidence map[string]interface{}) string {
+	if len(evidence) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(evidence))
+	for k := range evidence {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+evidenceString(evidence[k]))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+func evidenceString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return fmt.Sprintf("%t", val)
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		return string(data)
+	}
+}
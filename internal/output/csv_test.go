@@ -0,0 +1,123 @@
+package output
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+func TestCSVFormatter(t *testing.T) {
+	result := &api.ScanResult{
+		ID:       "scan-1",
+		Platform: "BOTH",
+		Apps:     []api.AppResult{{Name: "mobile", ScanID: "scan-2"}},
+		Findings: []api.Finding{
+			{
+				RuleCode:    "GPL-SDK-001",
+				RuleName:    "Target SDK, too low",
+				Severity:    "high",
+				Platform:    "GOOGLE",
+				Message:     `targetSdk is "33"`,
+				FilePath:    "app/build.gradle",
+				Evidence:    map[string]interface{}{"target_sdk": float64(33), "required": true},
+				Remediation: &api.Remediation{Action: "update", Template: "targetSdk = 35"},
+			},
+			{
+				RuleCode:      "APL-PLIST-004",
+				RuleName:      "Vague purpose string",
+				Severity:      "LOW",
+				Message:       "=HYPERLINK(\"http://evil\")",
+				App:           "mobile",
+				BaselineState: api.BaselineUnchanged,
+				Suppressions:  []api.Suppression{{Kind: "inline", Justification: "reviewed"}},
+			},
+		},
+		Baseline: &api.BaselineResult{
+			FixedFindings: []api.Finding{{
+				RuleCode:      "APL-PRIV-001",
+				Severity:      "BLOCKER",
+				BaselineState: api.BaselineAbsent,
+			}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		formatter *CSVFormatter
+		want      string
+		wantErr   string
+	}{
+		{
+			name:      "selected columns",
+			formatter: NewCSVFormatter([]string{"scan_id", "platform", "severity", "rule_name", "message", "evidence", "remediation_template"}),
+			want: "scan_id,platform,severity,rule_name,message,evidence,remediation_template\r\n" +
+				"scan-1,GOOGLE,HIGH,\"Target SDK, too low\",\"targetSdk is \"\"33\"\"\",required=true; target_sdk=33,targetSdk = 35\r\n" +
+				"scan-2,BOTH,LOW,Vague purpose string,\"'=HYPERLINK(\"\"http://evil\"\")\",,\r\n",
+		},
+		{
+			name:      "fixed findings follow with the baseline column",
+			formatter: NewTSVFormatter([]string{"rule_code", "baseline_state", "suppression"}),
+			want: "rule_code\tbaseline_state\tsuppression\n" +
+				"GPL-SDK-001\t\t\n" +
+				"APL-PLIST-004\tunchanged\tinline: reviewed\n" +
+				"APL-PRIV-001\tabsent\t\n",
+		},
+		{
+			name:      "unknown column",
+			formatter: NewCSVFormatter([]string{"rule_code", "owner"}),
+			wantErr:   `unknown column "owner"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.formatter.Format(result)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Format() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Format() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVFormatterDefaultColumns(t *testing.T) {
+	f := NewCSVFormatter(nil)
+	if !reflect.DeepEqual(f.columns, DefaultCSVColumns) {
+		t.Errorf("columns = %v, want %v", f.columns, DefaultCSVColumns)
+	}
+	if err := ValidateCSVColumns(CSVColumns); err != nil {
+		t.Errorf("ValidateCSVColumns(CSVColumns) error = %v", err)
+	}
+}
+
+func TestEscapeFormula(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"plain", "plain"},
+		{"=1+1", "'=1+1"},
+		{"+cmd", "'+cmd"},
+		{"-2", "'-2"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tx", "'\tx"},
+		{"a=b", "a=b"},
+	}
+
+	for _, tt := range tests {
+		if got := escapeFormula(tt.value); got != tt.want {
+			t.Errorf("escapeFormula(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
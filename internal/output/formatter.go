@@ -10,13 +10,40 @@ type ProgressFormatter interface {
 	FormatProgress(percentage int, phase string) string
 }
 
-func NewFormatter(format string, noColor bool) Formatter {
+var SupportedFormats = []string{"text", "json", "sarif", "junit", "csv", "tsv", "checkstyle", "sonarqube"}
+
+func IsSupportedFormat(format string) bool {
+	for _, f := range SupportedFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+type Options struct {
+	NoColor     bool
+	ToolVersion string
+	Columns     []string
+}
+
+func NewFormatter(format string, opts Options) Formatter {
 	switch format {
 	case "json":
 		return NewJSONFormatter(false)
 	case "sarif":
-		return NewSARIFFormatter()
+		return NewSARIFFormatter(opts.ToolVersion)
+	case "junit":
+		return NewJUnitFormatter()
+	case "csv":
+		return NewCSVFormatter(opts.Columns)
+	case "tsv":
+		return NewTSVFormatter(opts.Columns)
+	case "checkstyle":
+		return NewCheckstyleFormatter()
+	case "sonarqube":
+		return NewSonarQubeFormatter()
 	default:
-		return NewTextFormatter(noColor)
+		return NewTextFormatter(opts.NoColor)
 	}
 }
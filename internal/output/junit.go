@@ -0,0 +1,154 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+type JUnitFormatter struct{}
+
+func NewJUnitFormatter() *JUnitFormatter {
+	return &JUnitFormatter{}
+}
+
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type JUnitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+func (f *JUnitFormatter) Format(result *api.ScanResult) ([]byte, error) {
+	duration := fmt.Sprintf("%.3f", float64(result.DurationMs)/1000)
+
+	suite := JUnitTestSuite{
+		Name: fmt.Sprintf("Canopy (%s)", formatPlatform(result.Platform)),
+		Time: duration,
+	}
+
+	for _, finding := range result.Findings {
+		testCase := JUnitTestCase{
+			Name:      junitTestName(finding),
+			ClassName: finding.RuleCode,
+			File:      finding.FilePath,
+		}
+
+		if finding.Suppressed() {
+			testCase.Skipped = &JUnitSkipped{Message: "Suppressed: " + finding.Suppressions[0].Justification}
+			suite.Skipped++
+		} else if finding.BaselineState == api.BaselineUnchanged {
+			testCase.Skipped = &JUnitSkipped{Message: "Accepted in baseline: " + finding.Message}
+			suite.Skipped++
+		} else if strings.EqualFold(finding.Severity, "INFO") {
+			testCase.Skipped = &JUnitSkipped{Message: finding.Message}
+			suite.Skipped++
+		} else {
+			testCase.Failure = &JUnitFailure{
+				Message: finding.Message,
+				Type:    strings.ToUpper(finding.Severity),
+				Text:    junitFailureText(finding),
+			}
+			suite.Failures++
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	// Findings fixed since the baseline are reported as passing test cases.
+	if result.Baseline != nil {
+		for _, finding := range result.Baseline.FixedFindings {
+			suite.TestCases = append(suite.TestCases, JUnitTestCase{
+				Name:      junitTestName(finding),
+				ClassName: finding.RuleCode,
+				File:      finding.FilePath,
+				SystemOut: "Fixed since baseline: " + finding.Message,
+			})
+		}
+	}
+
+	if len(suite.TestCases) == 0 {
+		suite.TestCases = append(suite.TestCases, JUnitTestCase{
+			Name:      "policy compliance",
+			ClassName: "canopy",
+		})
+	}
+	suite.Tests = len(suite.TestCases)
+
+	report := JUnitTestSuites{
+		Name:     "canopy",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     duration,
+		Suites:   []JUnitTestSuite{suite},
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+func junitTestName(finding api.Finding) string {
+	name := finding.RuleName
+	if name == "" {
+		name = finding.RuleCode
+	}
+	if finding.FilePath != "" {
+		name += " (" + finding.FilePath + ")"
+	}
+	return name
+}
+
+func junitFailureText(finding api.Finding) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s: %s - %s\n", strings.ToUpper(finding.Severity), finding.RuleCode, finding.RuleName))
+	sb.WriteString(finding.Message + "\n")
+
+	if finding.FilePath != "" {
+		sb.WriteString(fmt.Sprintf("File: %s\n", finding.FilePath))
+	}
+	if finding.Remediation != nil && finding.Remediation.Template != "" {
+		sb.WriteString(fmt.Sprintf("Fix: %s\n", finding.Remediation.Template))
+	}
+	if finding.DocsURL != "" {
+		sb.WriteString(fmt.Sprintf("Docs: %s\n", finding.DocsURL))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,191 @@
+package output
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+func TestJUnitFormatter(t *testing.T) {
+	tests := []struct {
+		name         string
+		findings     []api.Finding
+		wantTests    int
+		wantFailures int
+		wantSkipped  int
+		wantCase     JUnitTestCase
+	}{
+		{
+			name:      "no findings",
+			wantTests: 1,
+			wantCase:  JUnitTestCase{Name: "policy compliance", ClassName: "canopy"},
+		},
+		{
+			name: "failure",
+			findings: []api.Finding{{
+				RuleCode: "APL-PLIST-001",
+				RuleName: "Missing purpose string",
+				Severity: "high",
+				Message:  "NSCameraUsageDescription is missing",
+				FilePath: "ios/Runner/Info.plist",
+			}},
+			wantTests:    1,
+			wantFailures: 1,
+			wantCase: JUnitTestCase{
+				Name:      "Missing purpose string (ios/Runner/Info.plist)",
+				ClassName: "APL-PLIST-001",
+				File:      "ios/Runner/Info.plist",
+				Failure: &JUnitFailure{
+					Message: "NSCameraUsageDescription is missing",
+					Type:    "HIGH",
+				},
+			},
+		},
+		{
+			name: "suppressed",
+			findings: []api.Finding{{
+				RuleCode:     "APL-PRIV-001",
+				Severity:     "BLOCKER",
+				Message:      "missing reason",
+				Suppressions: []api.Suppression{{Kind: "inline", Justification: "handled upstream"}},
+			}},
+			wantTests:   1,
+			wantSkipped: 1,
+			wantCase: JUnitTestCase{
+				Name:      "APL-PRIV-001",
+				ClassName: "APL-PRIV-001",
+				Skipped:   &JUnitSkipped{Message: "Suppressed: handled upstream"},
+			},
+		},
+		{
+			name: "accepted in baseline",
+			findings: []api.Finding{{
+				RuleCode:      "GPL-SDK-001",
+				RuleName:      "Target SDK",
+				Severity:      "HIGH",
+				Message:       "targetSdk is 33",
+				BaselineState: api.BaselineUnchanged,
+			}},
+			wantTests:   1,
+			wantSkipped: 1,
+			wantCase: JUnitTestCase{
+				Name:      "Target SDK",
+				ClassName: "GPL-SDK-001",
+				Skipped:   &JUnitSkipped{Message: "Accepted in baseline: targetSdk is 33"},
+			},
+		},
+		{
+			name: "info is skipped",
+			findings: []api.Finding{{
+				RuleCode: "APL-INFO-001",
+				RuleName: "Note",
+				Severity: "info",
+				Message:  "for your information",
+			}},
+			wantTests:   1,
+			wantSkipped: 1,
+			wantCase: JUnitTestCase{
+				Name:      "Note",
+				ClassName: "APL-INFO-001",
+				Skipped:   &JUnitSkipped{Message: "for your information"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := NewJUnitFormatter().Format(&api.ScanResult{
+				Platform:   "APPLE",
+				DurationMs: 1500,
+				Findings:   tt.findings,
+			})
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if !strings.HasPrefix(string(data), xml.Header) {
+				t.Errorf("Format() output does not start with the XML header")
+			}
+
+			var got JUnitTestSuites
+			if err := xml.Unmarshal(data, &got); err != nil {
+				t.Fatalf("xml.Unmarshal() error = %v", err)
+			}
+			if got.Tests != tt.wantTests || got.Failures != tt.wantFailures || got.Time != "1.500" {
+				t.Errorf("testsuites tests=%d failures=%d time=%s, want %d, %d, 1.500",
+					got.Tests, got.Failures, got.Time, tt.wantTests, tt.wantFailures)
+			}
+			if len(got.Suites) != 1 {
+				t.Fatalf("got %d suites, want 1", len(got.Suites))
+			}
+			suite := got.Suites[0]
+			if suite.Name != "Canopy (Apple App Store)" {
+				t.Errorf("suite name = %q", suite.Name)
+			}
+			if suite.Skipped != tt.wantSkipped {
+				t.Errorf("suite skipped = %d, want %d", suite.Skipped, tt.wantSkipped)
+			}
+
+			tc := suite.TestCases[0]
+			if tc.Name != tt.wantCase.Name || tc.ClassName != tt.wantCase.ClassName || tc.File != tt.wantCase.File {
+				t.Errorf("testcase = %+v, want %+v", tc, tt.wantCase)
+			}
+			switch {
+			case tt.wantCase.Failure != nil:
+				if tc.Failure == nil || tc.Failure.Message != tt.wantCase.Failure.Message || tc.Failure.Type != tt.wantCase.Failure.Type {
+					t.Errorf("failure = %+v, want %+v", tc.Failure, tt.wantCase.Failure)
+				}
+				if tc.Failure != nil && !strings.Contains(tc.Failure.Text, "File: "+tt.wantCase.File) {
+					t.Errorf("failure text = %q, want file line", tc.Failure.Text)
+				}
+			case tt.wantCase.Skipped != nil:
+				if tc.Skipped == nil || *tc.Skipped != *tt.wantCase.Skipped {
+					t.Errorf("skipped = %+v, want %+v", tc.Skipped, tt.wantCase.Skipped)
+				}
+			default:
+				if tc.Failure != nil || tc.Skipped != nil {
+					t.Errorf("testcase = %+v, want passing", tc)
+				}
+			}
+		})
+	}
+}
+
+func TestJUnitFormatterFixedFindings(t *testing.T) {
+	data, err := NewJUnitFormatter().Format(&api.ScanResult{
+		Findings: []api.Finding{{RuleCode: "A-1", Severity: "HIGH", Message: "still there", BaselineState: api.BaselineNew}},
+		Baseline: &api.BaselineResult{
+			Fixed: 1,
+			FixedFindings: []api.Finding{{
+				RuleCode:      "APL-PLIST-001",
+				RuleName:      "Missing purpose string",
+				Severity:      "HIGH",
+				Message:       "NSCameraUsageDescription is missing",
+				FilePath:      "Info.plist",
+				BaselineState: api.BaselineAbsent,
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var got JUnitTestSuites
+	if err := xml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("xml.Unmarshal() error = %v", err)
+	}
+	if got.Tests != 2 || got.Failures != 1 {
+		t.Errorf("tests=%d failures=%d, want 2 and 1", got.Tests, got.Failures)
+	}
+	fixed := got.Suites[0].TestCases[1]
+	want := JUnitTestCase{
+		Name:      "Missing purpose string (Info.plist)",
+		ClassName: "APL-PLIST-001",
+		File:      "Info.plist",
+		SystemOut: "Fixed since baseline: NSCameraUsageDescription is missing",
+	}
+	if fixed != want {
+		t.Errorf("fixed testcase = %+v, want %+v", fixed, want)
+	}
+}
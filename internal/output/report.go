@@ -0,0 +1,39 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+const Stdout = "-"
+
+type Report struct {
+	Format string
+	Path   string
+}
+
+func (r Report) IsStdout() bool {
+	return r.Path == "" || r.Path == Stdout
+}
+
+func ParseReport(spec string) (Report, error) {
+	format, path, found := strings.Cut(spec, "=")
+	format = strings.ToLower(strings.TrimSpace(format))
+	path = strings.TrimSpace(path)
+
+	if format == "" {
+		return Report{}, fmt.Errorf("invalid report %q: expected format=path", spec)
+	}
+	if !IsSupportedFormat(format) {
+		return Report{}, fmt.Errorf("invalid report %q: unsupported format %q (supported: %s)",
+			spec, format, strings.Join(SupportedFormats, ", "))
+	}
+	if found && path == "" {
+		return Report{}, fmt.Errorf("invalid report %q: missing path", spec)
+	}
+	if !found {
+		path = Stdout
+	}
+
+	return Report{Format: format, Path: path}, nil
+}
@@ -0,0 +1,72 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    Report
+		wantErr string
+	}{
+		{
+			name: "format and path",
+			spec: "junit=reports/canopy.xml",
+			want: Report{Format: "junit", Path: "reports/canopy.xml"},
+		},
+		{
+			name: "format only writes to stdout",
+			spec: "sarif",
+			want: Report{Format: "sarif", Path: Stdout},
+		},
+		{
+			name: "normalizes format and trims spaces",
+			spec: " JSON = out.json ",
+			want: Report{Format: "json", Path: "out.json"},
+		},
+		{
+			name: "path may contain equals",
+			spec: "csv=a=b.csv",
+			want: Report{Format: "csv", Path: "a=b.csv"},
+		},
+		{
+			name:    "missing format",
+			spec:    "=out.xml",
+			wantErr: "expected format=path",
+		},
+		{
+			name:    "unsupported format",
+			spec:    "html=out.html",
+			wantErr: `unsupported format "html"`,
+		},
+		{
+			name:    "missing path",
+			spec:    "junit=",
+			wantErr: "missing path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReport(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseReport() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseReport() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseReport() = %+v, want %+v", got, tt.want)
+			}
+			if got.IsStdout() != (tt.want.Path == Stdout) {
+				t.Errorf("IsStdout() = %v for %q", got.IsStdout(), got.Path)
+			}
+		})
+	}
+}
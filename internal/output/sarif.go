@@ -2,17 +2,23 @@ package output
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/fingerprint"
 )
 
 const sarifSchema = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
 
-type SARIFFormatter struct{}
+type SARIFFormatter struct {
+	toolVersion string
+}
 
-func NewSARIFFormatter() *SARIFFormatter {
-	return &SARIFFormatter{}
+func NewSARIFFormatter(toolVersion string) *SARIFFormatter {
+	return &SARIFFormatter{toolVersion: toolVersion}
 }
 
 type SARIFReport struct {
@@ -22,8 +28,12 @@ type SARIFReport struct {
 }
 
 type SARIFRun struct {
-	Tool    SARIFTool     `json:"tool"`
-	Results []SARIFResult `json:"results"`
+	Tool                     SARIFTool                    `json:"tool"`
+	Invocations              []SARIFInvocation            `json:"invocations,omitempty"`
+	AutomationDetails        *SARIFAutomationDetails      `json:"automationDetails,omitempty"`
+	VersionControlProvenance []SARIFVersionControlDetails `json:"versionControlProvenance,omitempty"`
+	Results                  []SARIFResult                `json:"results"`
+	Properties               map[string]interface{}       `json:"properties,omitempty"`
 }
 
 type SARIFTool struct {
@@ -31,19 +41,21 @@ type SARIFTool struct {
 }
 
 type SARIFDriver struct {
-	Name           string      `json:"name"`
-	Version        string      `json:"version"`
-	InformationUri string      `json:"informationUri"`
-	Rules          []SARIFRule `json:"rules"`
+	Name           string                 `json:"name"`
+	Version        string                 `json:"version"`
+	InformationUri string                 `json:"informationUri"`
+	Rules          []SARIFRule            `json:"rules"`
+	Properties     map[string]interface{} `json:"properties,omitempty"`
 }
 
 type SARIFRule struct {
-	ID               string          `json:"id"`
-	Name             string          `json:"name"`
-	ShortDescription SARIFMessage    `json:"shortDescription"`
-	FullDescription  SARIFMessage    `json:"fullDescription,omitempty"`
-	HelpUri          string          `json:"helpUri,omitempty"`
-	DefaultConfig    SARIFRuleConfig `json:"defaultConfiguration"`
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name"`
+	ShortDescription SARIFMessage           `json:"shortDescription"`
+	FullDescription  SARIFMessage           `json:"fullDescription,omitempty"`
+	HelpUri          string                 `json:"helpUri,omitempty"`
+	DefaultConfig    SARIFRuleConfig        `json:"defaultConfiguration"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
 }
 
 type SARIFRuleConfig struct {
@@ -54,11 +66,45 @@ type SARIFMessage struct {
 	Text string `json:"text"`
 }
 
+type SARIFInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	StartTimeUtc               string              `json:"startTimeUtc,omitempty"`
+	EndTimeUtc                 string              `json:"endTimeUtc,omitempty"`
+	ToolExecutionNotifications []SARIFNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type SARIFNotification struct {
+	Level   string       `json:"level"`
+	Message SARIFMessage `json:"message"`
+}
+
+type SARIFAutomationDetails struct {
+	ID string `json:"id"`
+}
+
+type SARIFVersionControlDetails struct {
+	RepositoryUri string `json:"repositoryUri"`
+	RevisionID    string `json:"revisionId,omitempty"`
+	Branch        string `json:"branch,omitempty"`
+}
+
 type SARIFResult struct {
-	RuleID    string          `json:"ruleId"`
-	Level     string          `json:"level"`
-	Message   SARIFMessage    `json:"message"`
-	Locations []SARIFLocation `json:"locations,omitempty"`
+	RuleID              string                 `json:"ruleId"`
+	RuleIndex           int                    `json:"ruleIndex"`
+	Level               string                 `json:"level"`
+	Message             SARIFMessage           `json:"message"`
+	Locations           []SARIFLocation        `json:"locations,omitempty"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints,omitempty"`
+	BaselineState       string                 `json:"baselineState,omitempty"`
+	Suppressions        []SARIFSuppression     `json:"suppressions,omitempty"`
+	Properties          map[string]interface{} `json:"properties,omitempty"`
+}
+
+type SARIFSuppression struct {
+	Kind          string                 `json:"kind"`
+	Status        string                 `json:"status"`
+	Justification string                 `json:"justification,omitempty"`
+	Properties    map[string]interface{} `json:"properties,omitempty"`
 }
 
 type SARIFLocation struct {
@@ -67,40 +113,49 @@ type SARIFLocation struct {
 
 type SARIFPhysicalLocation struct {
 	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
 }
 
 type SARIFArtifactLocation struct {
 	URI string `json:"uri"`
 }
 
+type SARIFRegion struct {
+	StartLine   int           `json:"startLine,omitempty"`
+	StartColumn int           `json:"startColumn,omitempty"`
+	EndLine     int           `json:"endLine,omitempty"`
+	EndColumn   int           `json:"endColumn,omitempty"`
+	Snippet     *SARIFMessage `json:"snippet,omitempty"`
+}
+
 func (f *SARIFFormatter) Format(result *api.ScanResult) ([]byte, error) {
-	rulesMap := make(map[string]SARIFRule)
-	var results []SARIFResult
+	rules, ruleIndex := buildSARIFRules(result.Findings)
+	results := make([]SARIFResult, 0, len(result.Findings))
 
 	for _, finding := range result.Findings {
-		if _, exists := rulesMap[finding.RuleCode]; !exists {
-			rulesMap[finding.RuleCode] = SARIFRule{
-				ID:   finding.RuleCode,
-				Name: toCamelCase(finding.RuleName),
-				ShortDescription: SARIFMessage{
-					Text: finding.RuleName,
-				},
-				FullDescription: SARIFMessage{
-					Text: finding.Message,
-				},
-				HelpUri: finding.DocsURL,
-				DefaultConfig: SARIFRuleConfig{
-					Level: mapSeverityToSARIF(finding.Severity),
-				},
-			}
-		}
-
 		sarifResult := SARIFResult{
-			RuleID: finding.RuleCode,
-			Level:  mapSeverityToSARIF(finding.Severity),
+			RuleID:    finding.RuleCode,
+			RuleIndex: ruleIndex[finding.RuleCode],
+			Level:     mapSeverityToSARIF(finding.Severity),
 			Message: SARIFMessage{
 				Text: finding.Message,
 			},
+			PartialFingerprints: map[string]string{
+				fingerprint.Version: fingerprint.Compute(finding),
+			},
+			BaselineState: finding.BaselineState,
+			Suppressions:  sarifSuppressions(finding.Suppressions),
+			Properties: map[string]interface{}{
+				"severity": strings.ToUpper(finding.Severity),
+			},
+		}
+
+		if finding.OriginalSeverity != "" {
+			sarifResult.Properties["originalSeverity"] = strings.ToUpper(finding.OriginalSeverity)
+		}
+
+		if finding.Source != "" {
+			sarifResult.Properties["source"] = finding.Source
 		}
 
 		if finding.FilePath != "" {
@@ -108,8 +163,9 @@ func (f *SARIFFormatter) Format(result *api.ScanResult) ([]byte, error) {
 				{
 					PhysicalLocation: SARIFPhysicalLocation{
 						ArtifactLocation: SARIFArtifactLocation{
-							URI: finding.FilePath,
+							URI: fingerprint.NormalizePath(finding.FilePath),
 						},
+						Region: sarifRegion(finding.Evidence),
 					},
 				},
 			}
@@ -118,32 +174,225 @@ func (f *SARIFFormatter) Format(result *api.ScanResult) ([]byte, error) {
 		results = append(results, sarifResult)
 	}
 
-	var rules []SARIFRule
-	for _, rule := range rulesMap {
-		rules = append(rules, rule)
+	run := SARIFRun{
+		Tool: SARIFTool{
+			Driver: SARIFDriver{
+				Name:           "Canopy",
+				Version:        f.driverVersion(),
+				InformationUri: "https://canopy.app",
+				Rules:          rules,
+			},
+		},
+		Invocations:       []SARIFInvocation{sarifInvocation(result)},
+		AutomationDetails: sarifAutomationDetails(result),
+		Results:           results,
+		Properties:        sarifRunProperties(result),
+	}
+
+	if result.Git != nil && result.Git.RepositoryURL != "" {
+		run.VersionControlProvenance = []SARIFVersionControlDetails{
+			{
+				RepositoryUri: result.Git.RepositoryURL,
+				RevisionID:    result.Git.Commit,
+				Branch:        result.Git.Branch,
+			},
+		}
 	}
 
 	report := SARIFReport{
 		Schema:  sarifSchema,
 		Version: "2.1.0",
-		Runs: []SARIFRun{
-			{
-				Tool: SARIFTool{
-					Driver: SARIFDriver{
-						Name:           "Canopy",
-						Version:        result.PolicyVersion,
-						InformationUri: "https://canopy.app",
-						Rules:          rules,
-					},
-				},
-				Results: results,
-			},
-		},
+		Runs:    []SARIFRun{run},
 	}
 
 	return json.MarshalIndent(report, "", "  ")
 }
 
+func (f *SARIFFormatter) driverVersion() string {
+	if f.toolVersion == "" {
+		return "dev"
+	}
+	return f.toolVersion
+}
+
+func buildSARIFRules(findings []api.Finding) ([]SARIFRule, map[string]int) {
+	rulesMap := make(map[string]SARIFRule)
+
+	for _, finding := range findings {
+		if _, exists := rulesMap[finding.RuleCode]; exists {
+			continue
+		}
+		rulesMap[finding.RuleCode] = SARIFRule{
+			ID:   finding.RuleCode,
+			Name: toCamelCase(finding.RuleName),
+			ShortDescription: SARIFMessage{
+				Text: finding.RuleName,
+			},
+			FullDescription: SARIFMessage{
+				Text: finding.Message,
+			},
+			HelpUri: finding.DocsURL,
+			DefaultConfig: SARIFRuleConfig{
+				Level: mapSeverityToSARIF(finding.Severity),
+			},
+			Properties: map[string]interface{}{
+				"severity": strings.ToUpper(finding.Severity),
+			},
+		}
+	}
+
+	codes := make([]string, 0, len(rulesMap))
+	for code := range rulesMap {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	rules := make([]SARIFRule, 0, len(codes))
+	index := make(map[string]int, len(codes))
+	for i, code := range codes {
+		rules = append(rules, rulesMap[code])
+		index[code] = i
+	}
+
+	return rules, index
+}
+
+func sarifRegion(evidence map[string]interface{}) *SARIFRegion {
+	region := &SARIFRegion{
+		StartLine:   evidenceInt(evidence, "start_line", "line", "line_number"),
+		StartColumn: evidenceInt(evidence, "start_column", "column"),
+		EndLine:     evidenceInt(evidence, "end_line"),
+		EndColumn:   evidenceInt(evidence, "end_column"),
+	}
+
+	if region.StartLine <= 0 {
+		return nil
+	}
+
+	if snippet, ok := evidence["snippet"].(string); ok && snippet != "" {
+		region.Snippet = &SARIFMessage{Text: snippet}
+	}
+
+	return region
+}
+
+func evidenceInt(evidence map[string]interface{}, keys ...string) int {
+	for _, key := range keys {
+		switch v := evidence[key].(type) {
+		case float64:
+			return int(v)
+		case int:
+			return v
+		case int64:
+			return int(v)
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				return int(n)
+			}
+		case string:
+			var n int
+			if _, err := fmt.Sscanf(v, "%d", &n); err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+func sarifSuppressions(suppressions []api.Suppression) []SARIFSuppression {
+	if len(suppressions) == 0 {
+		return nil
+	}
+
+	out := make([]SARIFSuppression, 0, len(suppressions))
+	for _, s := range suppressions {
+		props := map[string]interface{}{"category": s.Kind}
+		if s.Owner != "" {
+			props["owner"] = s.Owner
+		}
+		if s.Expires != "" {
+			props["expires"] = s.Expires
+		}
+		out = append(out, SARIFSuppression{
+			Kind:          "external",
+			Status:        "accepted",
+			Justification: s.Justification,
+			Properties:    props,
+		})
+	}
+	return out
+}
+
+func sarifInvocation(result *api.ScanResult) SARIFInvocation {
+	invocation := SARIFInvocation{
+		ExecutionSuccessful: result.Status != "FAILED",
+	}
+
+	if !result.CreatedAt.IsZero() {
+		invocation.StartTimeUtc = result.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	if result.CompletedAt != nil {
+		invocation.EndTimeUtc = result.CompletedAt.UTC().Format(time.RFC3339)
+	}
+
+	for _, msg := range result.Errors {
+		invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, SARIFNotification{
+			Level:   "error",
+			Message: SARIFMessage{Text: msg},
+		})
+	}
+
+	return invocation
+}
+
+func sarifAutomationDetails(result *api.ScanResult) *SARIFAutomationDetails {
+	platform := strings.ToLower(result.Platform)
+	if platform == "" {
+		platform = "both"
+	}
+	return &SARIFAutomationDetails{
+		ID: fmt.Sprintf("canopy/%s/%s", platform, result.ID),
+	}
+}
+
+func sarifRunProperties(result *api.ScanResult) map[string]interface{} {
+	props := map[string]interface{}{
+		"scanId":        result.ID,
+		"platform":      result.Platform,
+		"policyVersion": result.PolicyVersion,
+		"durationMs":    result.DurationMs,
+	}
+
+	if result.RiskAssessment != nil {
+		props["riskScore"] = result.RiskAssessment.Score
+		props["riskConfidence"] = result.RiskAssessment.Confidence
+		props["riskInterpretation"] = result.RiskAssessment.Interpretation
+	}
+
+	if result.Git != nil {
+		props["git"] = result.Git
+	}
+
+	if result.Gate != nil {
+		props["gate"] = result.Gate
+	}
+
+	if result.Baseline != nil {
+		fixed := make([]string, 0, len(result.Baseline.FixedFindings))
+		for _, finding := range result.Baseline.FixedFindings {
+			fixed = append(fixed, finding.Fingerprint)
+		}
+		props["baseline"] = map[string]interface{}{
+			"new":               result.Baseline.New,
+			"unchanged":         result.Baseline.Unchanged,
+			"fixed":             result.Baseline.Fixed,
+			"fixedFingerprints": fixed,
+		}
+	}
+
+	return props
+}
+
 func mapSeverityToSARIF(severity string) string {
 	switch strings.ToUpper(severity) {
 	case "BLOCKER", "HIGH":
@@ -0,0 +1,119 @@
+package output
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/fingerprint"
+)
+
+func TestSARIFFormatter(t *testing.T) {
+	result := &api.ScanResult{
+		ID:       "scan-1",
+		Platform: "APPLE",
+		Git:      &api.GitMetadata{RepositoryURL: "https://example.com/app.git", Commit: "abc123", Branch: "main"},
+		Errors:   []string{"pubspec.lock could not be parsed"},
+		Findings: []api.Finding{
+			{
+				RuleCode: "APL-PRIV-001",
+				RuleName: "missing required reason",
+				Severity: "BLOCKER",
+				Message:  "UserDefaults is used without a reason",
+				FilePath: `ios\Runner\PrivacyInfo.xcprivacy`,
+				Evidence: map[string]interface{}{"line": float64(7), "column": "3", "snippet": "<dict>"},
+				Source:   "local",
+			},
+			{
+				RuleCode:         "APL-PLIST-004",
+				RuleName:         "vague purpose string",
+				Severity:         "LOW",
+				OriginalSeverity: "MEDIUM",
+				Message:          "purpose string is vague",
+				BaselineState:    api.BaselineUnchanged,
+				Suppressions: []api.Suppression{{
+					Kind:          "project_config",
+					Justification: "copy reviewed",
+					Owner:         "mobile",
+				}},
+			},
+		},
+	}
+
+	data, err := NewSARIFFormatter("1.2.3").Format(result)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var report SARIFReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if report.Version != "2.1.0" || len(report.Runs) != 1 {
+		t.Fatalf("report version %q with %d runs", report.Version, len(report.Runs))
+	}
+	run := report.Runs[0]
+
+	if run.Tool.Driver.Version != "1.2.3" {
+		t.Errorf("driver version = %q", run.Tool.Driver.Version)
+	}
+	var ruleIDs []string
+	for _, r := range run.Tool.Driver.Rules {
+		ruleIDs = append(ruleIDs, r.ID)
+	}
+	if want := []string{"APL-PLIST-004", "APL-PRIV-001"}; !reflect.DeepEqual(ruleIDs, want) {
+		t.Errorf("rules = %v, want %v", ruleIDs, want)
+	}
+	if run.AutomationDetails == nil || run.AutomationDetails.ID != "canopy/apple/scan-1" {
+		t.Errorf("automationDetails = %+v", run.AutomationDetails)
+	}
+	if len(run.VersionControlProvenance) != 1 || run.VersionControlProvenance[0].RevisionID != "abc123" {
+		t.Errorf("versionControlProvenance = %+v", run.VersionControlProvenance)
+	}
+	if inv := run.Invocations[0]; !inv.ExecutionSuccessful || len(inv.ToolExecutionNotifications) != 1 {
+		t.Errorf("invocation = %+v", inv)
+	}
+
+	if len(run.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(run.Results))
+	}
+
+	blocker := run.Results[0]
+	if blocker.RuleIndex != 1 || blocker.Level != "error" {
+		t.Errorf("blocker ruleIndex=%d level=%q", blocker.RuleIndex, blocker.Level)
+	}
+	if got, want := blocker.PartialFingerprints[fingerprint.Version], fingerprint.Compute(result.Findings[0]); got != want {
+		t.Errorf("partialFingerprints = %q, want %q", got, want)
+	}
+	if blocker.Properties["source"] != "local" {
+		t.Errorf("properties = %v, want source local", blocker.Properties)
+	}
+	wantLocation := SARIFPhysicalLocation{
+		ArtifactLocation: SARIFArtifactLocation{URI: "ios/Runner/PrivacyInfo.xcprivacy"},
+		Region:           &SARIFRegion{StartLine: 7, StartColumn: 3, Snippet: &SARIFMessage{Text: "<dict>"}},
+	}
+	if len(blocker.Locations) != 1 || !reflect.DeepEqual(blocker.Locations[0].PhysicalLocation, wantLocation) {
+		t.Errorf("locations = %+v, want %+v", blocker.Locations, wantLocation)
+	}
+
+	accepted := run.Results[1]
+	if accepted.Level != "note" || accepted.BaselineState != api.BaselineUnchanged {
+		t.Errorf("accepted level=%q baselineState=%q", accepted.Level, accepted.BaselineState)
+	}
+	if accepted.Properties["originalSeverity"] != "MEDIUM" {
+		t.Errorf("properties = %v, want originalSeverity MEDIUM", accepted.Properties)
+	}
+	if len(accepted.Locations) != 0 {
+		t.Errorf("locations = %+v, want none without a file path", accepted.Locations)
+	}
+	wantSuppression := SARIFSuppression{
+		Kind:          "external",
+		Status:        "accepted",
+		Justification: "copy reviewed",
+		Properties:    map[string]interface{}{"category": "project_config", "owner": "mobile"},
+	}
+	if len(accepted.Suppressions) != 1 || !reflect.DeepEqual(accepted.Suppressions[0], wantSuppression) {
+		t.Errorf("suppressions = %+v, want %+v", accepted.Suppressions, wantSuppression)
+	}
+}
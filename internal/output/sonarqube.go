@@ -0,0 +1,137 @@
+package output
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/fingerprint"
+)
+
+const sonarEngineID = "canopy"
+
+// SonarQubeFormatter writes the Generic Issue Import format (SonarQube 10.3+).
+// Findings without a file path are omitted because SonarQube rejects issues
+// that do not point at an indexed file. Suppressed and baselined findings are
+// omitted too: SonarQube would report them as open issues. The format has no
+// state for resolved issues, so findings fixed since the baseline are left
+// out as well; SonarQube closes an issue once a later import no longer
+// contains it.
+type SonarQubeFormatter struct{}
+
+func NewSonarQubeFormatter() *SonarQubeFormatter {
+	return &SonarQubeFormatter{}
+}
+
+type SonarReport struct {
+	Rules  []SonarRule  `json:"rules"`
+	Issues []SonarIssue `json:"issues"`
+}
+
+type SonarRule struct {
+	ID                 string        `json:"id"`
+	Name               string        `json:"name"`
+	Description        string        `json:"description"`
+	EngineID           string        `json:"engineId"`
+	CleanCodeAttribute string        `json:"cleanCodeAttribute"`
+	Impacts            []SonarImpact `json:"impacts"`
+}
+
+type SonarImpact struct {
+	SoftwareQuality string `json:"softwareQuality"`
+	Severity        string `json:"severity"`
+}
+
+type SonarIssue struct {
+	RuleID          string        `json:"ruleId"`
+	EffortMinutes   int           `json:"effortMinutes,omitempty"`
+	PrimaryLocation SonarLocation `json:"primaryLocation"`
+}
+
+type SonarLocation struct {
+	Message   string          `json:"message"`
+	FilePath  string          `json:"filePath"`
+	TextRange *SonarTextRange `json:"textRange,omitempty"`
+}
+
+type SonarTextRange struct {
+	StartLine   int `json:"startLine"`
+	EndLine     int `json:"endLine,omitempty"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+func (f *SonarQubeFormatter) Format(result *api.ScanResult) ([]byte, error) {
+	report := SonarReport{
+		Rules:  []SonarRule{},
+		Issues: []SonarIssue{},
+	}
+	rules := make(map[string]SonarRule)
+
+	for _, finding := range result.Findings {
+		path := fingerprint.NormalizePath(finding.FilePath)
+		if path == "" || finding.Accepted() {
+			continue
+		}
+
+		if _, exists := rules[finding.RuleCode]; !exists {
+			description := finding.RuleName
+			if finding.DocsURL != "" {
+				description += " (" + finding.DocsURL + ")"
+			}
+			rules[finding.RuleCode] = SonarRule{
+				ID:                 finding.RuleCode,
+				Name:               finding.RuleName,
+				Description:        description,
+				EngineID:           sonarEngineID,
+				CleanCodeAttribute: "TRUSTWORTHY",
+				Impacts: []SonarImpact{
+					{
+						SoftwareQuality: "SECURITY",
+						Severity:        mapSeverityToSonar(finding.Severity),
+					},
+				},
+			}
+		}
+
+		issue := SonarIssue{
+			RuleID: finding.RuleCode,
+			PrimaryLocation: SonarLocation{
+				Message:  finding.Message,
+				FilePath: path,
+			},
+		}
+
+		if line := evidenceInt(finding.Evidence, "start_line", "line", "line_number"); line > 0 {
+			issue.PrimaryLocation.TextRange = &SonarTextRange{
+				StartLine: line,
+				EndLine:   evidenceInt(finding.Evidence, "end_line"),
+			}
+		}
+
+		report.Issues = append(report.Issues, issue)
+	}
+
+	codes := make([]string, 0, len(rules))
+	for code := range rules {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		report.Rules = append(report.Rules, rules[code])
+	}
+
+	return json.MarshalIndent(report, "", "  ")
+}
+
+func mapSeverityToSonar(severity string) string {
+	switch strings.ToUpper(severity) {
+	case "BLOCKER", "HIGH":
+		return "HIGH"
+	case "MEDIUM":
+		return "MEDIUM"
+	default:
+		return "LOW"
+	}
+}
@@ -0,0 +1,118 @@
+package output
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+func TestSonarQubeFormatter(t *testing.T) {
+	tests := []struct {
+		name       string
+		findings   []api.Finding
+		wantRules  []string
+		wantIssues []SonarIssue
+	}{
+		{
+			name:       "empty report keeps arrays",
+			wantRules:  []string{},
+			wantIssues: []SonarIssue{},
+		},
+		{
+			name: "issues with and without a line",
+			findings: []api.Finding{
+				{
+					RuleCode: "GPL-SDK-001",
+					RuleName: "Target SDK",
+					Severity: "HIGH",
+					Message:  "targetSdk is 33",
+					FilePath: "./app/build.gradle",
+					Evidence: map[string]interface{}{"line": float64(12), "end_line": float64(13)},
+				},
+				{
+					RuleCode: "APL-PLIST-001",
+					RuleName: "Missing purpose string",
+					Severity: "MEDIUM",
+					Message:  "NSCameraUsageDescription is missing",
+					FilePath: `ios\Runner\Info.plist`,
+				},
+			},
+			wantRules: []string{"APL-PLIST-001", "GPL-SDK-001"},
+			wantIssues: []SonarIssue{
+				{
+					RuleID: "GPL-SDK-001",
+					PrimaryLocation: SonarLocation{
+						Message:   "targetSdk is 33",
+						FilePath:  "app/build.gradle",
+						TextRange: &SonarTextRange{StartLine: 12, EndLine: 13},
+					},
+				},
+				{
+					RuleID: "APL-PLIST-001",
+					PrimaryLocation: SonarLocation{
+						Message:  "NSCameraUsageDescription is missing",
+						FilePath: "ios/Runner/Info.plist",
+					},
+				},
+			},
+		},
+		{
+			name: "project-level and accepted findings are omitted",
+			findings: []api.Finding{
+				{RuleCode: "APL-ENT-001", Severity: "HIGH", Message: "no file"},
+				{RuleCode: "APL-PRIV-001", Severity: "BLOCKER", FilePath: "PrivacyInfo.xcprivacy", BaselineState: api.BaselineUnchanged},
+				{RuleCode: "APL-PRIV-002", Severity: "BLOCKER", FilePath: "PrivacyInfo.xcprivacy", Suppressions: []api.Suppression{{Kind: "inline"}}},
+			},
+			wantRules:  []string{},
+			wantIssues: []SonarIssue{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := NewSonarQubeFormatter().Format(&api.ScanResult{Findings: tt.findings})
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+
+			var got SonarReport
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if got.Rules == nil || got.Issues == nil {
+				t.Fatalf("Format() = %s, want rules and issues arrays", data)
+			}
+
+			rules := []string{}
+			for _, r := range got.Rules {
+				rules = append(rules, r.ID)
+				if r.EngineID != sonarEngineID || len(r.Impacts) != 1 {
+					t.Errorf("rule %s = %+v", r.ID, r)
+				}
+			}
+			if !reflect.DeepEqual(rules, tt.wantRules) {
+				t.Errorf("rules = %v, want %v", rules, tt.wantRules)
+			}
+			if !reflect.DeepEqual(got.Issues, tt.wantIssues) {
+				t.Errorf("issues =\n%+v\nwant\n%+v", got.Issues, tt.wantIssues)
+			}
+		})
+	}
+}
+
+func TestMapSeverityToSonar(t *testing.T) {
+	tests := map[string]string{
+		"BLOCKER": "HIGH",
+		"high":    "HIGH",
+		"MEDIUM":  "MEDIUM",
+		"LOW":     "LOW",
+		"INFO":    "LOW",
+	}
+	for severity, want := range tests {
+		if got := mapSeverityToSonar(severity); got != want {
+			t.Errorf("mapSeverityToSonar(%q) = %q, want %q", severity, got, want)
+		}
+	}
+}
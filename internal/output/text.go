@@ -13,10 +13,7 @@ type TextFormatter struct {
 }
 
 func NewTextFormatter(noColor bool) *TextFormatter {
-	if noColor {
-		color.NoColor = true
-	}
-	return &TextFormatter{noColor: noColor}
+	return &TextFormatter{noColor: noColor || color.NoColor}
 }
 
 func (f *TextFormatter) Format(result *api.ScanResult) ([]byte, error) {
@@ -25,31 +22,63 @@ func (f *TextFormatter) Format(result *api.ScanResult) ([]byte, error) {
 	sb.WriteString("Canopy Scan Results\n")
 	sb.WriteString("==================\n\n")
 
-	sb.WriteString(fmt.Sprintf("Scan ID: %s\n", result.ID))
+	if result.ID != "" {
+		sb.WriteString(fmt.Sprintf("Scan ID: %s\n", result.ID))
+	}
 	sb.WriteString(fmt.Sprintf("Platform: %s\n", formatPlatform(result.Platform)))
-	sb.WriteString(fmt.Sprintf("Policy Version: %s\n", result.PolicyVersion))
+	if result.PolicyVersion != "" {
+		sb.WriteString(fmt.Sprintf("Policy Version: %s\n", result.PolicyVersion))
+	}
 	sb.WriteString(fmt.Sprintf("Duration: %dms\n", result.DurationMs))
+	if git := result.Git; git != nil {
+		if git.RepositoryURL != "" {
+			sb.WriteString(fmt.Sprintf("Repository: %s\n", git.RepositoryURL))
+		}
+		if git.Commit != "" {
+			commit := git.Commit
+			if len(commit) > 12 {
+				commit = commit[:12]
+			}
+			if git.Dirty {
+				commit += " (dirty)"
+			}
+			sb.WriteString(fmt.Sprintf("Commit: %s\n", commit))
+		}
+		if git.Branch != "" {
+			sb.WriteString(fmt.Sprintf("Branch: %s\n", git.Branch))
+		}
+		if git.Tag != "" {
+			sb.WriteString(fmt.Sprintf("Tag: %s\n", git.Tag))
+		}
+		if git.Author != "" {
+			sb.WriteString(fmt.Sprintf("Author: %s\n", git.Author))
+		}
+	}
 	sb.WriteString("\n")
 
+	if len(result.Apps) > 0 {
+		f.writeApps(&sb, result.Apps)
+	}
+
 	if result.Summary != nil {
 		sb.WriteString("Summary\n")
 		sb.WriteString("-------\n")
 
 		if result.RiskAssessment != nil {
-			riskColor := getRiskColor(result.RiskAssessment.Score)
+			riskColor := f.getRiskColor(result.RiskAssessment.Score)
 			sb.WriteString(fmt.Sprintf("Risk Score: %s%d/100 (%s)%s\n",
 				riskColor,
 				result.RiskAssessment.Score,
 				result.RiskAssessment.Interpretation,
-				colorReset()))
+				f.colorReset()))
 		}
 
 		sb.WriteString(fmt.Sprintf("Total Issues: %d\n", result.Summary.Total))
 		if result.Summary.Blocker > 0 {
-			sb.WriteString(fmt.Sprintf("  %sBLOCKER: %d%s\n", colorRed(), result.Summary.Blocker, colorReset()))
+			sb.WriteString(fmt.Sprintf("  %sBLOCKER: %d%s\n", f.colorRed(), result.Summary.Blocker, f.colorReset()))
 		}
 		if result.Summary.High > 0 {
-			sb.WriteString(fmt.Sprintf("  %sHIGH: %d%s\n", colorYellow(), result.Summary.High, colorReset()))
+			sb.WriteString(fmt.Sprintf("  %sHIGH: %d%s\n", f.colorYellow(), result.Summary.High, f.colorReset()))
 		}
 		if result.Summary.Medium > 0 {
 			sb.WriteString(fmt.Sprintf("  MEDIUM: %d\n", result.Summary.Medium))
@@ -57,41 +86,72 @@ func (f *TextFormatter) Format(result *api.ScanResult) ([]byte, error) {
 		if result.Summary.Low > 0 {
 			sb.WriteString(fmt.Sprintf("  LOW: %d\n", result.Summary.Low))
 		}
+		if result.Baseline != nil {
+			sb.WriteString(fmt.Sprintf("Baseline: %d new, %d unchanged, %d fixed\n",
+				result.Baseline.New, result.Baseline.Unchanged, result.Baseline.Fixed))
+		}
 		sb.WriteString("\n")
 	}
 
-	if len(result.Findings) > 0 {
+	var active, suppressed []api.Finding
+	for _, finding := range result.Findings {
+		if finding.Suppressed() {
+			suppressed = append(suppressed, finding)
+		} else {
+			active = append(active, finding)
+		}
+	}
+
+	if result.Baseline != nil {
+		f.writeBaselineSections(&sb, active, result.Baseline)
+	} else if len(active) > 0 {
 		sb.WriteString("Issues\n")
 		sb.WriteString("------\n\n")
 
-		for _, finding := range result.Findings {
-			icon := getSeverityIcon(finding.Severity)
-			severityColor := getSeverityColor(finding.Severity)
+		for _, finding := range active {
+			f.writeFinding(&sb, finding)
+		}
+	}
 
-			sb.WriteString(fmt.Sprintf("%s %s%s%s: %s - %s\n",
-				icon,
-				severityColor,
-				finding.Severity,
-				colorReset(),
-				finding.RuleCode,
-				finding.RuleName,
-			))
-			sb.WriteString(fmt.Sprintf("   %s\n", finding.Message))
+	if len(suppressed) > 0 {
+		sb.WriteString("Suppressed Issues\n")
+		sb.WriteString("-----------------\n\n")
 
+		for _, finding := range suppressed {
+			line := fmt.Sprintf("• %s: %s - %s", finding.Severity, finding.RuleCode, finding.RuleName)
 			if finding.FilePath != "" {
-				sb.WriteString(fmt.Sprintf("   File: %s\n", finding.FilePath))
+				line += fmt.Sprintf(" (%s)", finding.FilePath)
 			}
+			sb.WriteString(line + "\n")
 
-			if finding.Remediation != nil && finding.Remediation.Template != "" {
-				sb.WriteString(fmt.Sprintf("   Fix: %s\n", finding.Remediation.Template))
+			for _, s := range finding.Suppressions {
+				detail := fmt.Sprintf("   %s: %s", strings.ReplaceAll(s.Kind, "_", " "), s.Justification)
+				if s.Owner != "" {
+					detail += fmt.Sprintf(" (owner: %s)", s.Owner)
+				}
+				if s.Expires != "" {
+					detail += fmt.Sprintf(" [expires %s]", s.Expires)
+				}
+				sb.WriteString(detail + "\n")
 			}
+		}
+		sb.WriteString("\n")
+	}
 
-			if finding.DocsURL != "" {
-				sb.WriteString(fmt.Sprintf("   Docs: %s\n", finding.DocsURL))
-			}
+	if len(result.Entitlements) > 0 {
+		f.writeEntitlements(&sb, result.Entitlements)
+	}
 
-			sb.WriteString("\n")
+	if result.Gate != nil {
+		if result.Gate.Passed {
+			sb.WriteString(fmt.Sprintf("Gate: %sPASSED%s\n", f.colorGreen(), f.colorReset()))
+		} else {
+			sb.WriteString(fmt.Sprintf("Gate: %sFAILED%s\n", f.colorRed(), f.colorReset()))
+			for _, violation := range result.Gate.Violations {
+				sb.WriteString(fmt.Sprintf("  - %s\n", violation))
+			}
 		}
+		sb.WriteString("\n")
 	}
 
 	blockerCount := 0
@@ -101,15 +161,165 @@ func (f *TextFormatter) Format(result *api.ScanResult) ([]byte, error) {
 
 	if blockerCount > 0 {
 		sb.WriteString(fmt.Sprintf("%sScan completed with %d BLOCKER issues. Fix these before submission.%s\n",
-			colorRed(), blockerCount, colorReset()))
+			f.colorRed(), blockerCount, f.colorReset()))
 	} else {
 		sb.WriteString(fmt.Sprintf("%sScan completed with no BLOCKER issues.%s\n",
-			colorGreen(), colorReset()))
+			f.colorGreen(), f.colorReset()))
 	}
 
 	return []byte(sb.String()), nil
 }
 
+func (f *TextFormatter) writeFinding(sb *strings.Builder, finding api.Finding) {
+	icon := getSeverityIcon(finding.Severity)
+	severityColor := f.getSeverityColor(finding.Severity)
+
+	sb.WriteString(fmt.Sprintf("%s %s%s%s: %s - %s\n",
+		icon,
+		severityColor,
+		finding.Severity,
+		f.colorReset(),
+		finding.RuleCode,
+		finding.RuleName,
+	))
+	sb.WriteString(fmt.Sprintf("   %s\n", finding.Message))
+
+	if finding.OriginalSeverity != "" {
+		sb.WriteString(fmt.Sprintf("   Severity overridden by policy (was %s)\n", finding.OriginalSeverity))
+	}
+
+	if finding.App != "" {
+		sb.WriteString(fmt.Sprintf("   App: %s\n", finding.App))
+	}
+
+	if finding.Source != "" {
+		sb.WriteString(fmt.Sprintf("   Source: %s\n", finding.Source))
+	}
+
+	if finding.FilePath != "" {
+		sb.WriteString(fmt.Sprintf("   File: %s\n", finding.FilePath))
+	}
+
+	if plugin, ok := finding.Evidence["plugin"].(string); ok && plugin != "" {
+		if v, ok := finding.Evidence["plugin_version"].(string); ok && v != "" {
+			plugin += " " + v
+		}
+		sb.WriteString(fmt.Sprintf("   Plugin: %s\n", plugin))
+	}
+
+	if finding.Remediation != nil && finding.Remediation.Template != "" {
+		sb.WriteString(fmt.Sprintf("   Fix: %s\n", finding.Remediation.Template))
+	}
+
+	if finding.DocsURL != "" {
+		sb.WriteString(fmt.Sprintf("   Docs: %s\n", finding.DocsURL))
+	}
+
+	sb.WriteString("\n")
+}
+
+func (f *TextFormatter) writeApps(sb *strings.Builder, apps []api.AppResult) {
+	sb.WriteString("Apps\n")
+	sb.WriteString("----\n")
+
+	for _, app := range apps {
+		line := fmt.Sprintf("%s (%s, %s): ", app.Name, app.Path, formatPlatform(app.Platform))
+		switch {
+		case app.Status == "FAILED":
+			line += f.colorRed() + "FAILED" + f.colorReset()
+			if app.Error != "" {
+				line += " - " + app.Error
+			}
+		case app.Summary != nil:
+			line += fmt.Sprintf("%d issues", app.Summary.Total)
+			if app.Summary.Blocker > 0 {
+				line += fmt.Sprintf(", %s%d BLOCKER%s", f.colorRed(), app.Summary.Blocker, f.colorReset())
+			}
+		default:
+			line += app.Status
+		}
+		if app.ScanID != "" {
+			line += fmt.Sprintf(" [scan %s]", app.ScanID)
+		}
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString("\n")
+}
+
+func (f *TextFormatter) writeEntitlements(sb *strings.Builder, targets []api.TargetEntitlements) {
+	sb.WriteString("Entitlements\n")
+	sb.WriteString("------------\n")
+
+	for _, t := range targets {
+		line := t.Target
+		if t.App != "" {
+			line = t.App + "/" + line
+		}
+		if t.BundleID != "" {
+			line += fmt.Sprintf(" (%s)", t.BundleID)
+		}
+		if t.File != "" {
+			line += fmt.Sprintf(" - %s", t.File)
+		}
+		sb.WriteString(f.colorCyan() + line + f.colorReset() + "\n")
+
+		if len(t.Capabilities) == 0 {
+			sb.WriteString("   no capabilities\n")
+		}
+		for _, c := range t.Capabilities {
+			capLine := "   • " + c.Name
+			if len(c.Values) > 0 {
+				capLine += ": " + strings.Join(c.Values, ", ")
+			}
+			if c.Source != "entitlements" {
+				capLine += fmt.Sprintf(" [%s]", c.Source)
+			}
+			sb.WriteString(capLine + "\n")
+		}
+	}
+	sb.WriteString("\n")
+}
+
+func (f *TextFormatter) writeBaselineSections(sb *strings.Builder, findings []api.Finding, baseline *api.BaselineResult) {
+	var newFindings, unchanged []api.Finding
+	for _, finding := range findings {
+		if finding.BaselineState == api.BaselineUnchanged {
+			unchanged = append(unchanged, finding)
+		} else {
+			newFindings = append(newFindings, finding)
+		}
+	}
+
+	if len(newFindings) > 0 {
+		sb.WriteString("New Issues\n")
+		sb.WriteString("----------\n\n")
+		for _, finding := range newFindings {
+			f.writeFinding(sb, finding)
+		}
+	}
+
+	if len(unchanged) > 0 {
+		sb.WriteString("Baseline Issues (still present)\n")
+		sb.WriteString("-------------------------------\n\n")
+		for _, finding := range unchanged {
+			f.writeFinding(sb, finding)
+		}
+	}
+
+	if len(baseline.FixedFindings) > 0 {
+		sb.WriteString("Fixed Since Baseline\n")
+		sb.WriteString("--------------------\n\n")
+		for _, finding := range baseline.FixedFindings {
+			line := fmt.Sprintf("%s✓%s %s: %s - %s", f.colorGreen(), f.colorReset(), finding.Severity, finding.RuleCode, finding.RuleName)
+			if finding.FilePath != "" {
+				line += fmt.Sprintf(" (%s)", finding.FilePath)
+			}
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString("\n")
+	}
+}
+
 func formatPlatform(platform string) string {
 	switch strings.ToUpper(platform) {
 	case "APPLE":
@@ -138,58 +348,58 @@ func getSeverityIcon(severity string) string {
 	}
 }
 
-func getSeverityColor(severity string) string {
+func (f *TextFormatter) getSeverityColor(severity string) string {
 	switch strings.ToUpper(severity) {
 	case "BLOCKER":
-		return colorRed()
+		return f.colorRed()
 	case "HIGH":
-		return colorYellow()
+		return f.colorYellow()
 	case "MEDIUM":
-		return colorCyan()
+		return f.colorCyan()
 	default:
 		return ""
 	}
 }
 
-func getRiskColor(score int) string {
+func (f *TextFormatter) getRiskColor(score int) string {
 	if score >= 80 {
-		return colorRed()
+		return f.colorRed()
 	} else if score >= 50 {
-		return colorYellow()
+		return f.colorYellow()
 	}
-	return colorGreen()
+	return f.colorGreen()
 }
 
-func colorRed() string {
-	if color.NoColor {
+func (f *TextFormatter) colorRed() string {
+	if f.noColor {
 		return ""
 	}
 	return "\033[31m"
 }
 
-func colorYellow() string {
-	if color.NoColor {
+func (f *TextFormatter) colorYellow() string {
+	if f.noColor {
 		return ""
 	}
 	return "\033[33m"
 }
 
-func colorGreen() string {
-	if color.NoColor {
+func (f *TextFormatter) colorGreen() string {
+	if f.noColor {
 		return ""
 	}
 	return "\033[32m"
 }
 
-func colorCyan() string {
-	if color.NoColor {
+func (f *TextFormatter) colorCyan() string {
+	if f.noColor {
 		return ""
 	}
 	return "\033[36m"
 }
 
-func colorReset() string {
-	if color.NoColor {
+func (f *TextFormatter) colorReset() string {
+	if f.noColor {
 		return ""
 	}
 	return "\033[0m"
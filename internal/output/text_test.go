@@ -0,0 +1,127 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+func TestTextFormatter(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  *api.ScanResult
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "issues and summary",
+			result: &api.ScanResult{
+				ID:       "scan-1",
+				Platform: "GOOGLE",
+				Summary:  &api.ScanSummary{Total: 1, Blocker: 1},
+				Findings: []api.Finding{{
+					RuleCode:    "GPL-SDK-001",
+					RuleName:    "Target SDK",
+					Severity:    "BLOCKER",
+					Message:     "targetSdk is 33",
+					FilePath:    "app/build.gradle",
+					Remediation: &api.Remediation{Template: "targetSdk = 35"},
+				}},
+			},
+			want: []string{
+				"Scan ID: scan-1",
+				"Platform: Google Play",
+				"Total Issues: 1",
+				"  BLOCKER: 1",
+				"Issues\n------",
+				"BLOCKER: GPL-SDK-001 - Target SDK",
+				"   File: app/build.gradle",
+				"   Fix: targetSdk = 35",
+				"Scan completed with 1 BLOCKER issues.",
+			},
+			notWant: []string{"Suppressed Issues", "\033["},
+		},
+		{
+			name: "suppressed findings are listed separately",
+			result: &api.ScanResult{
+				Platform: "APPLE",
+				Findings: []api.Finding{{
+					RuleCode: "APL-PRIV-001",
+					RuleName: "Missing reason",
+					Severity: "HIGH",
+					Suppressions: []api.Suppression{{
+						Kind:          "project_config",
+						Justification: "vendor SDK",
+						Owner:         "mobile",
+						Expires:       "2030-01-01",
+					}},
+				}},
+			},
+			want: []string{
+				"Suppressed Issues",
+				"• HIGH: APL-PRIV-001 - Missing reason",
+				"   project config: vendor SDK (owner: mobile) [expires 2030-01-01]",
+				"Scan completed with no BLOCKER issues.",
+			},
+			notWant: []string{"\nIssues\n"},
+		},
+		{
+			name: "baseline sections",
+			result: &api.ScanResult{
+				Platform: "BOTH",
+				Summary:  &api.ScanSummary{Total: 2},
+				Baseline: &api.BaselineResult{
+					New:       1,
+					Unchanged: 1,
+					Fixed:     1,
+					FixedFindings: []api.Finding{{
+						RuleCode: "APL-PLIST-001",
+						RuleName: "Missing purpose string",
+						Severity: "HIGH",
+						FilePath: "Info.plist",
+					}},
+				},
+				Findings: []api.Finding{
+					{RuleCode: "A-1", RuleName: "new", Severity: "LOW", BaselineState: api.BaselineNew},
+					{RuleCode: "A-2", RuleName: "old", Severity: "LOW", BaselineState: api.BaselineUnchanged},
+				},
+			},
+			want: []string{
+				"Baseline: 1 new, 1 unchanged, 1 fixed",
+				"New Issues",
+				"Baseline Issues (still present)",
+				"Fixed Since Baseline",
+				"✓ HIGH: APL-PLIST-001 - Missing purpose string (Info.plist)",
+			},
+		},
+		{
+			name: "failed gate",
+			result: &api.ScanResult{
+				Platform: "APPLE",
+				Gate:     &api.GateResult{Violations: []string{"1 HIGH finding(s) exceed the limit of 0"}},
+			},
+			want: []string{"Gate: FAILED", "  - 1 HIGH finding(s) exceed the limit of 0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := NewTextFormatter(true).Format(tt.result)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			got := string(data)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Format() missing %q in\n%s", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("Format() unexpectedly contains %q in\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package plist
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+	"unicode/utf16"
+)
+
+const maxBinaryDepth = 128
+
+// appleEpoch is the reference date of binary plist dates.
+var appleEpoch = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type binaryDecoder struct {
+	data       []byte
+	offsets    []uint64
+	refSize    int
+	inProgress map[uint64]bool
+}
+
+func decodeBinary(data []byte) (interface{}, error) {
+	if len(data) < 8+32 || string(data[:8]) != "bplist00" {
+		return nil, fmt.Errorf("plist: unsupported binary format")
+	}
+
+	trailer := data[len(data)-32:]
+	offsetSize := int(trailer[6])
+	refSize := int(trailer[7])
+	numObjects := binary.BigEndian.Uint64(trailer[8:16])
+	topObject := binary.BigEndian.Uint64(trailer[16:24])
+	tableOffset := binary.BigEndian.Uint64(trailer[24:32])
+
+	if offsetSize < 1 || offsetSize > 8 || refSize < 1 || refSize > 8 {
+		return nil, fmt.Errorf("plist: invalid binary trailer")
+	}
+	if numObjects == 0 || topObject >= numObjects {
+		return nil, fmt.Errorf("plist: invalid binary object count")
+	}
+	limit := uint64(len(data) - 32)
+	if tableOffset < 8 || tableOffset > limit || numObjects > limit ||
+		tableOffset+numObjects*uint64(offsetSize) > limit {
+		return nil, fmt.Errorf("plist: invalid binary offset table")
+	}
+
+	d := &binaryDecoder{
+		data:       data,
+		offsets:    make([]uint64, numObjects),
+		refSize:    refSize,
+		inProgress: make(map[uint64]bool),
+	}
+	for i := range d.offsets {
+		start := tableOffset + uint64(i*offsetSize)
+		d.offsets[i] = readUint(data[start : start+uint64(offsetSize)])
+		if d.offsets[i] >= tableOffset {
+			return nil, fmt.Errorf("plist: object %d out of range", i)
+		}
+	}
+
+	return d.object(topObject, 0)
+}
+
+func (d *binaryDecoder) object(ref uint64, depth int) (interface{}, error) {
+	if ref >= uint64(len(d.offsets)) {
+		return nil, fmt.Errorf("plist: invalid object reference %d", ref)
+	}
+	if depth > maxBinaryDepth || d.inProgress[ref] {
+		return nil, fmt.Errorf("plist: object graph too deep or cyclic")
+	}
+	d.inProgress[ref] = true
+	defer delete(d.inProgress, ref)
+
+	off := d.offsets[ref]
+	marker := d.data[off]
+	kind, info := marker>>4, int(marker&0x0f)
+
+	switch kind {
+	case 0x0:
+		switch marker {
+		case 0x08:
+			return false, nil
+		case 0x09:
+			return true, nil
+		default:
+			return nil, nil
+		}
+
+	case 0x1:
+		b, err := d.slice(off+1, 1<<info)
+		if err != nil {
+			return nil, err
+		}
+		// 8-byte integers are signed; 16-byte integers keep the low word.
+		if len(b) == 16 {
+			b = b[8:]
+		}
+		return int64(readUint(b)), nil
+
+	case 0x2:
+		b, err := d.slice(off+1, 1<<info)
+		if err != nil {
+			return nil, err
+		}
+		switch len(b) {
+		case 4:
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+		case 8:
+			return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+		}
+		return nil, fmt.Errorf("plist: invalid real size %d", len(b))
+
+	case 0x3:
+		b, err := d.slice(off+1, 8)
+		if err != nil {
+			return nil, err
+		}
+		secs := math.Float64frombits(binary.BigEndian.Uint64(b))
+		return appleEpoch.Add(time.Duration(secs * float64(time.Second))), nil
+
+	case 0x4:
+		count, start, err := d.count(off, info)
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.slice(start, count)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), b...), nil
+
+	case 0x5:
+		count, start, err := d.count(off, info)
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.slice(start, count)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+
+	case 0x6:
+		count, start, err := d.count(off, info)
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.slice(start, count*2)
+		if err != nil {
+			return nil, err
+		}
+		units := make([]uint16, count)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(b[i*2:])
+		}
+		return string(utf16.Decode(units)), nil
+
+	case 0x8:
+		b, err := d.slice(off+1, info+1)
+		if err != nil {
+			return nil, err
+		}
+		return UID(readUint(b)), nil
+
+	case 0xA:
+		count, start, err := d.count(off, info)
+		if err != nil {
+			return nil, err
+		}
+		refs, err := d.refs(start, count)
+		if err != nil {
+			return nil, err
+		}
+		arr := make([]interface{}, 0, count)
+		for _, r := range refs {
+			v, err := d.object(r, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+
+	case 0xD:
+		count, start, err := d.count(off, info)
+		if err != nil {
+			return nil, err
+		}
+		refs, err := d.refs(start, count*2)
+		if err != nil {
+			return nil, err
+		}
+		dict := make(map[string]interface{}, count)
+		for i := 0; i < count; i++ {
+			k, err := d.object(refs[i], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("plist: dictionary key is %T, not a string", k)
+			}
+			v, err := d.object(refs[count+i], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			dict[key] = v
+		}
+		return dict, nil
+	}
+
+	return nil, fmt.Errorf("plist: unknown object marker 0x%02x", marker)
+}
+
+// count returns the element count of a variable-length object and the
+// offset of its payload. A count nibble of 0xF is followed by an integer
+// object holding the real count.
+func (d *binaryDecoder) count(off uint64, info int) (int, uint64, error) {
+	if info != 0x0f {
+		return info, off + 1, nil
+	}
+
+	if off+1 >= uint64(len(d.data)) || d.data[off+1]>>4 != 0x1 {
+		return 0, 0, fmt.Errorf("plist: invalid length marker")
+	}
+	size := 1 << (d.data[off+1] & 0x0f)
+	b, err := d.slice(off+2, size)
+	if err != nil {
+		return 0, 0, err
+	}
+	n := readUint(b)
+	if n > uint64(len(d.data)) {
+		return 0, 0, fmt.Errorf("plist: length %d out of range", n)
+	}
+	return int(n), off + 2 + uint64(size), nil
+}
+
+func (d *binaryDecoder) refs(start uint64, count int) ([]uint64, error) {
+	b, err := d.slice(start, count*d.refSize)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]uint64, count)
+	for i := range refs {
+		refs[i] = readUint(b[i*d.refSize : (i+1)*d.refSize])
+	}
+	return refs, nil
+}
+
+func (d *binaryDecoder) slice(start uint64, n int) ([]byte, error) {
+	end := start + uint64(n)
+	if n < 0 || end > uint64(len(d.data)) || end < start {
+		return nil, fmt.Errorf("plist: object extends past end of data")
+	}
+	return d.data[start:end], nil
+}
+
+func readUint(b []byte) uint64 {
+	var n uint64
+	for _, c := range b {
+		n = n<<8 | uint64(c)
+	}
+	return n
+}
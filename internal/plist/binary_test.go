@@ -0,0 +1,218 @@
+package plist
+
+import (
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// trailer describes the last 32 bytes of a binary plist.
+type trailer struct {
+	offsetSize  byte
+	refSize     byte
+	numObjects  uint64
+	topObject   uint64
+	tableOffset uint64
+}
+
+// buildBinary lays out objects after the header, appends a one-byte
+// offset table and returns the data with its default trailer. edit may
+// adjust the trailer before it is written.
+func buildBinary(objects [][]byte, edit func(*trailer)) []byte {
+	data := []byte("bplist00")
+	var offsets []byte
+	for _, obj := range objects {
+		offsets = append(offsets, byte(len(data)))
+		data = append(data, obj...)
+	}
+	t := trailer{
+		offsetSize:  1,
+		refSize:     1,
+		numObjects:  uint64(len(objects)),
+		tableOffset: uint64(len(data)),
+	}
+	data = append(data, offsets...)
+	if edit != nil {
+		edit(&t)
+	}
+
+	tail := make([]byte, 32)
+	tail[6] = t.offsetSize
+	tail[7] = t.refSize
+	binary.BigEndian.PutUint64(tail[8:], t.numObjects)
+	binary.BigEndian.PutUint64(tail[16:], t.topObject)
+	binary.BigEndian.PutUint64(tail[24:], t.tableOffset)
+	return append(data, tail...)
+}
+
+func TestDecodeBinary(t *testing.T) {
+	dict := [][]byte{
+		{0xD1, 0x01, 0x02},
+		{0x51, 'a'},
+		{0x10, 0x01},
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    interface{}
+		wantErr string
+	}{
+		{
+			name: "dictionary",
+			data: buildBinary(dict, nil),
+			want: map[string]interface{}{"a": int64(1)},
+		},
+		{
+			name: "array of scalars",
+			data: buildBinary([][]byte{
+				{0xA3, 0x01, 0x02, 0x03},
+				{0x09},
+				{0x53, 'f', 'o', 'o'},
+				{0x80, 0x07},
+			}, nil),
+			want: []interface{}{true, "foo", UID(7)},
+		},
+		{
+			name: "extended length string",
+			data: buildBinary([][]byte{
+				append([]byte{0x5F, 0x10, 0x10}, strings.Repeat("x", 16)...),
+			}, nil),
+			want: strings.Repeat("x", 16),
+		},
+		{
+			name:    "too short",
+			data:    []byte("bplist00"),
+			wantErr: "unsupported binary format",
+		},
+		{
+			name: "zero offset size",
+			data: buildBinary(dict, func(t *trailer) {
+				t.offsetSize = 0
+			}),
+			wantErr: "invalid binary trailer",
+		},
+		{
+			name: "oversized ref size",
+			data: buildBinary(dict, func(t *trailer) {
+				t.refSize = 9
+			}),
+			wantErr: "invalid binary trailer",
+		},
+		{
+			name: "top object out of range",
+			data: buildBinary(dict, func(t *trailer) {
+				t.topObject = 3
+			}),
+			wantErr: "invalid binary object count",
+		},
+		{
+			name: "no objects",
+			data: buildBinary(dict, func(t *trailer) {
+				t.numObjects = 0
+			}),
+			wantErr: "invalid binary object count",
+		},
+		{
+			name: "offset table overlaps trailer",
+			data: buildBinary(dict, func(t *trailer) {
+				t.tableOffset += 2
+			}),
+			wantErr: "invalid binary offset table",
+		},
+		{
+			name: "offset table inside header",
+			data: buildBinary(dict, func(t *trailer) {
+				t.tableOffset = 4
+			}),
+			wantErr: "invalid binary offset table",
+		},
+		{
+			name: "object count larger than data",
+			data: buildBinary(dict, func(t *trailer) {
+				t.numObjects = 1 << 40
+			}),
+			wantErr: "invalid binary offset table",
+		},
+		{
+			name: "table offset overflows",
+			data: buildBinary(dict, func(t *trailer) {
+				t.offsetSize = 8
+				t.tableOffset = ^uint64(0) - 8
+			}),
+			wantErr: "invalid binary offset table",
+		},
+		{
+			name: "object offset past offset table",
+			data: func() []byte {
+				data := buildBinary(dict, nil)
+				// Point the last object at the offset table itself.
+				data[len(data)-33] = byte(len(data) - 32 - len(dict))
+				return data
+			}(),
+			wantErr: "out of range",
+		},
+		{
+			name: "dangling reference",
+			data: buildBinary([][]byte{
+				{0xA1, 0x05},
+			}, nil),
+			wantErr: "invalid object reference 5",
+		},
+		{
+			name: "cyclic array",
+			data: buildBinary([][]byte{
+				{0xA1, 0x00},
+			}, nil),
+			wantErr: "too deep or cyclic",
+		},
+		{
+			name: "non-string key",
+			data: buildBinary([][]byte{
+				{0xD1, 0x01, 0x01},
+				{0x10, 0x01},
+			}, nil),
+			wantErr: "dictionary key is int64",
+		},
+		{
+			name: "string past end of data",
+			data: buildBinary([][]byte{
+				{0x5F, 0x10, 0x28, 'x'},
+			}, nil),
+			wantErr: "past end of data",
+		},
+		{
+			name: "length larger than data",
+			data: buildBinary([][]byte{
+				{0x5F, 0x13, 0, 0, 0, 0x01, 0, 0, 0, 0},
+			}, nil),
+			wantErr: "length 4294967296 out of range",
+		},
+		{
+			name: "bad length marker",
+			data: buildBinary([][]byte{
+				{0x5F, 0x50},
+			}, nil),
+			wantErr: "invalid length marker",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Decode() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
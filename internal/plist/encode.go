@@ -0,0 +1,106 @@
+package plist
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+`
+
+// EncodeXML writes v as an XML property list in the layout Xcode uses:
+// tab indentation and dictionary keys in sorted order.
+func EncodeXML(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xmlHeader)
+	if err := encodeXMLValue(&buf, v, 0); err != nil {
+		return nil, err
+	}
+	buf.WriteString("</plist>\n")
+	return buf.Bytes(), nil
+}
+
+func encodeXMLValue(buf *bytes.Buffer, v interface{}, depth int) error {
+	indent := strings.Repeat("\t", depth)
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 0 {
+			buf.WriteString(indent + "<dict/>\n")
+			return nil
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		buf.WriteString(indent + "<dict>\n")
+		for _, k := range keys {
+			buf.WriteString(indent + "\t<key>" + escapeXML(k) + "</key>\n")
+			if err := encodeXMLValue(buf, val[k], depth+1); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(indent + "</dict>\n")
+
+	case []interface{}:
+		if len(val) == 0 {
+			buf.WriteString(indent + "<array/>\n")
+			return nil
+		}
+		buf.WriteString(indent + "<array>\n")
+		for _, item := range val {
+			if err := encodeXMLValue(buf, item, depth+1); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(indent + "</array>\n")
+
+	case []string:
+		items := make([]interface{}, len(val))
+		for i, s := range val {
+			items[i] = s
+		}
+		return encodeXMLValue(buf, items, depth)
+
+	case string:
+		buf.WriteString(indent + "<string>" + escapeXML(val) + "</string>\n")
+	case bool:
+		if val {
+			buf.WriteString(indent + "<true/>\n")
+		} else {
+			buf.WriteString(indent + "<false/>\n")
+		}
+	case int:
+		buf.WriteString(indent + "<integer>" + strconv.Itoa(val) + "</integer>\n")
+	case int64:
+		buf.WriteString(indent + "<integer>" + strconv.FormatInt(val, 10) + "</integer>\n")
+	case UID:
+		buf.WriteString(indent + "<integer>" + strconv.FormatUint(uint64(val), 10) + "</integer>\n")
+	case float64:
+		buf.WriteString(indent + "<real>" + strconv.FormatFloat(val, 'g', -1, 64) + "</real>\n")
+	case []byte:
+		buf.WriteString(indent + "<data>" + base64.StdEncoding.EncodeToString(val) + "</data>\n")
+	case time.Time:
+		buf.WriteString(indent + "<date>" + val.UTC().Format(time.RFC3339) + "</date>\n")
+	default:
+		return fmt.Errorf("plist: cannot encode %T", v)
+	}
+
+	return nil
+}
+
+func escapeXML(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,200 @@
+package plist
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// decodeOpenStep decodes the ASCII (OpenStep) format used by
+// project.pbxproj. Unquoted words and numbers are returned as strings.
+func decodeOpenStep(data []byte) (interface{}, error) {
+	d := &openStepDecoder{src: data}
+	v, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+	d.skip()
+	if d.pos < len(d.src) {
+		return nil, d.errorf("unexpected %q after root value", d.src[d.pos])
+	}
+	return v, nil
+}
+
+type openStepDecoder struct {
+	src []byte
+	pos int
+}
+
+func (d *openStepDecoder) errorf(format string, args ...interface{}) error {
+	line := 1 + strings.Count(string(d.src[:min(d.pos, len(d.src))]), "\n")
+	return fmt.Errorf("plist: line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// skip advances past whitespace and comments.
+func (d *openStepDecoder) skip() {
+	for d.pos < len(d.src) {
+		switch c := d.src[d.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			d.pos++
+		case c == '/' && d.pos+1 < len(d.src) && d.src[d.pos+1] == '/':
+			for d.pos < len(d.src) && d.src[d.pos] != '\n' {
+				d.pos++
+			}
+		case c == '/' && d.pos+1 < len(d.src) && d.src[d.pos+1] == '*':
+			end := strings.Index(string(d.src[d.pos+2:]), "*/")
+			if end < 0 {
+				d.pos = len(d.src)
+				return
+			}
+			d.pos += end + 4
+		default:
+			return
+		}
+	}
+}
+
+func (d *openStepDecoder) expect(c byte) error {
+	d.skip()
+	if d.pos >= len(d.src) || d.src[d.pos] != c {
+		if d.pos >= len(d.src) {
+			return d.errorf("expected %q, found end of input", c)
+		}
+		return d.errorf("expected %q, found %q", c, d.src[d.pos])
+	}
+	d.pos++
+	return nil
+}
+
+func (d *openStepDecoder) value() (interface{}, error) {
+	d.skip()
+	if d.pos >= len(d.src) {
+		return nil, d.errorf("unexpected end of input")
+	}
+
+	switch d.src[d.pos] {
+	case '{':
+		d.pos++
+		dict := make(map[string]interface{})
+		for {
+			d.skip()
+			if d.pos < len(d.src) && d.src[d.pos] == '}' {
+				d.pos++
+				return dict, nil
+			}
+			key, err := d.value()
+			if err != nil {
+				return nil, err
+			}
+			k, ok := key.(string)
+			if !ok {
+				return nil, d.errorf("dictionary key is %T, not a string", key)
+			}
+			if err := d.expect('='); err != nil {
+				return nil, err
+			}
+			v, err := d.value()
+			if err != nil {
+				return nil, err
+			}
+			if err := d.expect(';'); err != nil {
+				return nil, err
+			}
+			dict[k] = v
+		}
+
+	case '(':
+		d.pos++
+		arr := []interface{}{}
+		for {
+			d.skip()
+			if d.pos < len(d.src) && d.src[d.pos] == ')' {
+				d.pos++
+				return arr, nil
+			}
+			v, err := d.value()
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+			d.skip()
+			if d.pos < len(d.src) && d.src[d.pos] == ',' {
+				d.pos++
+			} else if d.pos >= len(d.src) || d.src[d.pos] != ')' {
+				return nil, d.errorf("expected ',' or ')' in array")
+			}
+		}
+
+	case '<':
+		end := strings.IndexByte(string(d.src[d.pos:]), '>')
+		if end < 0 {
+			return nil, d.errorf("unterminated data")
+		}
+		raw := strings.Join(strings.Fields(string(d.src[d.pos+1:d.pos+end])), "")
+		d.pos += end + 1
+		b, err := hex.DecodeString(raw)
+		if err != nil {
+			return nil, d.errorf("invalid data: %v", err)
+		}
+		return b, nil
+
+	case '"', '\'':
+		return d.quoted()
+
+	default:
+		start := d.pos
+		for d.pos < len(d.src) && isOpenStepWordChar(d.src[d.pos]) {
+			d.pos++
+		}
+		if d.pos == start {
+			return nil, d.errorf("unexpected %q", d.src[d.pos])
+		}
+		return string(d.src[start:d.pos]), nil
+	}
+}
+
+func (d *openStepDecoder) quoted() (string, error) {
+	quote := d.src[d.pos]
+	d.pos++
+	var sb strings.Builder
+	for d.pos < len(d.src) {
+		c := d.src[d.pos]
+		switch {
+		case c == quote:
+			d.pos++
+			return sb.String(), nil
+		case c == '\\' && d.pos+1 < len(d.src):
+			d.pos++
+			switch e := d.src[d.pos]; e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case 'U':
+				if d.pos+4 < len(d.src) {
+					if r, err := strconv.ParseUint(string(d.src[d.pos+1:d.pos+5]), 16, 32); err == nil {
+						sb.WriteRune(rune(r))
+						d.pos += 4
+						break
+					}
+				}
+				sb.WriteByte(e)
+			default:
+				sb.WriteByte(e)
+			}
+			d.pos++
+		default:
+			sb.WriteByte(c)
+			d.pos++
+		}
+	}
+	return "", d.errorf("unterminated string")
+}
+
+func isOpenStepWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '$' || c == '/' || c == ':' || c == '.' || c == '-'
+}
@@ -0,0 +1,136 @@
+package plist
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeOpenStep(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    interface{}
+		wantErr string
+	}{
+		{
+			name: "pbxproj",
+			src: `// !$*UTF8*$!
+{
+	archiveVersion = 1;
+	objects = {
+
+/* Begin XCBuildConfiguration section */
+		13B07F941A680F5B00A75B9A /* Debug */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				CODE_SIGN_ENTITLEMENTS = App/App.entitlements;
+				INFOPLIST_KEY_NSCameraUsageDescription = "Scan \"receipts\"";
+				LD_RUNPATH_SEARCH_PATHS = (
+					"$(inherited)",
+					"@executable_path/Frameworks",
+				);
+			};
+			name = Debug;
+		};
+/* End XCBuildConfiguration section */
+	};
+	rootObject = 83CBB9F71A601CBA00E9B192 /* Project object */;
+}
+`,
+			want: map[string]interface{}{
+				"archiveVersion": "1",
+				"objects": map[string]interface{}{
+					"13B07F941A680F5B00A75B9A": map[string]interface{}{
+						"isa": "XCBuildConfiguration",
+						"buildSettings": map[string]interface{}{
+							"CODE_SIGN_ENTITLEMENTS":                 "App/App.entitlements",
+							"INFOPLIST_KEY_NSCameraUsageDescription": `Scan "receipts"`,
+							"LD_RUNPATH_SEARCH_PATHS":                []interface{}{"$(inherited)", "@executable_path/Frameworks"},
+						},
+						"name": "Debug",
+					},
+				},
+				"rootObject": "83CBB9F71A601CBA00E9B192",
+			},
+		},
+		{
+			name: "escapes",
+			src:  `{ a = "tab\there\nnew \U00e9"; b = 'single'; }`,
+			want: map[string]interface{}{"a": "tab\there\nnew é", "b": "single"},
+		},
+		{
+			name: "array without trailing comma",
+			src:  `(one, "two", 3)`,
+			want: []interface{}{"one", "two", "3"},
+		},
+		{
+			name: "empty containers",
+			src:  `{ a = (); b = {}; }`,
+			want: map[string]interface{}{"a": []interface{}{}, "b": map[string]interface{}{}},
+		},
+		{
+			name: "data",
+			src:  `{ d = <0fbd 7770>; }`,
+			want: map[string]interface{}{"d": []byte{0x0f, 0xbd, 0x77, 0x70}},
+		},
+		{
+			name:    "missing semicolon",
+			src:     "{\n\ta = b\n}",
+			wantErr: `line 3: expected ';', found '}'`,
+		},
+		{
+			name:    "unterminated dictionary",
+			src:     "{ a = b;",
+			wantErr: "unexpected end of input",
+		},
+		{
+			name:    "unterminated string",
+			src:     `{ a = "open; }`,
+			wantErr: "unterminated string",
+		},
+		{
+			name:    "unterminated comment",
+			src:     "{ a = b; /* open",
+			wantErr: "unexpected end of input",
+		},
+		{
+			name:    "array separator",
+			src:     `(a b)`,
+			wantErr: "expected ',' or ')' in array",
+		},
+		{
+			name:    "invalid data",
+			src:     `{ d = <0fz>; }`,
+			wantErr: "invalid data",
+		},
+		{
+			name:    "trailing content",
+			src:     `{ } }`,
+			wantErr: "unexpected '}' after root value",
+		},
+		{
+			name:    "non-string key",
+			src:     `{ (a) = b; }`,
+			wantErr: "dictionary key is []interface {}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode([]byte(tt.src))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Decode() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Package plist decodes XML, binary and ASCII (OpenStep, as in
+// project.pbxproj) property lists and encodes XML ones.
+//
+// Values are decoded to map[string]interface{} (dict), []interface{}
+// (array), string, int64 (integer), float64 (real), bool, []byte (data),
+// time.Time (date) and UID (binary keyed-archiver references).
+package plist
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type UID uint64
+
+func Decode(data []byte) (interface{}, error) {
+	if bytes.HasPrefix(data, []byte("bplist")) {
+		return decodeBinary(data)
+	}
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if len(trimmed) > 0 && trimmed[0] != '<' {
+		return decodeOpenStep(trimmed)
+	}
+	return decodeXML(data)
+}
+
+// DecodeDict decodes a property list whose root is a dictionary, such as
+// Info.plist or an entitlements file.
+func DecodeDict(data []byte) (map[string]interface{}, error) {
+	v, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	dict, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("plist root is %T, not a dictionary", v)
+	}
+	return dict, nil
+}
@@ -0,0 +1,180 @@
+package plist
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func decodeXML(data []byte) (interface{}, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("plist: no value found")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("plist: %w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local == "plist" {
+			continue
+		}
+		return decodeXMLValue(dec, start)
+	}
+}
+
+func decodeXMLValue(dec *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	switch start.Name.Local {
+	case "dict":
+		return decodeXMLDict(dec)
+
+	case "array":
+		var arr []interface{}
+		for {
+			tok, err := nextElement(dec)
+			if err != nil {
+				return nil, err
+			}
+			if tok == nil {
+				return arr, nil
+			}
+			v, err := decodeXMLValue(dec, *tok)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+
+	case "true", "false":
+		if err := dec.Skip(); err != nil {
+			return nil, err
+		}
+		return start.Name.Local == "true", nil
+	}
+
+	text, err := elementText(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	switch start.Name.Local {
+	case "string":
+		return text, nil
+	case "integer":
+		text = strings.TrimSpace(text)
+		if n, err := strconv.ParseInt(text, 0, 64); err == nil {
+			return n, nil
+		}
+		n, err := strconv.ParseUint(text, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("plist: invalid integer %q", text)
+		}
+		return int64(n), nil
+	case "real":
+		f, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return nil, fmt.Errorf("plist: invalid real %q", text)
+		}
+		return f, nil
+	case "data":
+		clean := strings.Map(func(r rune) rune {
+			if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+				return -1
+			}
+			return r
+		}, text)
+		b, err := base64.StdEncoding.DecodeString(clean)
+		if err != nil {
+			return nil, fmt.Errorf("plist: invalid data: %w", err)
+		}
+		return b, nil
+	case "date":
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("plist: invalid date %q", text)
+		}
+		return t, nil
+	default:
+		return nil, fmt.Errorf("plist: unexpected element <%s>", start.Name.Local)
+	}
+}
+
+func decodeXMLDict(dec *xml.Decoder) (map[string]interface{}, error) {
+	dict := make(map[string]interface{})
+
+	for {
+		tok, err := nextElement(dec)
+		if err != nil {
+			return nil, err
+		}
+		if tok == nil {
+			return dict, nil
+		}
+		if tok.Name.Local != "key" {
+			return nil, fmt.Errorf("plist: expected <key> in dict, got <%s>", tok.Name.Local)
+		}
+
+		key, err := elementText(dec)
+		if err != nil {
+			return nil, err
+		}
+
+		valueStart, err := nextElement(dec)
+		if err != nil {
+			return nil, err
+		}
+		if valueStart == nil {
+			return nil, fmt.Errorf("plist: missing value for key %q", key)
+		}
+
+		value, err := decodeXMLValue(dec, *valueStart)
+		if err != nil {
+			return nil, err
+		}
+		dict[key] = value
+	}
+}
+
+// nextElement returns the next child start element, or nil at the end of
+// the enclosing element.
+func nextElement(dec *xml.Decoder) (*xml.StartElement, error) {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("plist: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			return &t, nil
+		case xml.EndElement:
+			return nil, nil
+		}
+	}
+}
+
+func elementText(dec *xml.Decoder) (string, error) {
+	var sb strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", fmt.Errorf("plist: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			sb.Write(t)
+		case xml.EndElement:
+			return sb.String(), nil
+		case xml.StartElement:
+			return "", fmt.Errorf("plist: unexpected element <%s>", t.Name.Local)
+		}
+	}
+}
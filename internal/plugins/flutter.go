@@ -0,0 +1,177 @@
+package plugins
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/sbom"
+	"github.com/hha-nguyen/canopy-cli/internal/strset"
+)
+
+var (
+	pubspecDependencyRe  = regexp.MustCompile(`^  ([A-Za-z0-9_]+):`)
+	registrantJavaRe     = regexp.MustCompile(`Error registering plugin ([A-Za-z0-9_]+), ([A-Za-z0-9_.]+)`)
+	registrantImportRe   = regexp.MustCompile(`#import <([A-Za-z0-9_]+)/([A-Za-z0-9_]+)\.h>`)
+	flutterPluginPathRe  = regexp.MustCompile(`\.symlinks/plugins/([A-Za-z0-9_]+)/`)
+	flutterPluginVersion = regexp.MustCompile(`-(\d+\.\d+\.\d+[^/]*)/?$`)
+)
+
+// flutter collects the native plugins of a Flutter app from the files
+// flutter pub get and the Xcode and Gradle builds generate. Federated
+// plugins (camera_android, camera_avfoundation) are grouped under the
+// package the app depends on (camera).
+func (b *builder) flutter() error {
+	versions := make(map[string]string)
+	var direct []string
+	for _, c := range sbom.CollectFiles([]string{"pubspec.lock"}, b.read).Components {
+		versions[c.Name] = c.Version
+		if c.Direct {
+			direct = append(direct, c.Name)
+		}
+	}
+	direct = append(direct, b.pubspecDependencies()...)
+
+	// owner returns the direct dependency a plugin implementation belongs
+	// to, preferring the longest matching name.
+	owner := func(name string) string {
+		best := ""
+		for _, d := range direct {
+			if (name == d || strings.HasPrefix(name, d+"_")) && len(d) > len(best) {
+				best = d
+			}
+		}
+		if best == "" {
+			return name
+		}
+		return best
+	}
+
+	add := func(name, platform string) *Plugin {
+		plugin := b.addNative(owner(name), name, platform)
+		plugin.dirs = strset.Add(plugin.dirs, ".symlinks/plugins/"+name)
+		return plugin
+	}
+
+	if !b.flutterPluginsDependencies(add) {
+		b.flutterPluginsLegacy(add)
+	}
+
+	for _, dir := range b.podfileExternalSources("ios/Podfile.lock") {
+		if m := flutterPluginPathRe.FindStringSubmatch(dir + "/"); m != nil {
+			add(m[1], "ios")
+		}
+	}
+
+	if data, err := b.read("android/app/src/main/java/io/flutter/plugins/GeneratedPluginRegistrant.java"); err == nil {
+		for _, m := range registrantJavaRe.FindAllStringSubmatch(string(data), -1) {
+			plugin := add(m[1], "android")
+			class := m[2]
+			if i := strings.LastIndex(class, "."); i > 0 {
+				plugin.androidPackages = strset.Add(plugin.androidPackages, class[:i])
+				class = class[i+1:]
+			}
+			plugin.classes = strset.Add(plugin.classes, class)
+		}
+	}
+
+	if data, err := b.read("ios/Runner/GeneratedPluginRegistrant.m"); err == nil {
+		for _, m := range registrantImportRe.FindAllStringSubmatch(string(data), -1) {
+			plugin := add(m[1], "ios")
+			plugin.classes = strset.Add(plugin.classes, m[2])
+		}
+	}
+
+	for pkg, plugin := range b.byPackage {
+		plugin.Direct = slices.Contains(direct, pkg)
+		if plugin.Version == "" {
+			plugin.Version = versions[pkg]
+		}
+	}
+	return nil
+}
+
+// pubspecDependencies returns the packages listed under dependencies in
+// pubspec.yaml.
+func (b *builder) pubspecDependencies() []string {
+	data, err := b.read("pubspec.yaml")
+	if err != nil {
+		return nil
+	}
+	var deps []string
+	inDeps := false
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if line != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "#") {
+			inDeps = strings.TrimSpace(line) == "dependencies:"
+			continue
+		}
+		if m := pubspecDependencyRe.FindStringSubmatch(line); inDeps && m != nil && m[1] != "flutter" {
+			deps = append(deps, m[1])
+		}
+	}
+	return deps
+}
+
+// flutterPluginsDependencies reads .flutter-plugins-dependencies, which
+// lists the plugins per platform with their location in the pub cache.
+func (b *builder) flutterPluginsDependencies(add func(name, platform string) *Plugin) bool {
+	data, err := b.read(".flutter-plugins-dependencies")
+	if err != nil {
+		return false
+	}
+	var deps struct {
+		Plugins map[string][]struct {
+			Name        string `json:"name"`
+			Path        string `json:"path"`
+			NativeBuild *bool  `json:"native_build"`
+		} `json:"plugins"`
+	}
+	if err := json.Unmarshal(data, &deps); err != nil {
+		return false
+	}
+
+	for _, platform := range []string{"ios", "android"} {
+		for _, p := range deps.Plugins[platform] {
+			if p.NativeBuild != nil && !*p.NativeBuild {
+				continue
+			}
+			plugin := add(p.Name, platform)
+			if m := flutterPluginVersion.FindStringSubmatch(p.Path); m != nil && plugin.Package == p.Name {
+				plugin.Version = m[1]
+			}
+			if platform == "android" && p.Path != "" {
+				b.readAndroidSources(plugin, filepath.Join(p.Path, "android"))
+			}
+		}
+	}
+	return true
+}
+
+// flutterPluginsLegacy reads the older .flutter-plugins file of name=path
+// lines.
+func (b *builder) flutterPluginsLegacy(add func(name, platform string) *Plugin) {
+	data, err := b.read(".flutter-plugins")
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		name, dir, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || strings.HasPrefix(name, "#") {
+			continue
+		}
+		if isDir(filepath.Join(dir, "ios")) {
+			add(name, "ios")
+		}
+		if isDir(filepath.Join(dir, "android")) {
+			b.readAndroidSources(add(name, "android"), filepath.Join(dir, "android"))
+		}
+	}
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,308 @@
+// Package plugins describes Flutter and React Native projects: the native
+// plugins their Dart and JavaScript packages bring in, and which plugin
+// introduced a finding located in generated or vendored native code.
+package plugins
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/discover"
+	"github.com/hha-nguyen/canopy-cli/internal/match"
+	"github.com/hha-nguyen/canopy-cli/internal/strset"
+)
+
+const (
+	Flutter     = discover.KindFlutter
+	ReactNative = discover.KindReactNative
+)
+
+// Project is a cross-platform app and its native plugins.
+type Project struct {
+	Framework string `json:"framework"`
+	Name      string `json:"name"`
+	// Platform is apple, google or both, depending on the native folders
+	// the project has.
+	Platform string   `json:"platform"`
+	Plugins  []Plugin `json:"plugins"`
+
+	root string
+}
+
+// Plugin is a Dart or npm package with native code.
+type Plugin struct {
+	Package string `json:"package"`
+	Version string `json:"version,omitempty"`
+	Direct  bool   `json:"direct"`
+	// Native lists the CocoaPods and Gradle project names of the package's
+	// native code. Federated Flutter plugins have one per platform.
+	Native             []string `json:"native,omitempty"`
+	Platforms          []string `json:"platforms,omitempty"`
+	AndroidPermissions []string `json:"android_permissions,omitempty"`
+
+	// dirs are path fragments of directories holding the plugin's code,
+	// classes the identifiers generated registrants use for it.
+	dirs            []string
+	classes         []string
+	androidPackages []string
+}
+
+// Detect describes the Flutter or React Native project rooted at root. It
+// returns nil for other projects.
+func Detect(root string) (*Project, error) {
+	app, ok := discover.DetectApp(root)
+	if !ok || app.Kind != Flutter && app.Kind != ReactNative {
+		return nil, nil
+	}
+
+	p := &Project{Framework: app.Kind, Name: app.Name, Platform: app.Platform, root: root}
+	b := &builder{project: p, byPackage: make(map[string]*Plugin)}
+
+	var err error
+	if app.Kind == Flutter {
+		err = b.flutter()
+	} else {
+		err = b.reactNative()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, plugin := range b.byPackage {
+		sort.Strings(plugin.Native)
+		sort.Strings(plugin.Platforms)
+		sort.Strings(plugin.AndroidPermissions)
+		p.Plugins = append(p.Plugins, *plugin)
+	}
+	sort.Slice(p.Plugins, func(i, j int) bool { return p.Plugins[i].Package < p.Plugins[j].Package })
+	return p, nil
+}
+
+// JSON encodes the project for upload.
+func (p *Project) JSON() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// Attribute returns the plugin that introduced a finding: from its file
+// path, the SDK, permission or component in its evidence, or, in generated
+// registrant files, the native names its message mentions.
+func (p *Project) Attribute(f api.Finding) (Plugin, bool) {
+	for _, file := range []string{f.FilePath, evidenceString(f, "source_file")} {
+		if plugin, ok := p.locate(file); ok {
+			return plugin, true
+		}
+	}
+
+	if sdk := evidenceString(f, "sdk"); sdk != "" {
+		for _, plugin := range p.Plugins {
+			if containsFold(plugin.Native, sdk) {
+				return plugin, true
+			}
+		}
+	}
+
+	if permission := evidenceString(f, "permission"); permission != "" {
+		if plugin, ok := p.unique(func(plugin Plugin) bool { return slices.Contains(plugin.AndroidPermissions, permission) }); ok {
+			return plugin, true
+		}
+	}
+
+	if component := evidenceString(f, "component"); component != "" {
+		if plugin, ok := p.unique(func(plugin Plugin) bool {
+			for _, pkg := range plugin.androidPackages {
+				if strings.HasPrefix(component, pkg+".") {
+					return true
+				}
+			}
+			return false
+		}); ok {
+			return plugin, true
+		}
+	}
+
+	if IsGenerated(f.FilePath) {
+		return p.unique(func(plugin Plugin) bool {
+			for _, id := range append(append([]string{}, plugin.Native...), plugin.classes...) {
+				if match.Identifier(f.Message, id) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	return Plugin{}, false
+}
+
+func (p *Project) locate(rel string) (Plugin, bool) {
+	if rel == "" {
+		return Plugin{}, false
+	}
+	rel = "/" + strings.TrimPrefix(filepath.ToSlash(rel), "./")
+	for _, plugin := range p.Plugins {
+		for _, dir := range plugin.dirs {
+			if strings.Contains(rel, "/"+dir+"/") {
+				return plugin, true
+			}
+		}
+	}
+	return Plugin{}, false
+}
+
+// unique returns the only plugin matching, so shared permissions are not
+// blamed on an arbitrary plugin.
+func (p *Project) unique(match func(Plugin) bool) (Plugin, bool) {
+	var found []Plugin
+	for _, plugin := range p.Plugins {
+		if match(plugin) {
+			found = append(found, plugin)
+		}
+	}
+	if len(found) != 1 {
+		return Plugin{}, false
+	}
+	return found[0], true
+}
+
+var generatedNames = map[string]bool{
+	"GeneratedPluginRegistrant.java":     true,
+	"GeneratedPluginRegistrant.kt":       true,
+	"GeneratedPluginRegistrant.m":        true,
+	"GeneratedPluginRegistrant.h":        true,
+	"GeneratedPluginRegistrant.swift":    true,
+	"Generated.xcconfig":                 true,
+	"flutter_export_environment.sh":      true,
+	"PackageList.java":                   true,
+	"autolinking.json":                   true,
+	"RCTAppDependencyProvider.mm":        true,
+	"RCTModuleProviders.mm":              true,
+	"RCTThirdPartyComponentsProvider.mm": true,
+}
+
+// IsGenerated reports whether rel is native code generated by the Flutter
+// or React Native tooling.
+func IsGenerated(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	return generatedNames[path.Base(rel)] || strings.Contains(rel, "/generated/autolinking/") ||
+		strings.Contains(rel, "ios/build/generated/")
+}
+
+type builder struct {
+	project   *Project
+	byPackage map[string]*Plugin
+}
+
+func (b *builder) read(rel string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(b.project.root, filepath.FromSlash(rel)))
+}
+
+// plugin returns the entry of a package, creating it on first use.
+func (b *builder) plugin(pkg string) *Plugin {
+	plugin, ok := b.byPackage[pkg]
+	if !ok {
+		plugin = &Plugin{Package: pkg}
+		b.byPackage[pkg] = plugin
+	}
+	return plugin
+}
+
+// addNative records native code of a package for a platform.
+func (b *builder) addNative(pkg, native, platform string) *Plugin {
+	plugin := b.plugin(pkg)
+	if native != "" && !slices.Contains(plugin.Native, native) {
+		plugin.Native = append(plugin.Native, native)
+	}
+	if native != "" && platform == "ios" {
+		plugin.dirs = strset.Add(plugin.dirs, "Pods/"+native)
+	}
+	if platform != "" && !slices.Contains(plugin.Platforms, platform) {
+		plugin.Platforms = append(plugin.Platforms, platform)
+	}
+	return plugin
+}
+
+var (
+	manifestPermissionRe = regexp.MustCompile(`<uses-permission(?:-sdk-23)?\s[^>]*android:name\s*=\s*"([^"]+)"`)
+	manifestPackageRe    = regexp.MustCompile(`<manifest\s[^>]*package\s*=\s*"([^"]+)"`)
+	gradleNamespaceRe    = regexp.MustCompile(`(?m)^\s*namespace\s*=?\s*["']([^"']+)["']`)
+)
+
+// readAndroidSources records the permissions and package of a plugin's
+// Android library at dir, an absolute or project-relative path.
+func (b *builder) readAndroidSources(plugin *Plugin, dir string) {
+	read := func(rel string) ([]byte, error) {
+		if filepath.IsAbs(dir) {
+			return os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		}
+		return b.read(path.Join(dir, rel))
+	}
+
+	if data, err := read("src/main/AndroidManifest.xml"); err == nil {
+		for _, m := range manifestPermissionRe.FindAllStringSubmatch(string(data), -1) {
+			plugin.AndroidPermissions = strset.Add(plugin.AndroidPermissions, m[1])
+		}
+		if m := manifestPackageRe.FindStringSubmatch(string(data)); m != nil {
+			plugin.androidPackages = strset.Add(plugin.androidPackages, m[1])
+		}
+	}
+	for _, name := range []string{"build.gradle", "build.gradle.kts"} {
+		if data, err := read(name); err == nil {
+			if m := gradleNamespaceRe.FindStringSubmatch(string(data)); m != nil {
+				plugin.androidPackages = strset.Add(plugin.androidPackages, m[1])
+			}
+		}
+	}
+}
+
+var (
+	externalSourceRe = regexp.MustCompile(`^  "?([^":]+)"?:\s*$`)
+	externalPathRe   = regexp.MustCompile(`^    :(?:path|podspec):\s*"?([^"]+)"?\s*$`)
+)
+
+// podfileExternalSources returns the local path of each pod installed from
+// the project tree, relative to the project root.
+func (b *builder) podfileExternalSources(lockfile string) map[string]string {
+	data, err := b.read(lockfile)
+	if err != nil {
+		return nil
+	}
+
+	sources := make(map[string]string)
+	inSection := false
+	pod := ""
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if line != "" && !strings.HasPrefix(line, " ") {
+			inSection = line == "EXTERNAL SOURCES:"
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		if m := externalSourceRe.FindStringSubmatch(line); m != nil {
+			pod = m[1]
+		} else if m := externalPathRe.FindStringSubmatch(line); m != nil && pod != "" {
+			sources[pod] = path.Clean(path.Join(path.Dir(lockfile), m[1]))
+		}
+	}
+	return sources
+}
+
+func evidenceString(f api.Finding, key string) string {
+	s, _ := f.Evidence[key].(string)
+	return s
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+func testProject() *Project {
+	return &Project{
+		Framework: Flutter,
+		Plugins: []Plugin{
+			{
+				Package:            "camera",
+				Native:             []string{"camera_android", "camera_avfoundation"},
+				Platforms:          []string{"android", "ios"},
+				AndroidPermissions: []string{"CAMERA", "RECORD_AUDIO"},
+				dirs:               []string{".symlinks/plugins/camera_avfoundation", ".symlinks/plugins/camera_android"},
+				classes:            []string{"CameraPlugin"},
+				androidPackages:    []string{"io.flutter.plugins.camera"},
+			},
+			{
+				Package:            "geolocator",
+				Native:             []string{"geolocator_apple"},
+				Platforms:          []string{"android", "ios"},
+				AndroidPermissions: []string{"ACCESS_FINE_LOCATION", "RECORD_AUDIO"},
+				dirs:               []string{"Pods/geolocator_apple"},
+				classes:            []string{"GeolocatorPlugin"},
+				androidPackages:    []string{"com.baseflow.geolocator"},
+			},
+		},
+	}
+}
+
+func TestAttribute(t *testing.T) {
+	tests := []struct {
+		name    string
+		finding api.Finding
+		want    string
+	}{
+		{
+			name:    "file in plugin directory",
+			finding: api.Finding{FilePath: "ios/.symlinks/plugins/camera_avfoundation/ios/Classes/CameraPlugin.m"},
+			want:    "camera",
+		},
+		{
+			name:    "vendored pod",
+			finding: api.Finding{FilePath: "./ios/Pods/geolocator_apple/PrivacyInfo.xcprivacy"},
+			want:    "geolocator",
+		},
+		{
+			name: "source file evidence",
+			finding: api.Finding{FilePath: "ios/Runner/Info.plist",
+				Evidence: map[string]interface{}{"source_file": "ios/.symlinks/plugins/camera_avfoundation/ios/Classes/Camera.m"}},
+			want: "camera",
+		},
+		{
+			name:    "directory name prefix is not a match",
+			finding: api.Finding{FilePath: "ios/Pods/geolocator_apple_extra/File.m"},
+		},
+		{
+			name:    "sdk evidence",
+			finding: api.Finding{Evidence: map[string]interface{}{"sdk": "Camera_AVFoundation"}},
+			want:    "camera",
+		},
+		{
+			name:    "permission only one plugin declares",
+			finding: api.Finding{FilePath: "android/app/src/main/AndroidManifest.xml", Evidence: map[string]interface{}{"permission": "CAMERA"}},
+			want:    "camera",
+		},
+		{
+			name:    "permission several plugins declare",
+			finding: api.Finding{FilePath: "android/app/src/main/AndroidManifest.xml", Evidence: map[string]interface{}{"permission": "RECORD_AUDIO"}},
+		},
+		{
+			name:    "component in plugin package",
+			finding: api.Finding{Evidence: map[string]interface{}{"component": "com.baseflow.geolocator.LocationService"}},
+			want:    "geolocator",
+		},
+		{
+			name:    "component in package with the same prefix",
+			finding: api.Finding{Evidence: map[string]interface{}{"component": "com.baseflow.geolocatorx.Service"}},
+		},
+		{
+			name:    "generated registrant naming a class",
+			finding: api.Finding{FilePath: "ios/Runner/GeneratedPluginRegistrant.m", Message: "GeolocatorPlugin uses location"},
+			want:    "geolocator",
+		},
+		{
+			name:    "generated registrant naming part of an identifier",
+			finding: api.Finding{FilePath: "ios/Runner/GeneratedPluginRegistrant.m", Message: "MyCameraPluginWrapper registered"},
+		},
+		{
+			name:    "identifier outside generated code",
+			finding: api.Finding{FilePath: "ios/Runner/AppDelegate.swift", Message: "CameraPlugin is referenced"},
+		},
+	}
+
+	p := testProject()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := p.Attribute(tt.finding)
+			if ok != (tt.want != "") || got.Package != tt.want {
+				t.Errorf("Attribute() = %q, %v, want %q", got.Package, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsGenerated(t *testing.T) {
+	tests := []struct {
+		rel  string
+		want bool
+	}{
+		{"android/app/src/main/java/io/flutter/plugins/GeneratedPluginRegistrant.java", true},
+		{"ios/Runner/GeneratedPluginRegistrant.m", true},
+		{"ios/Flutter/Generated.xcconfig", true},
+		{"android/app/build/generated/autolinking/src/main/java/com/facebook/react/PackageList.java", true},
+		{"ios/build/generated/ios/RCTModuleProviders.mm", true},
+		{"ios/Runner/AppDelegate.swift", false},
+		{"lib/generated/strings.dart", false},
+	}
+	for _, tt := range tests {
+		if got := IsGenerated(tt.rel); got != tt.want {
+			t.Errorf("IsGenerated(%q) = %v, want %v", tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestDetectFlutter(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"pubspec.yaml": "name: app\ndependencies:\n  flutter:\n    sdk: flutter\n  camera: ^0.10.0\n",
+		"pubspec.lock": "packages:\n" +
+			"  camera:\n    dependency: \"direct main\"\n    source: hosted\n    version: \"0.10.5\"\n" +
+			"  camera_avfoundation:\n    dependency: transitive\n    source: hosted\n    version: \"0.9.13\"\n",
+		".flutter-plugins-dependencies": `{"plugins": {` +
+			`"ios": [{"name": "camera_avfoundation", "path": "/pub/camera_avfoundation-0.9.13/"}],` +
+			`"android": [{"name": "camera_android", "path": "/pub/camera_android-0.10.8/"}]}}`,
+		"ios/Runner/AppDelegate.swift": "",
+		"android/app/build.gradle":     "",
+	}
+	for rel, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p, err := Detect(root)
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if p == nil || len(p.Plugins) != 1 {
+		t.Fatalf("Detect() = %+v, want one plugin", p)
+	}
+	plugin := p.Plugins[0]
+	if plugin.Package != "camera" || !plugin.Direct || plugin.Version != "0.10.5" {
+		t.Errorf("plugin = %s %s direct=%v, want camera 0.10.5 direct", plugin.Package, plugin.Version, plugin.Direct)
+	}
+	if len(plugin.Platforms) != 2 || plugin.Platforms[0] != "android" || plugin.Platforms[1] != "ios" {
+		t.Errorf("Platforms = %v, want [android ios]", plugin.Platforms)
+	}
+	if got, ok := p.Attribute(api.Finding{FilePath: "ios/.symlinks/plugins/camera_avfoundation/ios/PrivacyInfo.xcprivacy"}); !ok || got.Package != "camera" {
+		t.Errorf("Attribute() = %q, %v, want camera", got.Package, ok)
+	}
+}
@@ -0,0 +1,142 @@
+package plugins
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/sbom"
+	"github.com/hha-nguyen/canopy-cli/internal/strset"
+)
+
+var gradleProjectDirRe = regexp.MustCompile(`project\(\s*['"]:([^'"]+)['"]\s*\)\.projectDir\s*=\s*new\s+File\([^,]+,\s*['"]([^'"]*node_modules/[^'"]+)['"]`)
+
+// reactNative collects the native modules of a React Native app from the
+// Podfile.lock, android/settings.gradle and the installed node_modules.
+// Pure JavaScript packages are left out.
+func (b *builder) reactNative() error {
+	data, err := b.read("package.json")
+	if err != nil {
+		return err
+	}
+	var pkg struct {
+		Dependencies map[string]string `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return err
+	}
+
+	for pod, dir := range b.podfileExternalSources("ios/Podfile.lock") {
+		if name := npmPackage(dir); name != "" && name != "react-native" {
+			plugin := b.addNative(name, pod, "ios")
+			plugin.dirs = strset.Add(plugin.dirs, "node_modules/"+name)
+		}
+	}
+
+	for _, settings := range []string{"android/settings.gradle", "android/settings.gradle.kts"} {
+		data, err := b.read(settings)
+		if err != nil {
+			continue
+		}
+		for _, m := range gradleProjectDirRe.FindAllStringSubmatch(string(data), -1) {
+			if name := npmPackage(m[2]); name != "" {
+				plugin := b.addNative(name, m[1], "android")
+				plugin.dirs = strset.Add(plugin.dirs, "node_modules/"+name)
+			}
+		}
+	}
+
+	for name := range pkg.Dependencies {
+		if name != "react-native" {
+			b.nodeModule(name)
+		}
+	}
+
+	versions := make(map[string]string)
+	pods := make(map[string]string)
+	for _, c := range sbom.CollectFiles([]string{"package-lock.json", "ios/Podfile.lock"}, b.read).Components {
+		switch c.Ecosystem {
+		case sbom.NPM:
+			name := c.Name
+			if c.Group != "" {
+				name = c.Group + "/" + c.Name
+			}
+			versions[name] = c.Version
+		case sbom.CocoaPods:
+			pods[c.Name] = c.Version
+		}
+	}
+
+	for name, plugin := range b.byPackage {
+		_, plugin.Direct = pkg.Dependencies[name]
+		if plugin.Version == "" {
+			plugin.Version = versions[name]
+		}
+		for _, native := range plugin.Native {
+			if plugin.Version == "" {
+				plugin.Version = pods[native]
+			}
+		}
+		if plugin.Version == "" {
+			plugin.Version = pkg.Dependencies[name]
+		}
+	}
+	return nil
+}
+
+// nodeModule records the native code of an installed npm package: its
+// podspecs and its Android library.
+func (b *builder) nodeModule(name string) {
+	dir := "node_modules/" + name
+	abs := filepath.Join(b.project.root, filepath.FromSlash(dir))
+
+	var pods []string
+	for _, pattern := range []string{"*.podspec", "ios/*.podspec"} {
+		matches, _ := filepath.Glob(filepath.Join(abs, filepath.FromSlash(pattern)))
+		for _, m := range matches {
+			pods = append(pods, strings.TrimSuffix(filepath.Base(m), ".podspec"))
+		}
+	}
+	android := isDir(filepath.Join(abs, "android"))
+	if len(pods) == 0 && !android {
+		return
+	}
+
+	var plugin *Plugin
+	for _, pod := range pods {
+		plugin = b.addNative(name, pod, "ios")
+	}
+	if android {
+		plugin = b.addNative(name, strings.NewReplacer("@", "", "/", "_").Replace(name), "android")
+		b.readAndroidSources(plugin, dir+"/android")
+	}
+	plugin.dirs = strset.Add(plugin.dirs, dir)
+
+	if data, err := os.ReadFile(filepath.Join(abs, "package.json")); err == nil {
+		var meta struct {
+			Version string `json:"version"`
+		}
+		if json.Unmarshal(data, &meta) == nil {
+			plugin.Version = meta.Version
+		}
+	}
+}
+
+// npmPackage returns the package a node_modules path belongs to, or "".
+func npmPackage(p string) string {
+	i := strings.LastIndex(p, "node_modules/")
+	if i < 0 {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(p[i+len("node_modules/"):], "/"), "/")
+	if parts[0] == "" {
+		return ""
+	}
+	if strings.HasPrefix(parts[0], "@") && len(parts) > 1 {
+		return path.Join(parts[0], parts[1])
+	}
+	return parts[0]
+}
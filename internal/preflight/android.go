@@ -0,0 +1,710 @@
+package preflight
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/strset"
+)
+
+const (
+	RuleTargetSdkBelow       = "GPL-SDK-001"
+	RuleTargetSdkMissing     = "GPL-SDK-002"
+	RuleMinSdkAboveTarget    = "GPL-SDK-003"
+	RuleRestrictedPermission = "GPL-PERM-001"
+	RuleUndeclaredPermission = "GPL-PERM-002"
+	RuleLegacyStorage        = "GPL-PERM-003"
+	RuleExportedMissing      = "GPL-EXPORT-001"
+	RuleExportedUnprotected  = "GPL-EXPORT-002"
+	RuleInvalidManifestXML   = "GPL-MANIFEST-001"
+)
+
+const (
+	targetSdkDocsURL  = "https://developer.android.com/google/play/requirements/target-sdk"
+	permissionDocsURL = "https://support.google.com/googleplay/android-developer/answer/9888170"
+	exportedDocsURL   = "https://developer.android.com/guide/topics/manifest/activity-element#exported"
+)
+
+// targetSdkDeadlines are the Play target API level requirements for app
+// updates, in date order.
+var targetSdkDeadlines = []struct {
+	Deadline time.Time
+	Level    int
+}{
+	{time.Date(2023, 8, 31, 0, 0, 0, 0, time.UTC), 33},
+	{time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC), 34},
+	{time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC), 35},
+	{time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC), 36},
+}
+
+var now = time.Now
+
+var dangerousPermissions = strset.Of(
+	"READ_CALENDAR", "WRITE_CALENDAR", "CAMERA", "READ_CONTACTS", "WRITE_CONTACTS",
+	"GET_ACCOUNTS", "ACCESS_FINE_LOCATION", "ACCESS_COARSE_LOCATION",
+	"ACCESS_BACKGROUND_LOCATION", "RECORD_AUDIO", "READ_PHONE_STATE",
+	"READ_PHONE_NUMBERS", "CALL_PHONE", "ANSWER_PHONE_CALLS", "READ_CALL_LOG",
+	"WRITE_CALL_LOG", "ADD_VOICEMAIL", "USE_SIP", "PROCESS_OUTGOING_CALLS",
+	"BODY_SENSORS", "BODY_SENSORS_BACKGROUND", "ACTIVITY_RECOGNITION", "SEND_SMS",
+	"RECEIVE_SMS", "READ_SMS", "RECEIVE_WAP_PUSH", "RECEIVE_MMS",
+	"READ_EXTERNAL_STORAGE", "WRITE_EXTERNAL_STORAGE", "ACCESS_MEDIA_LOCATION",
+	"READ_MEDIA_IMAGES", "READ_MEDIA_VIDEO", "READ_MEDIA_AUDIO",
+	"READ_MEDIA_VISUAL_USER_SELECTED", "BLUETOOTH_SCAN", "BLUETOOTH_CONNECT",
+	"BLUETOOTH_ADVERTISE", "UWB_RANGING", "NEARBY_WIFI_DEVICES", "POST_NOTIFICATIONS",
+)
+
+// restrictedPermissions need a declaration form in the Play Console,
+// keyed by permission and naming the policy.
+var restrictedPermissions = map[string]string{
+	"READ_SMS":                   "SMS and Call Log",
+	"SEND_SMS":                   "SMS and Call Log",
+	"RECEIVE_SMS":                "SMS and Call Log",
+	"RECEIVE_MMS":                "SMS and Call Log",
+	"RECEIVE_WAP_PUSH":           "SMS and Call Log",
+	"READ_CALL_LOG":              "SMS and Call Log",
+	"WRITE_CALL_LOG":             "SMS and Call Log",
+	"PROCESS_OUTGOING_CALLS":     "SMS and Call Log",
+	"ACCESS_BACKGROUND_LOCATION": "Background location",
+	"QUERY_ALL_PACKAGES":         "Package visibility",
+	"MANAGE_EXTERNAL_STORAGE":    "All files access",
+	"REQUEST_INSTALL_PACKAGES":   "Request install packages",
+	"USE_EXACT_ALARM":            "Exact alarms",
+	"USE_FULL_SCREEN_INTENT":     "Full-screen intents",
+	"READ_MEDIA_IMAGES":          "Photo and video permissions",
+	"READ_MEDIA_VIDEO":           "Photo and video permissions",
+}
+
+var (
+	gradleTargetSdkRe = regexp.MustCompile(`(?m)^\s*targetSdk(?:Version)?\s*(?:=|\(|\s)\s*(.+?)\)?\s*(?://.*)?$`)
+	gradleMinSdkRe    = regexp.MustCompile(`(?m)^\s*minSdk(?:Version)?\s*(?:=|\(|\s)\s*(.+?)\)?\s*(?://.*)?$`)
+	permissionRefRe   = regexp.MustCompile(`(?:Manifest\.permission\.|"android\.permission\.)([A-Z_]+)`)
+	versionCatalogRe  = regexp.MustCompile(`libs\.versions\.([\w.]+?)(?:\.get\(\)|$)`)
+)
+
+// androidModule is a Gradle module applying the Android application plugin.
+// Merged is the manifest a previous build left under the module's build
+// outputs, if any.
+type androidModule struct {
+	Dir       string
+	Gradle    string
+	Manifests []string
+	Merged    string
+}
+
+type androidManifest struct {
+	UsesSdk       []manifestTag `xml:"uses-sdk"`
+	Permissions   []manifestTag `xml:"uses-permission"`
+	Permissions23 []manifestTag `xml:"uses-permission-sdk-23"`
+	Application   struct {
+		Activities []manifestComponent `xml:"activity"`
+		Aliases    []manifestComponent `xml:"activity-alias"`
+		Services   []manifestComponent `xml:"service"`
+		Receivers  []manifestComponent `xml:"receiver"`
+		Providers  []manifestComponent `xml:"provider"`
+	} `xml:"application"`
+}
+
+type manifestTag struct {
+	Attrs []xml.Attr `xml:",any,attr"`
+}
+
+type manifestComponent struct {
+	Attrs         []xml.Attr             `xml:",any,attr"`
+	IntentFilters []manifestIntentFilter `xml:"intent-filter"`
+}
+
+type manifestIntentFilter struct {
+	Actions    []manifestTag `xml:"action"`
+	Categories []manifestTag `xml:"category"`
+}
+
+// isLauncher reports whether a component has a MAIN/LAUNCHER intent
+// filter, which the launcher needs to start it from outside the app.
+func (c manifestComponent) isLauncher() bool {
+	for _, filter := range c.IntentFilters {
+		if hasTagNamed(filter.Actions, "android.intent.action.MAIN") &&
+			hasTagNamed(filter.Categories, "android.intent.category.LAUNCHER") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTagNamed(tags []manifestTag, name string) bool {
+	for _, tag := range tags {
+		if v, _ := attr(tag.Attrs, "name"); v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// attr returns an android: attribute by local name.
+func attr(attrs []xml.Attr, name string) (string, bool) {
+	for _, a := range attrs {
+		if a.Name.Local == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+func checkAndroid(p *Project) []api.Finding {
+	var findings []api.Finding
+	for _, m := range p.androidModules() {
+		findings = append(findings, checkAndroidModule(p, m)...)
+	}
+	return findings
+}
+
+// AndroidPermissions returns the permissions declared by the app modules'
+// manifests, without the android.permission. prefix, mapped to the first
+// manifest declaring each. Permissions only libraries contribute are mapped
+// to the merged manifest.
+func (p *Project) AndroidPermissions() map[string]string {
+	perms := make(map[string]string)
+	for _, m := range p.androidModules() {
+		rels := m.Manifests
+		if m.Merged != "" {
+			rels = append(append([]string{}, rels...), m.Merged)
+		}
+		for _, rel := range rels {
+			data, err := p.Read(rel)
+			if err != nil {
+				continue
+			}
+			manifest, err := parseAndroidManifest(data)
+			if err != nil {
+				continue
+			}
+			for _, perm := range append(append([]manifestTag{}, manifest.Permissions...), manifest.Permissions23...) {
+				name, _ := attr(perm.Attrs, "name")
+				short := strings.TrimPrefix(name, "android.permission.")
+				if _, seen := perms[short]; !seen && short != "" {
+					perms[short] = rel
+				}
+			}
+		}
+	}
+	return perms
+}
+
+func checkAndroidModule(p *Project, m androidModule) []api.Finding {
+	var findings []api.Finding
+	var manifests []*androidManifest
+	var manifestFiles []string
+
+	for _, rel := range m.Manifests {
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		manifest, err := parseAndroidManifest(data)
+		if err != nil {
+			findings = append(findings, androidFinding(RuleInvalidManifestXML, "AndroidManifest.xml cannot be parsed", "HIGH", rel,
+				fmt.Sprintf("%s is not valid XML: %v", rel, err), nil, nil, ""))
+			continue
+		}
+		manifests = append(manifests, manifest)
+		manifestFiles = append(manifestFiles, rel)
+	}
+
+	// The merged manifest adds what libraries contribute. It is never
+	// reported against: the build regenerates it, so fixes go into the
+	// module's own manifest.
+	var merged *androidManifest
+	if m.Merged != "" {
+		if data, err := p.Read(m.Merged); err == nil {
+			merged, _ = parseAndroidManifest(data)
+		}
+	}
+
+	gradle := ""
+	if data, err := p.Read(m.Gradle); err == nil {
+		gradle = stripLineComments(string(data))
+	}
+	target, targetDeclared := p.resolveSdk(gradle, gradleTargetSdkRe)
+	minSdk, _ := p.resolveSdk(gradle, gradleMinSdkRe)
+	sdkSources := manifests
+	if merged != nil {
+		sdkSources = append(append([]*androidManifest{}, manifests...), merged)
+	}
+	for _, manifest := range sdkSources {
+		for _, sdk := range manifest.UsesSdk {
+			if v, ok := attr(sdk.Attrs, "targetSdkVersion"); ok && target == 0 {
+				target, _ = strconv.Atoi(v)
+				targetDeclared = true
+			}
+			if v, ok := attr(sdk.Attrs, "minSdkVersion"); ok && minSdk == 0 {
+				minSdk, _ = strconv.Atoi(v)
+			}
+		}
+	}
+
+	findings = append(findings, sdkFindings(m, target, targetDeclared, minSdk)...)
+
+	primary := m.Gradle
+	if len(manifestFiles) > 0 {
+		primary = manifestFiles[0]
+	}
+
+	declared := make(map[string]tagAt)
+	for i, manifest := range manifests {
+		for _, perm := range append(append([]manifestTag{}, manifest.Permissions...), manifest.Permissions23...) {
+			name, _ := attr(perm.Attrs, "name")
+			short := strings.TrimPrefix(name, "android.permission.")
+			if _, seen := declared[short]; !seen {
+				declared[short] = tagAt{Tag: perm, File: manifestFiles[i]}
+			}
+		}
+		findings = append(findings, componentFindings(manifestFiles[i], manifest, target, "")...)
+	}
+	if merged != nil {
+		for _, perm := range append(append([]manifestTag{}, merged.Permissions...), merged.Permissions23...) {
+			name, _ := attr(perm.Attrs, "name")
+			short := strings.TrimPrefix(name, "android.permission.")
+			if _, seen := declared[short]; !seen {
+				declared[short] = tagAt{Tag: perm, File: primary, Library: true}
+			}
+		}
+		findings = append(findings, componentFindings(primary, libraryComponents(merged, manifests), target, m.Merged)...)
+	}
+
+	names := make([]string, 0, len(declared))
+	for name := range declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		d := declared[name]
+		if policy, ok := restrictedPermissions[name]; ok || strings.HasPrefix(name, "FOREGROUND_SERVICE_") {
+			if !ok {
+				policy = "Foreground service types"
+			}
+			msg := fmt.Sprintf("%s declares %s, which Google Play only allows after you complete the %s declaration in the Play Console.", d.File, name, policy)
+			action := fmt.Sprintf("Complete the %s declaration in the Play Console, or remove %s if the core feature does not need it", policy, name)
+			if d.Library {
+				msg = fmt.Sprintf("A library adds %s to the merged manifest (%s), which Google Play only allows after you complete the %s declaration in the Play Console.", name, m.Merged, policy)
+				action = fmt.Sprintf(`Complete the %s declaration in the Play Console, or remove %s in %s with tools:node="remove" if the core feature does not need it`, policy, name, d.File)
+			}
+			findings = append(findings, androidFinding(RuleRestrictedPermission, "Restricted permission needs a Play Console declaration", "HIGH", d.File,
+				msg,
+				map[string]interface{}{"permission": name, "policy": policy},
+				&api.Remediation{Action: action},
+				permissionDocsURL))
+		}
+
+		if (name == "READ_EXTERNAL_STORAGE" || name == "WRITE_EXTERNAL_STORAGE") && target >= 33 && !d.Library {
+			if _, capped := attr(d.Tag.Attrs, "maxSdkVersion"); !capped {
+				findings = append(findings, androidFinding(RuleLegacyStorage, "Legacy storage permission", "MEDIUM", d.File,
+					fmt.Sprintf("%s declares %s without android:maxSdkVersion. It has no effect on Android 13+ (targetSdk %d); use the READ_MEDIA_* permissions or the photo picker instead.", d.File, name, target),
+					map[string]interface{}{"permission": name},
+					&api.Remediation{
+						Action:   fmt.Sprintf("Limit %s to the Android versions that use it", name),
+						Template: fmt.Sprintf(`<uses-permission android:name="android.permission.%s" android:maxSdkVersion="32" />`, name),
+					},
+					"https://developer.android.com/about/versions/13/behavior-changes-13#granular-media-permissions"))
+			}
+		}
+	}
+
+	requested := p.requestedPermissions(m)
+	names = names[:0]
+	for name := range requested {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		file := requested[name]
+		if _, ok := declared[name]; ok || !dangerousPermissions[name] {
+			continue
+		}
+		findings = append(findings, androidFinding(RuleUndeclaredPermission, "Permission requested but not declared", "HIGH", primary,
+			fmt.Sprintf("%s requests %s at runtime but the module's AndroidManifest.xml does not declare it, so the request always fails.", file, name),
+			map[string]interface{}{"permission": name, "source_file": file},
+			&api.Remediation{
+				Action:   fmt.Sprintf("Declare %s in AndroidManifest.xml", name),
+				Template: fmt.Sprintf(`<uses-permission android:name="android.permission.%s" />`, name),
+			},
+			"https://developer.android.com/training/permissions/declaring"))
+	}
+
+	return findings
+}
+
+// tagAt is a declared permission and the manifest to report it against.
+// Library is set when only the merged manifest declares it.
+type tagAt struct {
+	Tag     manifestTag
+	File    string
+	Library bool
+}
+
+func sdkFindings(m androidModule, target int, declared bool, minSdk int) []api.Finding {
+	var findings []api.Finding
+	file := m.Gradle
+
+	if !declared {
+		return append(findings, androidFinding(RuleTargetSdkMissing, "targetSdk not declared", "HIGH", file,
+			fmt.Sprintf("%s does not set targetSdk, so it defaults to minSdk and Google Play rejects the upload.", file),
+			nil,
+			&api.Remediation{Action: "Set targetSdk in the android.defaultConfig block", Template: fmt.Sprintf("targetSdk = %d", requiredTargetSdk())},
+			targetSdkDocsURL))
+	}
+	if target == 0 {
+		// Declared through an expression we cannot resolve, e.g.
+		// flutter.targetSdkVersion.
+		return nil
+	}
+
+	if required := requiredTargetSdk(); target < required {
+		findings = append(findings, androidFinding(RuleTargetSdkBelow, "targetSdk below Google Play requirement", "BLOCKER", file,
+			fmt.Sprintf("%s targets API %d. Google Play requires app updates to target API %d or higher.", file, target, required),
+			map[string]interface{}{"target_sdk": target, "required": required},
+			&api.Remediation{
+				Action:   fmt.Sprintf("Raise targetSdk to %d and test the behavior changes of each API level in between", required),
+				Template: fmt.Sprintf("targetSdk = %d", required),
+			},
+			targetSdkDocsURL))
+	}
+
+	if minSdk > target {
+		findings = append(findings, androidFinding(RuleMinSdkAboveTarget, "minSdk above targetSdk", "HIGH", file,
+			fmt.Sprintf("%s sets minSdk %d above targetSdk %d, which fails the build.", file, minSdk, target),
+			map[string]interface{}{"min_sdk": minSdk, "target_sdk": target},
+			&api.Remediation{Action: "Lower minSdk or raise targetSdk so minSdk <= targetSdk"},
+			targetSdkDocsURL))
+	}
+
+	return findings
+}
+
+// componentFindings checks the components of a manifest and reports them
+// against rel. merged is set when the components come from the merged
+// manifest, that is from libraries; their fix is an override in rel rather
+// than an edit of the component itself.
+func componentFindings(rel string, manifest *androidManifest, target int, merged string) []api.Finding {
+	var findings []api.Finding
+
+	groups := []struct {
+		Kind       string
+		Components []manifestComponent
+	}{
+		{"activity", manifest.Application.Activities},
+		{"activity-alias", manifest.Application.Aliases},
+		{"service", manifest.Application.Services},
+		{"receiver", manifest.Application.Receivers},
+		{"provider", manifest.Application.Providers},
+	}
+
+	for _, g := range groups {
+		for _, c := range g.Components {
+			name, _ := attr(c.Attrs, "name")
+			exported, hasExported := attr(c.Attrs, "exported")
+
+			if !hasExported && len(c.IntentFilters) > 0 && (target == 0 || target >= 31) {
+				// Only the launcher entry point must be reachable from
+				// outside the app; anything else stays private unless the
+				// developer decides otherwise.
+				remediation := &api.Remediation{
+					Action:   fmt.Sprintf(`Set android:exported="false" on %s, or "true" if other apps must start it`, name),
+					Template: `android:exported="false"`,
+				}
+				if (g.Kind == "activity" || g.Kind == "activity-alias") && c.isLauncher() {
+					remediation = &api.Remediation{
+						Action:   fmt.Sprintf(`Set android:exported="true" on %s so the launcher can start it`, name),
+						Template: `android:exported="true"`,
+					}
+				}
+				where := "in " + rel
+				if merged != "" {
+					where = fmt.Sprintf("from a library (see %s)", merged)
+					remediation = &api.Remediation{
+						Action: fmt.Sprintf(`Declare <%s android:name="%s" android:exported="false" tools:node="merge" /> in %s, or update the library`, g.Kind, name, rel),
+					}
+				}
+				findings = append(findings, androidFinding(RuleExportedMissing, "Component with intent filter lacks android:exported", "BLOCKER", rel,
+					fmt.Sprintf("<%s %s> %s has an intent filter but no android:exported. Apps targeting Android 12+ fail to install and are rejected by Google Play.", g.Kind, name, where),
+					map[string]interface{}{"component": name, "kind": g.Kind},
+					remediation,
+					exportedDocsURL))
+			}
+
+			if exported != "true" || g.Kind == "activity" || g.Kind == "activity-alias" {
+				continue
+			}
+			if hasPermission(c.Attrs, g.Kind) {
+				continue
+			}
+			severity := "MEDIUM"
+			if g.Kind == "provider" {
+				severity = "HIGH"
+			}
+			where := "in " + rel
+			action := fmt.Sprintf(`Set android:exported="false" on %s, or protect it with android:permission`, name)
+			if merged != "" {
+				where = fmt.Sprintf("from a library (see %s)", merged)
+				action = fmt.Sprintf(`Override %s in %s with android:exported="false" and tools:replace="android:exported" if the app does not need it exported`, name, rel)
+			}
+			findings = append(findings, androidFinding(RuleExportedUnprotected, "Exported component without permission", severity, rel,
+				fmt.Sprintf("<%s %s> %s is exported without a permission, so any app can reach it.", g.Kind, name, where),
+				map[string]interface{}{"component": name, "kind": g.Kind},
+				&api.Remediation{Action: action},
+				exportedDocsURL))
+		}
+	}
+
+	return findings
+}
+
+// libraryComponents returns the components of the merged manifest that none
+// of the module's own manifests declare.
+func libraryComponents(merged *androidManifest, own []*androidManifest) *androidManifest {
+	var declared []string
+	for _, manifest := range own {
+		app := manifest.Application
+		for _, group := range [][]manifestComponent{app.Activities, app.Aliases, app.Services, app.Receivers, app.Providers} {
+			for _, c := range group {
+				if name, ok := attr(c.Attrs, "name"); ok && name != "" {
+					declared = append(declared, name)
+				}
+			}
+		}
+	}
+
+	// The merger expands relative names such as .MainActivity with the
+	// module's namespace, which the Gradle script may hold instead of the
+	// manifest, so they are compared by suffix.
+	ownComponent := func(name string) bool {
+		for _, d := range declared {
+			if name == d || (strings.HasPrefix(d, ".") && strings.HasSuffix(name, d)) ||
+				(!strings.Contains(d, ".") && strings.HasSuffix(name, "."+d)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	keep := func(components []manifestComponent) []manifestComponent {
+		var out []manifestComponent
+		for _, c := range components {
+			if name, _ := attr(c.Attrs, "name"); !ownComponent(name) {
+				out = append(out, c)
+			}
+		}
+		return out
+	}
+
+	var lib androidManifest
+	lib.Application.Activities = keep(merged.Application.Activities)
+	lib.Application.Aliases = keep(merged.Application.Aliases)
+	lib.Application.Services = keep(merged.Application.Services)
+	lib.Application.Receivers = keep(merged.Application.Receivers)
+	lib.Application.Providers = keep(merged.Application.Providers)
+	return &lib
+}
+
+func hasPermission(attrs []xml.Attr, kind string) bool {
+	names := []string{"permission"}
+	if kind == "provider" {
+		names = append(names, "readPermission", "writePermission")
+	}
+	for _, name := range names {
+		if v, ok := attr(attrs, name); ok && v != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func androidFinding(rule, name, severity, file, msg string, evidence map[string]interface{}, remediation *api.Remediation, docs string) api.Finding {
+	return api.Finding{
+		RuleCode:    rule,
+		RuleName:    name,
+		Severity:    severity,
+		Message:     msg,
+		FilePath:    file,
+		Evidence:    evidence,
+		Remediation: remediation,
+		DocsURL:     docs,
+		Platform:    string(api.PlatformGoogle),
+	}
+}
+
+func parseAndroidManifest(data []byte) (*androidManifest, error) {
+	var m androidManifest
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// androidModules finds application modules, their source manifests and the
+// merged manifest of a previous build, which adds the permissions and
+// components contributed by libraries.
+func (p *Project) androidModules() []androidModule {
+	var modules []androidModule
+
+	for _, gradle := range p.FilesNamed("build.gradle", "build.gradle.kts") {
+		data, err := p.Read(gradle)
+		if err != nil {
+			continue
+		}
+		content := string(data)
+		if !strings.Contains(content, "com.android.application") && !strings.Contains(content, "android.application") {
+			continue
+		}
+
+		m := androidModule{Dir: path.Dir(gradle), Gradle: gradle, Merged: p.mergedManifest(path.Dir(gradle))}
+		prefix := m.Dir + "/src/"
+		if m.Dir == "." {
+			prefix = "src/"
+		}
+		m.Manifests = p.Find(func(rel string) bool {
+			return strings.HasPrefix(rel, prefix) && path.Base(rel) == "AndroidManifest.xml" &&
+				!isTestPath(rel) && !strings.HasPrefix(rel, prefix+"test/")
+		})
+		modules = append(modules, m)
+	}
+
+	return modules
+}
+
+// mergedManifest returns the merged manifest under the module's build
+// outputs, preferring a release variant. Build directories are excluded
+// from the project file list, so they are walked directly.
+func (p *Project) mergedManifest(dir string) string {
+	var found []string
+	root := filepath.Join(p.Root, filepath.FromSlash(dir), "build", "intermediates")
+	_ = filepath.WalkDir(root, func(full string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Name() != "AndroidManifest.xml" || d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(p.Root, full)
+		rel = filepath.ToSlash(rel)
+		if strings.Contains(rel, "/intermediates/merged_manifest") {
+			found = append(found, rel)
+		}
+		return nil
+	})
+	if len(found) == 0 {
+		return ""
+	}
+
+	sort.Strings(found)
+	for _, rel := range found {
+		if strings.Contains(strings.ToLower(rel), "release") {
+			return rel
+		}
+	}
+	return found[0]
+}
+
+// resolveSdk reads an SDK level from a Gradle script. Values defined
+// elsewhere (ext properties, gradle.properties, version catalogs) are
+// looked up by name; declared is true when the setting exists at all.
+func (p *Project) resolveSdk(gradle string, re *regexp.Regexp) (level int, declared bool) {
+	m := re.FindStringSubmatch(gradle)
+	if m == nil {
+		return 0, false
+	}
+
+	value := strings.Trim(m[1], `"'`)
+	if n, err := strconv.Atoi(value); err == nil {
+		return n, true
+	}
+
+	name := value
+	if vm := versionCatalogRe.FindStringSubmatch(value); vm != nil {
+		name = vm[1]
+	} else if strings.HasPrefix(value, "flutter.") {
+		return 0, true
+	} else if i := strings.LastIndex(value, "."); i >= 0 {
+		name = value[i+1:]
+	}
+	name = strings.TrimSuffix(name, ".toInt()")
+
+	if n, ok := p.lookupVersion(name); ok {
+		return n, true
+	}
+	return 0, true
+}
+
+// lookupVersion finds a numeric definition of name in Gradle scripts,
+// gradle.properties or libs.versions.toml. Catalog accessors use dots
+// where the catalog may use dashes or underscores.
+func (p *Project) lookupVersion(name string) (int, bool) {
+	key := regexp.QuoteMeta(name)
+	key = strings.ReplaceAll(key, `\.`, `[-_.]`)
+	re := regexp.MustCompile(`(?m)^\s*(?:ext\.|val\s+|def\s+)?` + key + `\s*[=:]?\s*"?(\d+)"?\s*(?:(?://|#).*)?$`)
+
+	files := p.Find(func(rel string) bool {
+		base := path.Base(rel)
+		return base == "gradle.properties" || base == "libs.versions.toml" ||
+			base == "build.gradle" || base == "build.gradle.kts"
+	})
+	for _, rel := range files {
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		if m := re.FindSubmatch(data); m != nil {
+			if n, err := strconv.Atoi(string(m[1])); err == nil {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// requestedPermissions returns permissions referenced in the module's
+// Kotlin and Java sources, mapped to the first file referencing them.
+func (p *Project) requestedPermissions(m androidModule) map[string]string {
+	prefix := m.Dir + "/"
+	if m.Dir == "." {
+		prefix = ""
+	}
+
+	out := make(map[string]string)
+	for _, rel := range p.FilesWithExt(".kt", ".java") {
+		if !strings.HasPrefix(rel, prefix) || isTestPath(rel) || strings.Contains(rel, "/src/test/") {
+			continue
+		}
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		for _, match := range permissionRefRe.FindAllStringSubmatch(stripLineComments(string(data)), -1) {
+			if _, seen := out[match[1]]; !seen {
+				out[match[1]] = rel
+			}
+		}
+	}
+	return out
+}
+
+func requiredTargetSdk() int {
+	level := 0
+	t := now()
+	for _, d := range targetSdkDeadlines {
+		if !t.Before(d.Deadline) {
+			level = d.Level
+		}
+	}
+	return level
+}
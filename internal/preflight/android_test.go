@@ -0,0 +1,202 @@
+package preflight
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+// newTestProject writes files under a temporary root and returns a
+// project listing them.
+func newTestProject(t *testing.T, files map[string]string) *Project {
+	t.Helper()
+	root := t.TempDir()
+	p := &Project{Root: root}
+	for rel, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		p.Files = append(p.Files, rel)
+	}
+	sort.Strings(p.Files)
+	return p
+}
+
+func TestResolveSdk(t *testing.T) {
+	p := newTestProject(t, map[string]string{
+		"build.gradle":              "ext.targetSdkVersion = 34\n",
+		"gradle/libs.versions.toml": "[versions]\nandroid-minSdk = \"24\"\n",
+	})
+
+	tests := []struct {
+		name         string
+		gradle       string
+		re           *regexp.Regexp
+		wantLevel    int
+		wantDeclared bool
+	}{
+		{"groovy target", "  targetSdkVersion 33\n", gradleTargetSdkRe, 33, true},
+		{"kotlin target", "    targetSdk = 35\n", gradleTargetSdkRe, 35, true},
+		{"call syntax", "targetSdkVersion(31)\n", gradleTargetSdkRe, 31, true},
+		{"quoted", "targetSdkVersion \"30\"\n", gradleTargetSdkRe, 30, true},
+		{"trailing comment", "targetSdk = 34 // bump yearly\n", gradleTargetSdkRe, 34, true},
+		{"ext property", "targetSdkVersion rootProject.ext.targetSdkVersion\n", gradleTargetSdkRe, 34, true},
+		{"version catalog", "minSdk = libs.versions.android.minSdk.get().toInt()\n", gradleMinSdkRe, 24, true},
+		{"flutter", "targetSdkVersion flutter.targetSdkVersion\n", gradleTargetSdkRe, 0, true},
+		{"unresolved", "targetSdk = rootProject.extra[\"target\"]\n", gradleTargetSdkRe, 0, true},
+		{"min only", "minSdkVersion 21\n", gradleTargetSdkRe, 0, false},
+		{"min sdk", "minSdkVersion 21\ntargetSdkVersion 34\n", gradleMinSdkRe, 21, true},
+		{"compile sdk is ignored", "compileSdk = 35\n", gradleTargetSdkRe, 0, false},
+		{"missing", "android {}\n", gradleTargetSdkRe, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, declared := p.resolveSdk(tt.gradle, tt.re)
+			if level != tt.wantLevel || declared != tt.wantDeclared {
+				t.Errorf("resolveSdk(%q) = %d, %v, want %d, %v",
+					tt.gradle, level, declared, tt.wantLevel, tt.wantDeclared)
+			}
+		})
+	}
+}
+
+func TestLookupVersion(t *testing.T) {
+	p := newTestProject(t, map[string]string{
+		"gradle.properties": "compileSdkVersion=35\n" +
+			"targetSdkVersionOverride=1\n" +
+			"# minSdkVersion=19\n",
+		"build.gradle": "ext {\n" +
+			"    minSdkVersion = 23\n" +
+			"}\n" +
+			"ext.appTarget = 33\n" +
+			"def legacyTarget = \"30\"\n",
+		"app/build.gradle.kts": "val kotlinTarget = 34 // keep in sync\n",
+		"gradle/libs.versions.toml": "[versions]\n" +
+			"android-targetSdk = \"34\"\n" +
+			"android_compileSdk = \"35\" # latest\n",
+		"app/src/main/Config.kt": "val ignoredTarget = 1\n",
+	})
+
+	tests := []struct {
+		name   string
+		want   int
+		wantOK bool
+	}{
+		{"compileSdkVersion", 35, true},
+		{"minSdkVersion", 23, true},
+		{"appTarget", 33, true},
+		{"legacyTarget", 30, true},
+		{"kotlinTarget", 34, true},
+		{"android.targetSdk", 34, true},
+		{"android.compileSdk", 35, true},
+		{"targetSdkVersion", 0, false},
+		{"ignoredTarget", 0, false},
+		{"missing", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := p.lookupVersion(tt.name)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("lookupVersion(%q) = %d, %v, want %d, %v", tt.name, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCheckAndroidMergedManifest(t *testing.T) {
+	const source = "app/src/main/AndroidManifest.xml"
+	const merged = "app/build/intermediates/merged_manifests/release/AndroidManifest.xml"
+	p := newTestProject(t, map[string]string{
+		"app/build.gradle": "plugins { id 'com.android.application' }\n" +
+			"android {\n    defaultConfig {\n        targetSdk 35\n    }\n}\n",
+		source: `<manifest xmlns:android="http://schemas.android.com/apk/res/android">
+    <uses-permission android:name="android.permission.CAMERA" />
+    <application>
+        <activity android:name=".MainActivity">
+            <intent-filter>
+                <action android:name="android.intent.action.MAIN" />
+            </intent-filter>
+        </activity>
+    </application>
+</manifest>
+`,
+		merged: `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.app">
+    <uses-permission android:name="android.permission.CAMERA" />
+    <uses-permission android:name="android.permission.READ_SMS" />
+    <application>
+        <activity android:name="com.example.app.MainActivity">
+            <intent-filter>
+                <action android:name="android.intent.action.MAIN" />
+            </intent-filter>
+        </activity>
+        <receiver android:name="com.vendor.sdk.PushReceiver">
+            <intent-filter>
+                <action android:name="com.vendor.PUSH" />
+            </intent-filter>
+        </receiver>
+    </application>
+</manifest>
+`,
+	})
+
+	modules := p.androidModules()
+	if len(modules) != 1 {
+		t.Fatalf("androidModules() = %d modules, want 1", len(modules))
+	}
+	if got := modules[0].Manifests; len(got) != 1 || got[0] != source {
+		t.Errorf("Manifests = %v, want [%s]", got, source)
+	}
+	if modules[0].Merged != merged {
+		t.Errorf("Merged = %q, want %q", modules[0].Merged, merged)
+	}
+
+	type key struct{ rule, subject string }
+	got := make(map[key]api.Finding)
+	for _, f := range checkAndroid(p) {
+		if strings.Contains(f.FilePath, "/build/") {
+			t.Errorf("%s reported against %s", f.RuleCode, f.FilePath)
+		}
+		subject, _ := f.Evidence["component"].(string)
+		if subject == "" {
+			subject, _ = f.Evidence["permission"].(string)
+		}
+		got[key{f.RuleCode, subject}] = f
+	}
+
+	tests := []struct {
+		rule         string
+		subject      string
+		wantTemplate bool
+	}{
+		{RuleExportedMissing, ".MainActivity", true},
+		{RuleExportedMissing, "com.vendor.sdk.PushReceiver", false},
+		{RuleRestrictedPermission, "READ_SMS", false},
+	}
+	for _, tt := range tests {
+		f, ok := got[key{tt.rule, tt.subject}]
+		if !ok {
+			t.Errorf("no %s finding for %s", tt.rule, tt.subject)
+			continue
+		}
+		if f.FilePath != source {
+			t.Errorf("%s %s FilePath = %q, want %q", tt.rule, tt.subject, f.FilePath, source)
+		}
+		if hasTemplate := f.Remediation != nil && f.Remediation.Template != ""; hasTemplate != tt.wantTemplate {
+			t.Errorf("%s %s has template = %v, want %v", tt.rule, tt.subject, hasTemplate, tt.wantTemplate)
+		}
+	}
+	if _, ok := got[key{RuleExportedMissing, "com.example.app.MainActivity"}]; ok {
+		t.Error("the app's own activity is reported twice through the merged manifest")
+	}
+}
@@ -0,0 +1,561 @@
+package preflight
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/plist"
+	"github.com/hha-nguyen/canopy-cli/internal/plugins"
+	"github.com/hha-nguyen/canopy-cli/internal/sbom"
+	"github.com/hha-nguyen/canopy-cli/internal/strset"
+)
+
+const (
+	RuleEntitlementsInvalid  = "APL-ENT-001"
+	RuleCapabilityUnused     = "APL-ENT-002"
+	RuleCapabilityMissing    = "APL-ENT-003"
+	RuleBackgroundModeUnused = "APL-ENT-004"
+	RuleAssociatedDomain     = "APL-ENT-005"
+)
+
+// capability describes an entitlement and the code that uses it. Packages
+// are CocoaPods, SwiftPM, Carthage, pub and npm package names, compared
+// case-insensitively, whose iOS code provides the usage. Required means the
+// API fails without the entitlement.
+type capability struct {
+	Key        string
+	Name       string
+	Frameworks []string
+	Symbols    []string
+	Packages   []string
+	Required   bool
+	// UnusedSeverity is the severity of a declared but unused capability;
+	// empty disables the check.
+	UnusedSeverity string
+}
+
+var capabilities = []capability{
+	{Key: "aps-environment", Name: "Push Notifications",
+		Frameworks: []string{"FirebaseMessaging", "OneSignal", "OneSignalFramework"},
+		Symbols:    []string{"registerForRemoteNotifications", "didRegisterForRemoteNotificationsWithDeviceToken"},
+		Packages:   []string{"FirebaseMessaging", "OneSignal", "OneSignalXCFramework", "onesignal-xcframework", "firebase_messaging", "onesignal_flutter", "@react-native-firebase/messaging", "react-native-onesignal", "@react-native-community/push-notification-ios", "react-native-notifications"},
+		Required:   true, UnusedSeverity: "MEDIUM"},
+	{Key: "com.apple.developer.associated-domains", Name: "Associated Domains",
+		Symbols:        []string{"NSUserActivityTypeBrowsingWeb", "continueUserActivity", "continue userActivity", "onContinueUserActivity", "onOpenURL", "webpageURL", "ASAuthorizationPasswordProvider"},
+		Packages:       []string{"Branch", "BranchSDK", "AppsFlyerFramework", "Adjust", "FirebaseDynamicLinks", "uni_links", "app_links", "flutter_branch_sdk", "appsflyer_sdk", "adjust_sdk", "firebase_dynamic_links", "react-native-branch", "react-native-appsflyer", "react-native-adjust", "@react-native-firebase/dynamic-links"},
+		UnusedSeverity: "LOW"},
+	{Key: "com.apple.developer.healthkit", Name: "HealthKit",
+		Frameworks: []string{"HealthKit"}, Symbols: []string{"HKHealthStore"},
+		Packages: []string{"health", "react-native-health", "@kingstinct/react-native-healthkit"},
+		Required: true, UnusedSeverity: "HIGH"},
+	{Key: "com.apple.developer.applesignin", Name: "Sign in with Apple",
+		Symbols:  []string{"ASAuthorizationAppleIDProvider", "SignInWithAppleButton", "ASAuthorizationAppleIDButton"},
+		Packages: []string{"sign_in_with_apple", "the_apple_sign_in", "apple_sign_in", "@invertase/react-native-apple-authentication", "expo-apple-authentication"},
+		Required: true, UnusedSeverity: "MEDIUM"},
+	{Key: "com.apple.developer.in-app-payments", Name: "Apple Pay",
+		Symbols:  []string{"PKPaymentRequest", "PKPaymentAuthorizationController", "PKPaymentAuthorizationViewController", "PayWithApplePayButton"},
+		Packages: []string{"StripeApplePay", "BraintreeApplePay", "pay", "react-native-payments"},
+		Required: true, UnusedSeverity: "MEDIUM"},
+	{Key: "com.apple.developer.homekit", Name: "HomeKit",
+		Frameworks: []string{"HomeKit"}, Symbols: []string{"HMHomeManager"},
+		Required: true, UnusedSeverity: "MEDIUM"},
+	{Key: "com.apple.developer.siri", Name: "Siri",
+		Symbols:  []string{"requestSiriAuthorization"},
+		Packages: []string{"flutter_siri_suggestions", "react-native-siri-shortcut"},
+		Required: true, UnusedSeverity: "MEDIUM"},
+	{Key: "com.apple.developer.nfc.readersession.formats", Name: "NFC Tag Reading",
+		Frameworks: []string{"CoreNFC"},
+		Packages:   []string{"nfc_manager", "flutter_nfc_kit", "react-native-nfc-manager"},
+		Required:   true, UnusedSeverity: "MEDIUM"},
+	{Key: "com.apple.developer.icloud-services", Name: "iCloud",
+		Frameworks: []string{"CloudKit"}, Symbols: []string{"NSUbiquitousKeyValueStore", "CKContainer", "forUbiquityContainerIdentifier", "URLForUbiquityContainerIdentifier"},
+		Packages: []string{"icloud_storage", "react-native-cloud-store", "react-native-icloudstore"},
+		Required: true, UnusedSeverity: "LOW"},
+	{Key: "com.apple.developer.networking.wifi-info", Name: "Access Wi-Fi Information",
+		Symbols:  []string{"CNCopyCurrentNetworkInfo", "NEHotspotNetwork"},
+		Packages: []string{"network_info_plus", "wifi_info_flutter", "wifi_iot", "react-native-wifi-reborn"},
+		Required: true, UnusedSeverity: "LOW"},
+	{Key: "com.apple.developer.game-center", Name: "Game Center",
+		Frameworks: []string{"GameKit"}, Packages: []string{"games_services", "react-native-game-center"},
+		UnusedSeverity: "LOW"},
+	{Key: "com.apple.security.application-groups", Name: "App Groups"},
+	{Key: "keychain-access-groups", Name: "Keychain Sharing"},
+	{Key: "com.apple.developer.icloud-container-identifiers", Name: "iCloud Containers"},
+	{Key: "com.apple.developer.ubiquity-kvstore-identifier", Name: "iCloud Key-Value Storage"},
+	{Key: "com.apple.developer.usernotifications.time-sensitive", Name: "Time Sensitive Notifications"},
+	{Key: "com.apple.developer.usernotifications.communication", Name: "Communication Notifications"},
+	{Key: "com.apple.developer.default-data-protection", Name: "Data Protection"},
+	{Key: "com.apple.developer.associated-appclip-app-identifiers", Name: "App Clips"},
+	{Key: "com.apple.developer.family-controls", Name: "Family Controls"},
+	{Key: "com.apple.developer.kernel.increased-memory-limit", Name: "Increased Memory Limit"},
+}
+
+// backgroundModes maps UIBackgroundModes values to the code that needs
+// them. App Review rejects modes the app does not use (guideline 2.5.4).
+var backgroundModes = map[string]capability{
+	"audio": {Name: "audio playback",
+		Symbols:  []string{"AVAudioSession", "AVPlayer", "AVAudioPlayer", "AVAudioEngine", "AVQueuePlayer"},
+		Packages: []string{"just_audio", "audioplayers", "audio_service", "assets_audio_player", "video_player", "flutter_sound", "flutter_tts", "react-native-track-player", "react-native-sound", "react-native-video", "react-native-tts", "expo-av", "expo-audio", "expo-video"}},
+	"location": {Name: "background location",
+		Symbols:  []string{"allowsBackgroundLocationUpdates", "startMonitoringSignificantLocationChanges", "startMonitoringVisits", "startMonitoring(for", "CLMonitor", "CLBackgroundActivitySession"},
+		Packages: []string{"geolocator", "location", "background_locator_2", "flutter_background_geolocation", "react-native-background-geolocation", "react-native-geolocation-service", "@react-native-community/geolocation", "expo-location"}},
+	"voip": {Name: "VoIP",
+		Frameworks: []string{"PushKit", "CallKit"},
+		Packages:   []string{"flutter_callkit_incoming", "callkeep", "agora_rtc_engine", "flutter_webrtc", "twilio_voice", "react-native-callkeep", "react-native-voip-push-notification", "react-native-agora", "react-native-webrtc", "@twilio/voice-react-native-sdk", "TwilioVoice", "AgoraRtcEngine_iOS", "GoogleWebRTC"}},
+	"fetch": {Name: "background fetch",
+		Symbols:  []string{"setMinimumBackgroundFetchInterval", "performFetchWithCompletionHandler", "performFetchWith", "BGAppRefreshTask"},
+		Packages: []string{"background_fetch", "workmanager", "flutter_background_service", "react-native-background-fetch", "expo-background-fetch", "expo-task-manager"}},
+	"processing": {Name: "background processing",
+		Symbols:  []string{"BGProcessingTask"},
+		Packages: []string{"background_fetch", "workmanager", "flutter_background_service", "react-native-background-fetch", "expo-background-fetch", "expo-task-manager"}},
+	"remote-notification": {Name: "remote notifications",
+		Frameworks: []string{"FirebaseMessaging", "OneSignal", "OneSignalFramework"},
+		Symbols:    []string{"didReceiveRemoteNotification", "registerForRemoteNotifications"},
+		Packages:   []string{"FirebaseMessaging", "OneSignal", "OneSignalXCFramework", "onesignal-xcframework", "firebase_messaging", "onesignal_flutter", "@react-native-firebase/messaging", "react-native-onesignal", "@react-native-community/push-notification-ios", "react-native-notifications"}},
+	"bluetooth-central": {Name: "Bluetooth central",
+		Frameworks: []string{"CoreBluetooth"}, Symbols: []string{"CBCentralManager"},
+		Packages: []string{"flutter_blue_plus", "flutter_blue", "flutter_reactive_ble", "react-native-ble-plx", "react-native-ble-manager"}},
+	"bluetooth-peripheral": {Name: "Bluetooth peripheral",
+		Symbols:  []string{"CBPeripheralManager"},
+		Packages: []string{"flutter_ble_peripheral", "flutter_blue_plus", "react-native-ble-plx", "react-native-ble-manager"}},
+	"external-accessory": {Name: "external accessory",
+		Frameworks: []string{"ExternalAccessory"}},
+	"nearby-interaction": {Name: "Nearby Interaction",
+		Frameworks: []string{"NearbyInteraction"}},
+	"push-to-talk": {Name: "Push to Talk",
+		Frameworks: []string{"PushToTalk"}},
+}
+
+var associatedDomainServices = []string{"applinks:", "webcredentials:", "activitycontinuation:", "appclips:"}
+
+// entitlementsFile is one parsed .entitlements file and the target using it.
+type entitlementsFile struct {
+	Target   XcodeTarget
+	File     string
+	Dict     map[string]interface{}
+	ParseErr error
+}
+
+func checkEntitlements(p *Project) []api.Finding {
+	targets := p.XcodeTargets()
+	files := p.entitlementsFiles(targets)
+	plists := p.targetInfoPlists(targets)
+	if len(files) == 0 && len(plists) == 0 {
+		return nil
+	}
+
+	usage := p.capabilityUsage()
+	crossPlatform := p.crossPlatformFramework()
+
+	var findings []api.Finding
+	declared := make(map[string]bool)
+
+	for _, ef := range files {
+		if ef.ParseErr != nil {
+			findings = append(findings, api.Finding{
+				RuleCode: RuleEntitlementsInvalid,
+				RuleName: "Entitlements file missing or invalid",
+				Severity: "HIGH",
+				Message:  fmt.Sprintf("%s (target %s): %v. Code signing fails or the app ships without its capabilities.", ef.File, ef.Target.Name, ef.ParseErr),
+				FilePath: ef.File,
+				Evidence: map[string]interface{}{"target": ef.Target.Name},
+				Platform: string(api.PlatformApple),
+			})
+			continue
+		}
+
+		for _, c := range capabilities {
+			value, ok := ef.Dict[c.Key]
+			if !ok || !enabled(value) {
+				continue
+			}
+			declared[c.Key] = true
+
+			if c.UnusedSeverity == "" || !ef.Target.IsApp() && ef.Target.ProductType != "" {
+				continue
+			}
+			if _, used := usage[c.Key]; used {
+				continue
+			}
+			findings = append(findings, unusedFinding(RuleCapabilityUnused, "Capability enabled without usage", c.UnusedSeverity, crossPlatform,
+				fmt.Sprintf("%s enables %s (%s) but no source file or dependency uses it.", ef.File, c.Name, c.Key),
+				ef.File, ef.Target.Name, c.Key,
+				fmt.Sprintf("Remove %s from %s and disable the capability in Xcode, or ship the feature that uses it", c.Key, path.Base(ef.File))))
+		}
+
+		if domains, ok := ef.Dict["com.apple.developer.associated-domains"].([]interface{}); ok {
+			findings = append(findings, associatedDomainFindings(ef, domains)...)
+		}
+	}
+
+	for _, c := range capabilities {
+		trigger, used := usage[c.Key]
+		if !c.Required || !used || declared[c.Key] {
+			continue
+		}
+		file := primaryEntitlementsFile(targets, files)
+		if file == "" {
+			file = trigger.File
+		}
+		findings = append(findings, api.Finding{
+			RuleCode: RuleCapabilityMissing,
+			RuleName: "Capability used without entitlement",
+			Severity: "HIGH",
+			Message: fmt.Sprintf("%s uses %s (%s) but no entitlements file declares %s. The API fails at runtime and App Store Connect warns on upload.",
+				trigger.File, c.Name, trigger.Reason, c.Key),
+			FilePath: file,
+			Evidence: map[string]interface{}{"entitlement": c.Key, "source_file": trigger.File, "reason": trigger.Reason},
+			Remediation: &api.Remediation{
+				Action: fmt.Sprintf("Enable the %s capability for the app target in Xcode (Signing & Capabilities)", c.Name),
+			},
+			Platform: string(api.PlatformApple),
+		})
+	}
+
+	for _, ip := range plists {
+		modes, _ := ip.Dict["UIBackgroundModes"].([]interface{})
+		for _, m := range modes {
+			mode, _ := m.(string)
+			need, known := backgroundModes[mode]
+			if !known {
+				continue
+			}
+			if _, used := usage["UIBackgroundModes:"+mode]; used {
+				continue
+			}
+			findings = append(findings, unusedFinding(RuleBackgroundModeUnused, "Background mode without usage", "HIGH", crossPlatform,
+				fmt.Sprintf("%s declares the %q background mode but no source file or dependency uses %s. App Review rejects unused background modes (guideline 2.5.4).", ip.File, mode, need.Name),
+				ip.File, ip.Target, "UIBackgroundModes:"+mode,
+				fmt.Sprintf("Remove %q from UIBackgroundModes, or describe the %s feature in the review notes", mode, need.Name)))
+		}
+	}
+
+	return findings
+}
+
+func unusedFinding(rule, name, severity, crossPlatform, message, file, target, key, action string) api.Finding {
+	if crossPlatform != "" {
+		severity = "LOW"
+		message += fmt.Sprintf(" %s code is not analyzed; confirm the feature is used.", crossPlatform)
+	}
+	return api.Finding{
+		RuleCode: rule,
+		RuleName: name,
+		Severity: severity,
+		Message:  message,
+		FilePath: file,
+		Evidence: map[string]interface{}{"target": target, "entitlement": key},
+		Remediation: &api.Remediation{
+			Action: action,
+		},
+		Platform: string(api.PlatformApple),
+	}
+}
+
+func associatedDomainFindings(ef entitlementsFile, domains []interface{}) []api.Finding {
+	var findings []api.Finding
+	for _, d := range domains {
+		domain, _ := d.(string)
+		valid := false
+		for _, service := range associatedDomainServices {
+			if strings.HasPrefix(domain, service) && len(domain) > len(service) {
+				valid = true
+			}
+		}
+		if valid && !strings.ContainsAny(strings.SplitN(domain, ":", 2)[1], "/ ") {
+			continue
+		}
+		findings = append(findings, api.Finding{
+			RuleCode: RuleAssociatedDomain,
+			RuleName: "Invalid associated domain",
+			Severity: "HIGH",
+			Message: fmt.Sprintf("%s lists associated domain %q. Entries must be <service>:<host> with service one of applinks, webcredentials, activitycontinuation or appclips, and no scheme or path.",
+				ef.File, domain),
+			FilePath: ef.File,
+			Evidence: map[string]interface{}{"target": ef.Target.Name, "domain": domain},
+			Remediation: &api.Remediation{
+				Action: "Use entries such as applinks:example.com; paths belong in the apple-app-site-association file",
+			},
+			Platform: string(api.PlatformApple),
+		})
+	}
+	return findings
+}
+
+// enabled reports whether an entitlement value turns the capability on.
+func enabled(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case []interface{}:
+		return len(v) > 0
+	case string:
+		return v != ""
+	}
+	return v != nil
+}
+
+// entitlementsFiles returns the entitlements files referenced by targets,
+// and unreferenced .entitlements files as targets of their own.
+func (p *Project) entitlementsFiles(targets []XcodeTarget) []entitlementsFile {
+	var files []entitlementsFile
+	referenced := make(map[string]bool)
+	present := strset.Of(p.Files...)
+
+	read := func(t XcodeTarget, rel string) entitlementsFile {
+		ef := entitlementsFile{Target: t, File: rel}
+		if !present[rel] {
+			ef.ParseErr = fmt.Errorf("referenced by CODE_SIGN_ENTITLEMENTS in %s but not found", t.Project)
+			return ef
+		}
+		data, err := p.Read(rel)
+		if err == nil {
+			ef.Dict, err = plist.DecodeDict(data)
+		}
+		if err != nil {
+			ef.ParseErr = fmt.Errorf("not a valid property list: %v", err)
+		}
+		return ef
+	}
+
+	for _, t := range targets {
+		for _, rel := range t.Entitlements {
+			referenced[rel] = true
+			files = append(files, read(t, rel))
+		}
+	}
+
+	for _, rel := range p.FilesWithExt(".entitlements") {
+		if referenced[rel] || isVendorPath(rel) || isTestPath(rel) {
+			continue
+		}
+		name := strings.TrimSuffix(path.Base(rel), ".entitlements")
+		files = append(files, read(XcodeTarget{Name: name}, rel))
+	}
+	return files
+}
+
+type targetPlist struct {
+	Target string
+	File   string
+	Dict   map[string]interface{}
+}
+
+// targetInfoPlists returns the Info.plists of app targets, or every app
+// Info.plist when the project files do not name them.
+func (p *Project) targetInfoPlists(targets []XcodeTarget) []targetPlist {
+	var out []targetPlist
+	seen := make(map[string]bool)
+	add := func(target, rel string) {
+		if rel == "" || seen[rel] {
+			return
+		}
+		seen[rel] = true
+		data, err := p.Read(rel)
+		if err != nil {
+			return
+		}
+		dict, err := plist.DecodeDict(data)
+		if err != nil {
+			return
+		}
+		if _, isExtension := dict["NSExtension"]; isExtension {
+			return
+		}
+		out = append(out, targetPlist{Target: target, File: rel, Dict: dict})
+	}
+
+	for _, t := range targets {
+		if t.IsApp() {
+			add(t.Name, t.InfoPlist)
+		}
+	}
+	if len(out) == 0 {
+		for _, rel := range p.Find(func(rel string) bool { return isInfoPlist(rel) && !isTestPath(rel) && !isVendorPath(rel) }) {
+			add(path.Base(path.Dir(rel)), rel)
+		}
+	}
+	return out
+}
+
+// capabilityUsage finds the code using each capability and background
+// mode, from Swift and Objective-C sources and dependency names. Keys are
+// entitlement keys and "UIBackgroundModes:<mode>".
+func (p *Project) capabilityUsage() map[string]usageTrigger {
+	var reqs []usageRequirement
+	packages := make(map[string][]string)
+	add := func(key string, c capability) {
+		reqs = append(reqs, usageRequirement{Feature: key, Frameworks: c.Frameworks, Symbols: c.Symbols})
+		packages[key] = c.Packages
+	}
+	for _, c := range capabilities {
+		add(c.Key, c)
+	}
+	for mode, c := range backgroundModes {
+		add("UIBackgroundModes:"+mode, c)
+	}
+
+	usage := findTriggers(p, reqs)
+
+	var iosPlugins map[string]bool
+	for _, component := range sbom.CollectFiles(p.Files, p.Read).Components {
+		switch component.Ecosystem {
+		case sbom.CocoaPods, sbom.SwiftPM, sbom.Carthage:
+		case sbom.Pub, sbom.NPM:
+			// Dart and JavaScript packages only count when they bring
+			// iOS code into the app.
+			if iosPlugins == nil {
+				iosPlugins = p.iosPlugins()
+			}
+			if !iosPlugins[component.Name] {
+				continue
+			}
+		default:
+			continue
+		}
+		for key, names := range packages {
+			if _, found := usage[key]; found {
+				continue
+			}
+			if slices.ContainsFunc(names, func(name string) bool { return strings.EqualFold(name, component.Name) }) {
+				usage[key] = usageTrigger{File: component.File, Reason: "depends on " + component.Name}
+			}
+		}
+	}
+	return usage
+}
+
+// iosPlugins returns the Flutter and React Native packages of the project
+// whose native code includes an iOS implementation.
+func (p *Project) iosPlugins() map[string]bool {
+	out := make(map[string]bool)
+	project, err := plugins.Detect(p.Root)
+	if err != nil || project == nil {
+		return out
+	}
+	for _, plugin := range project.Plugins {
+		if slices.Contains(plugin.Platforms, "ios") {
+			out[plugin.Package] = true
+		}
+	}
+	return out
+}
+
+// primaryEntitlementsFile returns the entitlements file of the first app
+// target, or of the first target with one.
+func primaryEntitlementsFile(targets []XcodeTarget, files []entitlementsFile) string {
+	for _, t := range targets {
+		if t.IsApp() && len(t.Entitlements) > 0 {
+			return t.Entitlements[0]
+		}
+	}
+	if len(files) > 0 {
+		return files[0].File
+	}
+	return ""
+}
+
+// crossPlatformFramework names the framework whose Dart or JavaScript code
+// the source checks cannot see, or returns "".
+func (p *Project) crossPlatformFramework() string {
+	for _, rel := range p.FilesNamed("pubspec.yaml", "package.json") {
+		if isVendorPath(rel) || strings.Contains(rel, "node_modules/") {
+			continue
+		}
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		switch {
+		case path.Base(rel) == "pubspec.yaml" && strings.Contains(string(data), "flutter"):
+			return "Flutter"
+		case path.Base(rel) == "package.json" && strings.Contains(string(data), `"react-native"`):
+			return "React Native"
+		}
+	}
+	return ""
+}
+
+// Entitlements returns the capabilities each iOS target declares, from its
+// entitlements files and the background modes in its Info.plist.
+func (p *Project) Entitlements() []api.TargetEntitlements {
+	targets := p.XcodeTargets()
+	byTarget := make(map[string]*api.TargetEntitlements)
+	var order []string
+
+	get := func(name, bundleID string) *api.TargetEntitlements {
+		te, ok := byTarget[name]
+		if !ok {
+			te = &api.TargetEntitlements{Target: name, BundleID: bundleID, Capabilities: []api.Capability{}}
+			byTarget[name] = te
+			order = append(order, name)
+		}
+		return te
+	}
+
+	names := make(map[string]string, len(capabilities))
+	for _, c := range capabilities {
+		names[c.Key] = c.Name
+	}
+
+	for _, ef := range p.entitlementsFiles(targets) {
+		te := get(ef.Target.Name, ef.Target.BundleID)
+		if te.File == "" {
+			te.File = ef.File
+		}
+		keys := make([]string, 0, len(ef.Dict))
+		for key := range ef.Dict {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !enabled(ef.Dict[key]) {
+				continue
+			}
+			name := names[key]
+			if name == "" {
+				name = key
+			}
+			te.Capabilities = append(te.Capabilities, api.Capability{Name: name, Key: key, Values: entitlementValues(ef.Dict[key]), Source: "entitlements"})
+		}
+	}
+
+	bundleIDs := make(map[string]string)
+	for _, t := range targets {
+		bundleIDs[t.Name] = t.BundleID
+	}
+	for _, ip := range p.targetInfoPlists(targets) {
+		modes, _ := ip.Dict["UIBackgroundModes"].([]interface{})
+		if len(modes) == 0 {
+			continue
+		}
+		te := get(ip.Target, bundleIDs[ip.Target])
+		te.Capabilities = append(te.Capabilities, api.Capability{Name: "Background Modes", Key: "UIBackgroundModes", Values: entitlementValues(modes), Source: "Info.plist"})
+	}
+
+	out := make([]api.TargetEntitlements, 0, len(order))
+	for _, name := range order {
+		out = append(out, *byTarget[name])
+	}
+	return out
+}
+
+func entitlementValues(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
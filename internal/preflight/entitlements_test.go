@@ -0,0 +1,85 @@
+package preflight
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+const emptyEntitlements = `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict/>
+</plist>
+`
+
+func TestCheckEntitlementsAndroidDependencies(t *testing.T) {
+	p := newTestProject(t, map[string]string{
+		"ios/App/App.entitlements": emptyEntitlements,
+		"android/app/gradle.lockfile": "com.stripe:stripe-android:20.37.0=releaseRuntimeClasspath\n" +
+			"com.google.firebase:firebase-messaging:23.4.0=releaseRuntimeClasspath\n" +
+			"androidx.health.connect:connect-client:1.1.0-alpha07=releaseRuntimeClasspath\n",
+	})
+
+	if findings := checkEntitlements(p); len(findings) != 0 {
+		for _, f := range findings {
+			t.Errorf("unexpected %s: %s", f.RuleCode, f.Message)
+		}
+	}
+}
+
+func TestCapabilityUsagePackages(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name: "CocoaPods",
+			files: map[string]string{
+				"ios/Podfile.lock": "PODS:\n  - FirebaseMessaging (10.20.0)\n  - StripeApplePay (23.0.0)\n\nDEPENDENCIES:\n  - FirebaseMessaging\n",
+			},
+			want: []string{"UIBackgroundModes:remote-notification", "aps-environment", "com.apple.developer.in-app-payments"},
+		},
+		{
+			name: "whole names only",
+			files: map[string]string{
+				"ios/Podfile.lock": "PODS:\n  - HealthDataKit (1.0.0)\n  - Stripe (23.0.0)\n  - SoundWave (1.0.0)\n\nDEPENDENCIES:\n  - Stripe\n",
+			},
+		},
+		{
+			name: "SwiftPM",
+			files: map[string]string{
+				"App.xcworkspace/xcshareddata/swiftpm/Package.resolved": `{"pins": [{"identity": "onesignal-xcframework", "location": "https://github.com/OneSignal/OneSignal-XCFramework", "state": {"version": "5.0.0"}}], "version": 2}`,
+			},
+			want: []string{"UIBackgroundModes:remote-notification", "aps-environment"},
+		},
+		{
+			name: "Flutter plugins with and without iOS code",
+			files: map[string]string{
+				"pubspec.yaml": "name: app\ndependencies:\n  flutter:\n    sdk: flutter\n  health: ^10.0.0\n  workmanager: ^0.5.0\n",
+				"pubspec.lock": "packages:\n" +
+					"  health:\n    dependency: \"direct main\"\n    source: hosted\n    version: \"10.0.0\"\n" +
+					"  workmanager:\n    dependency: \"direct main\"\n    source: hosted\n    version: \"0.5.2\"\n",
+				".flutter-plugins-dependencies": `{"plugins": {` +
+					`"ios": [{"name": "health", "path": "/pub/health-10.0.0/"}],` +
+					`"android": [{"name": "health", "path": "/pub/health-10.0.0/"}, {"name": "workmanager", "path": "/pub/workmanager-0.5.2/"}]}}`,
+			},
+			want: []string{"com.apple.developer.healthkit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProject(t, tt.files)
+
+			var got []string
+			for key := range p.capabilityUsage() {
+				got = append(got, key)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("capabilityUsage() keys = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,529 @@
+package preflight
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+const (
+	RuleAppleMetadataLength  = "APL-META-001"
+	RuleAppleMetadataEmpty   = "APL-META-002"
+	RuleAppleMetadataTerm    = "APL-META-003"
+	RuleApplePrivacyURL      = "APL-META-004"
+	RuleAppleMetadataURL     = "APL-META-005"
+	RuleAppleAgeRating       = "APL-META-006"
+	RuleGoogleMetadataLength = "GPL-META-001"
+	RuleGoogleMetadataEmpty  = "GPL-META-002"
+	RuleGoogleMetadataTerm   = "GPL-META-003"
+	RuleGoogleMetadataURL    = "GPL-META-004"
+)
+
+// listingField is a text file of a fastlane deliver or supply locale
+// directory. Limit is in characters; 0 means none.
+type listingField struct {
+	File     string
+	Name     string
+	Limit    int
+	Required bool
+	URL      bool
+}
+
+var appleListingFields = []listingField{
+	{File: "name.txt", Name: "App name", Limit: 30, Required: true},
+	{File: "subtitle.txt", Name: "Subtitle", Limit: 30},
+	{File: "keywords.txt", Name: "Keywords", Limit: 100, Required: true},
+	{File: "promotional_text.txt", Name: "Promotional text", Limit: 170},
+	{File: "description.txt", Name: "Description", Limit: 4000, Required: true},
+	{File: "release_notes.txt", Name: "What's New", Limit: 4000},
+	{File: "support_url.txt", Name: "Support URL", Required: true, URL: true},
+	{File: "marketing_url.txt", Name: "Marketing URL", URL: true},
+	{File: "privacy_url.txt", Name: "Privacy Policy URL", URL: true},
+}
+
+var googleListingFields = []listingField{
+	{File: "title.txt", Name: "Title", Limit: 30, Required: true},
+	{File: "short_description.txt", Name: "Short description", Limit: 80, Required: true},
+	{File: "full_description.txt", Name: "Full description", Limit: 4000, Required: true},
+	{File: "video.txt", Name: "Promo video", URL: true},
+}
+
+// googleChangelogLimit applies to changelogs/<version code>.txt.
+const googleChangelogLimit = 500
+
+// storeLocaleRe matches fastlane locale directories (en-US, zh-Hans, fil)
+// and deliver's default fallback.
+var storeLocaleRe = regexp.MustCompile(`^([a-z]{2,3}(-[A-Za-z0-9]{2,4})*|default)$`)
+
+// storeListing is one locale of a store listing. Fields holds the trimmed
+// content of the text files present, by file name.
+type storeListing struct {
+	Platform api.Platform
+	Dir      string
+	Locale   string
+	Fields   map[string]string
+}
+
+func (l storeListing) file(name string) string {
+	return path.Join(l.Dir, name)
+}
+
+// forbiddenTerm is listing text App Review or Play policy rejects. Fields
+// restricts the check to some files; nil means every text field.
+type forbiddenTerm struct {
+	Pattern  *regexp.Regexp
+	Reason   string
+	Severity string
+	Fields   []string
+}
+
+var placeholderTerm = forbiddenTerm{
+	Pattern:  regexp.MustCompile(`(?i)lorem ipsum|\bTBD\b|\bTODO:|\bFIXME\b|\bplaceholder text\b`),
+	Reason:   "placeholder text",
+	Severity: "HIGH",
+}
+
+var appleForbiddenTerms = []forbiddenTerm{
+	{
+		Pattern:  regexp.MustCompile(`(?i)\b(android|google play|play store)\b`),
+		Reason:   "references another mobile platform (guideline 2.3.10)",
+		Severity: "HIGH",
+	},
+	placeholderTerm,
+	{
+		Pattern:  regexp.MustCompile(`(?i)\b(beta|test version|demo version|pre-release)\b`),
+		Reason:   "describes a pre-release build (guideline 2.2)",
+		Severity: "MEDIUM",
+		Fields:   []string{"name.txt", "subtitle.txt", "keywords.txt", "promotional_text.txt", "description.txt"},
+	},
+	{
+		Pattern:  regexp.MustCompile(`(?i)(^|[^\w])(free|sale|discount|cheapest|best|#1|no\.\s?1|\d+% off)($|[^\w])`),
+		Reason:   "prices or rankings in the name, subtitle or keywords (guideline 2.3.7)",
+		Severity: "MEDIUM",
+		Fields:   []string{"name.txt", "subtitle.txt", "keywords.txt"},
+	},
+	{
+		Pattern:  regexp.MustCompile(`(?i)\b(iphone|ipad|apple watch|app store)\b`),
+		Reason:   "Apple trademarks in keywords (guideline 2.3.7)",
+		Severity: "MEDIUM",
+		Fields:   []string{"keywords.txt"},
+	},
+}
+
+var googleForbiddenTerms = []forbiddenTerm{
+	{
+		Pattern:  regexp.MustCompile(`(?i)(^|[^\w])(free|best|#1|no\.\s?1|number one|sale|discount|cheapest|\d+% off|download now|install now)($|[^\w])`),
+		Reason:   "price, ranking or call-to-action terms in the title (Play metadata policy)",
+		Severity: "HIGH",
+		Fields:   []string{"title.txt"},
+	},
+	{
+		Pattern:  regexp.MustCompile(`(?i)(^|[^\w])(free|best|#1|no\.\s?1|number one|sale|discount|\d+% off|download now|install now)($|[^\w])`),
+		Reason:   "price, ranking or call-to-action terms in the short description (Play metadata policy)",
+		Severity: "MEDIUM",
+		Fields:   []string{"short_description.txt"},
+	},
+	{
+		Pattern:  regexp.MustCompile(`(?i)\b(iphone|ipad|ios|app store)\b`),
+		Reason:   "references another mobile platform",
+		Severity: "MEDIUM",
+	},
+	placeholderTerm,
+}
+
+// ageRatingDescriptor is a content descriptor of the App Store age rating
+// questionnaire, with the keys fastlane's app_rating_config.json uses for
+// it (both the legacy and the current format) and listing text implying it.
+type ageRatingDescriptor struct {
+	Name  string
+	Keys  []string
+	Terms *regexp.Regexp
+}
+
+var ageRatingDescriptors = []ageRatingDescriptor{
+	{
+		Name:  "gambling",
+		Keys:  []string{"GAMBLING", "GAMBLING_CONTESTS", "gambling", "gamblingSimulated", "contests"},
+		Terms: regexp.MustCompile(`(?i)\b(casino|slots?|poker|blackjack|roulette|betting|sportsbook|jackpot|lottery)\b`),
+	},
+	{
+		Name:  "alcohol, tobacco or drug use",
+		Keys:  []string{"ALCOHOL_TOBACCO_DRUGS", "alcoholTobaccoOrDrugUseOrReferences"},
+		Terms: regexp.MustCompile(`(?i)\b(alcohol|beer|wine|cocktails?|cannabis|marijuana|tobacco|cigarettes?|vaping|drinking games?)\b`),
+	},
+	{
+		Name:  "horror or fear themes",
+		Keys:  []string{"HORROR", "horrorOrFearThemes"},
+		Terms: regexp.MustCompile(`(?i)\b(horror|zombies?|terrifying|gore)\b`),
+	},
+	{
+		Name: "violence",
+		Keys: []string{"CARTOON_FANTASY_VIOLENCE", "REALISTIC_VIOLENCE", "PROLONGED_GRAPHIC_SADISTIC_REALISTIC_VIOLENCE",
+			"violenceCartoonOrFantasy", "violenceRealistic", "violenceRealisticProlongedGraphicOrSadistic"},
+		Terms: regexp.MustCompile(`(?i)\b(shooter|guns?|weapons?|blood|gory|killing)\b`),
+	},
+	{
+		Name: "mature or suggestive themes",
+		Keys: []string{"MATURE_SUGGESTIVE", "SEXUAL_CONTENT_NUDITY", "GRAPHIC_SEXUAL_CONTENT_NUDITY",
+			"matureOrSuggestiveThemes", "sexualContentOrNudity", "sexualContentGraphicAndNudity"},
+		Terms: regexp.MustCompile(`(?i)\b(dating|hookups?|flirting|adults only|nsfw)\b`),
+	},
+	{
+		Name:  "medical or treatment information",
+		Keys:  []string{"MEDICAL_TREATMENT_INFO", "medicalOrTreatmentInformation"},
+		Terms: regexp.MustCompile(`(?i)\b(diagnosis|symptoms?|medications?|prescriptions?)\b`),
+	},
+	{
+		Name:  "unrestricted web access",
+		Keys:  []string{"UNRESTRICTED_WEB_ACCESS", "unrestrictedWebAccess"},
+		Terms: regexp.MustCompile(`(?i)\b(web browser|browse the web|browse any website|private browsing)\b`),
+	},
+}
+
+// ageRatingTextFields are the Apple listing files searched for content
+// implying an age rating descriptor.
+var ageRatingTextFields = []string{"name.txt", "subtitle.txt", "keywords.txt", "promotional_text.txt", "description.txt"}
+
+// CheckStoreMetadata validates the App Store and Google Play listings kept
+// in fastlane's metadata/ and screenshots/ directories. It is not part of
+// the default checks.
+func CheckStoreMetadata(p *Project) []api.Finding {
+	apple, google := p.storeListings()
+
+	var findings []api.Finding
+	for _, l := range apple {
+		findings = append(findings, checkListing(l, appleListingFields, appleForbiddenTerms)...)
+	}
+	for _, l := range google {
+		findings = append(findings, checkListing(l, googleListingFields, googleForbiddenTerms)...)
+		findings = append(findings, p.checkChangelogs(l)...)
+	}
+
+	if len(apple) > 0 && !hasField(apple, "privacy_url.txt") {
+		findings = append(findings, api.Finding{
+			RuleCode: RuleApplePrivacyURL,
+			RuleName: "Missing privacy policy URL",
+			Severity: "HIGH",
+			Message:  "No App Store locale has a privacy_url.txt. Every app needs a privacy policy URL before it can be submitted (guideline 5.1.1).",
+			FilePath: apple[0].file("privacy_url.txt"),
+			Remediation: &api.Remediation{
+				Action:   "Add privacy_url.txt with the privacy policy URL to the primary locale",
+				Template: "https://example.com/privacy",
+			},
+			Platform: string(api.PlatformApple),
+		})
+	}
+
+	findings = append(findings, p.checkAgeRating(apple)...)
+	findings = append(findings, p.checkScreenshots()...)
+	return findings
+}
+
+// HasStoreMetadata reports whether the project contains fastlane store
+// listings or screenshots.
+func (p *Project) HasStoreMetadata() bool {
+	apple, google := p.storeListings()
+	return len(apple) > 0 || len(google) > 0 || len(p.appleScreenshots()) > 0
+}
+
+// storeListings finds fastlane deliver (App Store) and supply (Google
+// Play) locale directories. Supply keeps its locales under android/.
+func (p *Project) storeListings() (apple, google []storeListing) {
+	isApple, isGoogle := make(map[string]bool), make(map[string]bool)
+	for _, f := range appleListingFields {
+		isApple[f.File] = true
+	}
+	for _, f := range googleListingFields {
+		isGoogle[f.File] = true
+	}
+
+	byDir := make(map[string]*storeListing)
+	var dirs []string
+	for _, rel := range p.Files {
+		if isVendorPath(rel) || strings.Contains(rel, "node_modules/") {
+			continue
+		}
+		dir, base := path.Dir(rel), path.Base(rel)
+		locale := path.Base(dir)
+		if !storeLocaleRe.MatchString(locale) {
+			continue
+		}
+
+		android := path.Base(path.Dir(dir)) == "android"
+		var platform api.Platform
+		switch {
+		case android && isGoogle[base]:
+			platform = api.PlatformGoogle
+		case !android && isApple[base]:
+			platform = api.PlatformApple
+		default:
+			continue
+		}
+
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		l, ok := byDir[dir]
+		if !ok {
+			l = &storeListing{Platform: platform, Dir: dir, Locale: locale, Fields: make(map[string]string)}
+			byDir[dir] = l
+			dirs = append(dirs, dir)
+		}
+		l.Fields[base] = strings.TrimSpace(string(data))
+	}
+
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		l := byDir[dir]
+		if l.Platform == api.PlatformGoogle {
+			google = append(google, *l)
+			continue
+		}
+		// A lone URL file is not enough to call a directory a listing.
+		if _, ok := l.Fields["name.txt"]; ok || l.Fields["description.txt"] != "" || l.Fields["keywords.txt"] != "" {
+			apple = append(apple, *l)
+		}
+	}
+	return apple, google
+}
+
+func checkListing(l storeListing, fields []listingField, terms []forbiddenTerm) []api.Finding {
+	lengthRule, emptyRule, termRule, urlRule := RuleAppleMetadataLength, RuleAppleMetadataEmpty, RuleAppleMetadataTerm, RuleAppleMetadataURL
+	if l.Platform == api.PlatformGoogle {
+		lengthRule, emptyRule, termRule, urlRule = RuleGoogleMetadataLength, RuleGoogleMetadataEmpty, RuleGoogleMetadataTerm, RuleGoogleMetadataURL
+	}
+
+	var findings []api.Finding
+	for _, field := range fields {
+		value, present := l.Fields[field.File]
+		if !present {
+			continue
+		}
+		file := l.file(field.File)
+		evidence := map[string]interface{}{"locale": l.Locale, "field": strings.TrimSuffix(field.File, ".txt")}
+
+		switch {
+		case value == "" && field.Required:
+			findings = append(findings, metadataFinding(l, emptyRule, "Empty store listing field", "HIGH", file,
+				fmt.Sprintf("%s (%s) is empty. The listing cannot be submitted without it.", field.Name, l.Locale), evidence,
+				fmt.Sprintf("Fill in %s or delete the file to keep the value in the store console", field.File)))
+		case field.Limit > 0 && utf8.RuneCountInString(value) > field.Limit:
+			n := utf8.RuneCountInString(value)
+			evidence["length"] = n
+			evidence["limit"] = field.Limit
+			findings = append(findings, metadataFinding(l, lengthRule, "Store listing field too long", "HIGH", file,
+				fmt.Sprintf("%s (%s) is %d characters; the limit is %d. The upload is rejected.", field.Name, l.Locale, n, field.Limit), evidence,
+				fmt.Sprintf("Shorten %s to %d characters", field.File, field.Limit)))
+		case field.URL && value != "" && !validStoreURL(value):
+			evidence["url"] = value
+			findings = append(findings, metadataFinding(l, urlRule, "Invalid store listing URL", "HIGH", file,
+				fmt.Sprintf("%s (%s) %q is not an absolute http(s) URL.", field.Name, l.Locale, value), evidence,
+				fmt.Sprintf("Put a single https:// URL in %s", field.File)))
+		}
+
+		if field.URL {
+			continue
+		}
+		for _, term := range terms {
+			if term.Fields != nil && !slices.Contains(term.Fields, field.File) {
+				continue
+			}
+			match := term.Pattern.FindString(value)
+			if match == "" {
+				continue
+			}
+			match = strings.TrimFunc(match, func(r rune) bool { return r == ' ' || r == ',' || r == '.' || r == '\n' })
+			termEvidence := map[string]interface{}{"locale": l.Locale, "field": evidence["field"], "term": match}
+			findings = append(findings, metadataFinding(l, termRule, "Disallowed term in store listing", term.Severity, file,
+				fmt.Sprintf("%s (%s) contains %q: %s.", field.Name, l.Locale, match, term.Reason), termEvidence,
+				fmt.Sprintf("Remove %q from %s", match, field.File)))
+		}
+	}
+
+	if l.Platform == api.PlatformGoogle {
+		if title := l.Fields["title.txt"]; hasEmoji(title) {
+			findings = append(findings, metadataFinding(l, termRule, "Disallowed term in store listing", "HIGH", l.file("title.txt"),
+				fmt.Sprintf("Title (%s) contains emoji, which Play does not allow in titles.", l.Locale),
+				map[string]interface{}{"locale": l.Locale, "field": "title"},
+				"Remove emoji and decorative symbols from title.txt"))
+		}
+	}
+	return findings
+}
+
+// checkChangelogs checks the release notes supply reads from
+// changelogs/<version code>.txt and changelogs/default.txt.
+func (p *Project) checkChangelogs(l storeListing) []api.Finding {
+	var findings []api.Finding
+	for _, rel := range p.Find(func(rel string) bool {
+		return path.Dir(rel) == path.Join(l.Dir, "changelogs") && path.Ext(rel) == ".txt"
+	}) {
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		n := utf8.RuneCountInString(strings.TrimSpace(string(data)))
+		if n <= googleChangelogLimit {
+			continue
+		}
+		findings = append(findings, metadataFinding(l, RuleGoogleMetadataLength, "Store listing field too long", "HIGH", rel,
+			fmt.Sprintf("Release notes %s (%s) are %d characters; the limit is %d. The upload is rejected.", path.Base(rel), l.Locale, n, googleChangelogLimit),
+			map[string]interface{}{"locale": l.Locale, "field": "changelog", "length": n, "limit": googleChangelogLimit},
+			fmt.Sprintf("Shorten %s to %d characters", path.Base(rel), googleChangelogLimit)))
+	}
+	return findings
+}
+
+// checkAgeRating compares app_rating_config.json with what the listing
+// text describes, and a Kids category age band with mature descriptors.
+func (p *Project) checkAgeRating(listings []storeListing) []api.Finding {
+	var findings []api.Finding
+	for _, rel := range p.FilesNamed("app_rating_config.json") {
+		if isVendorPath(rel) {
+			continue
+		}
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		var config map[string]interface{}
+		if err := json.Unmarshal(data, &config); err != nil {
+			findings = append(findings, api.Finding{
+				RuleCode: RuleAppleAgeRating,
+				RuleName: "Age rating inconsistent with listing",
+				Severity: "HIGH",
+				Message:  fmt.Sprintf("%s is not valid JSON: %v", rel, err),
+				FilePath: rel,
+				Platform: string(api.PlatformApple),
+			})
+			continue
+		}
+
+		root := path.Dir(rel)
+		var declared []string
+		for _, d := range ageRatingDescriptors {
+			if ratingDeclared(config, d.Keys) {
+				declared = append(declared, d.Name)
+				continue
+			}
+			if file, term := ratingEvidence(listings, root, d.Terms); term != "" {
+				findings = append(findings, api.Finding{
+					RuleCode: RuleAppleAgeRating,
+					RuleName: "Age rating inconsistent with listing",
+					Severity: "MEDIUM",
+					Message: fmt.Sprintf("%s mentions %q but %s declares no %s. App Review sets a higher rating or rejects the build (guideline 2.3.6).",
+						file, term, path.Base(rel), d.Name),
+					FilePath: rel,
+					Evidence: map[string]interface{}{"descriptor": d.Name, "term": term, "source_file": file},
+					Remediation: &api.Remediation{
+						Action: fmt.Sprintf("Answer the %s question of the age rating questionnaire, or reword the listing", d.Name),
+					},
+					Platform: string(api.PlatformApple),
+				})
+			}
+		}
+
+		band, _ := config["kidsAgeBand"].(string)
+		if band == "" {
+			band, _ = config["KIDS_AGE_BAND"].(string)
+		}
+		if band != "" && len(declared) > 0 {
+			findings = append(findings, api.Finding{
+				RuleCode: RuleAppleAgeRating,
+				RuleName: "Age rating inconsistent with listing",
+				Severity: "HIGH",
+				Message: fmt.Sprintf("%s places the app in the Kids category (%s) but declares %s. Kids category apps cannot include such content (guideline 1.3).",
+					rel, band, strings.Join(declared, ", ")),
+				FilePath: rel,
+				Evidence: map[string]interface{}{"kids_age_band": band, "descriptors": declared},
+				Platform: string(api.PlatformApple),
+			})
+		}
+	}
+	return findings
+}
+
+// ratingDeclared reports whether any of keys is answered with something
+// other than none: a non-zero level, a string other than NONE, or true.
+func ratingDeclared(config map[string]interface{}, keys []string) bool {
+	for _, key := range keys {
+		switch v := config[key].(type) {
+		case float64:
+			if v != 0 {
+				return true
+			}
+		case string:
+			if v != "" && !strings.EqualFold(v, "NONE") {
+				return true
+			}
+		case bool:
+			if v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ratingEvidence returns the first listing file under root whose text
+// matches terms, and the matched term.
+func ratingEvidence(listings []storeListing, root string, terms *regexp.Regexp) (string, string) {
+	for _, l := range listings {
+		if path.Dir(l.Dir) != root {
+			continue
+		}
+		for _, field := range ageRatingTextFields {
+			if m := terms.FindString(l.Fields[field]); m != "" {
+				return l.file(field), strings.ToLower(m)
+			}
+		}
+	}
+	return "", ""
+}
+
+func metadataFinding(l storeListing, rule, name, severity, file, message string, evidence map[string]interface{}, action string) api.Finding {
+	return api.Finding{
+		RuleCode: rule,
+		RuleName: name,
+		Severity: severity,
+		Message:  message,
+		FilePath: file,
+		Evidence: evidence,
+		Remediation: &api.Remediation{
+			Action: action,
+		},
+		Platform: string(l.Platform),
+	}
+}
+
+func hasField(listings []storeListing, file string) bool {
+	for _, l := range listings {
+		if l.Fields[file] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func validStoreURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && !strings.ContainsAny(s, " \n")
+}
+
+func hasEmoji(s string) bool {
+	for _, r := range s {
+		if r >= 0x1F000 || r >= 0x2600 && r <= 0x27BF {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+package preflight
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+// appleListing returns a valid deliver listing for locale under
+// fastlane/metadata, with files overriding the defaults.
+func appleListing(locale string, files map[string]string) map[string]string {
+	dir := "fastlane/metadata/" + locale + "/"
+	listing := map[string]string{
+		dir + "name.txt":        "Canopy Notes",
+		dir + "keywords.txt":    "notes,journal,writing",
+		dir + "description.txt": "Write and organise your notes.",
+		dir + "support_url.txt": "https://example.com/support",
+		dir + "privacy_url.txt": "https://example.com/privacy",
+	}
+	for name, content := range files {
+		listing[dir+name] = content
+	}
+	return listing
+}
+
+// googleListing returns a valid supply listing for locale under
+// fastlane/metadata/android, with files overriding the defaults.
+func googleListing(locale string, files map[string]string) map[string]string {
+	dir := "fastlane/metadata/android/" + locale + "/"
+	listing := map[string]string{
+		dir + "title.txt":             "Canopy Notes",
+		dir + "short_description.txt": "Write and organise your notes.",
+		dir + "full_description.txt":  "Write and organise your notes.",
+	}
+	for name, content := range files {
+		listing[dir+name] = content
+	}
+	return listing
+}
+
+func findingKeys(findings []api.Finding) []string {
+	var keys []string
+	for _, f := range findings {
+		keys = append(keys, f.RuleCode+" "+f.FilePath)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestCheckStoreMetadata(t *testing.T) {
+	const apple = "fastlane/metadata/en-US/"
+	const google = "fastlane/metadata/android/en-US/"
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name:  "valid listings",
+			files: merge(appleListing("en-US", nil), googleListing("en-US", nil)),
+		},
+		{
+			name:  "name over the limit",
+			files: appleListing("en-US", map[string]string{"name.txt": strings.Repeat("a", 31)}),
+			want:  []string{RuleAppleMetadataLength + " " + apple + "name.txt"},
+		},
+		{
+			name:  "limit counts characters, not bytes",
+			files: appleListing("ja", map[string]string{"name.txt": strings.Repeat("語", 30)}),
+		},
+		{
+			name:  "empty required field",
+			files: appleListing("en-US", map[string]string{"keywords.txt": "\n"}),
+			want:  []string{RuleAppleMetadataEmpty + " " + apple + "keywords.txt"},
+		},
+		{
+			name:  "empty optional field",
+			files: appleListing("en-US", map[string]string{"subtitle.txt": ""}),
+		},
+		{
+			name:  "invalid URL",
+			files: appleListing("en-US", map[string]string{"support_url.txt": "example.com/support"}),
+			want:  []string{RuleAppleMetadataURL + " " + apple + "support_url.txt"},
+		},
+		{
+			name:  "other platform in an App Store listing",
+			files: appleListing("en-US", map[string]string{"description.txt": "Also available on Android."}),
+			want:  []string{RuleAppleMetadataTerm + " " + apple + "description.txt"},
+		},
+		{
+			name:  "ranking in the subtitle but not the description",
+			files: appleListing("en-US", map[string]string{"subtitle.txt": "The #1 notes app", "description.txt": "Our best release yet."}),
+			want:  []string{RuleAppleMetadataTerm + " " + apple + "subtitle.txt"},
+		},
+		{
+			name: "missing privacy policy URL",
+			files: map[string]string{
+				apple + "name.txt":        "Canopy Notes",
+				apple + "description.txt": "Write and organise your notes.",
+			},
+			want: []string{RuleApplePrivacyURL + " " + apple + "privacy_url.txt"},
+		},
+		{
+			name: "lone URL file is not a listing",
+			files: map[string]string{
+				apple + "support_url.txt": "https://example.com/support",
+			},
+		},
+		{
+			name:  "emoji in a Play title",
+			files: googleListing("en-US", map[string]string{"title.txt": "Canopy Notes 📝"}),
+			want:  []string{RuleGoogleMetadataTerm + " " + google + "title.txt"},
+		},
+		{
+			name:  "call to action in a Play title",
+			files: googleListing("en-US", map[string]string{"title.txt": "Canopy Notes - Download now"}),
+			want:  []string{RuleGoogleMetadataTerm + " " + google + "title.txt"},
+		},
+		{
+			name:  "short description over the limit",
+			files: googleListing("de-DE", map[string]string{"short_description.txt": strings.Repeat("a", 81)}),
+			want:  []string{RuleGoogleMetadataLength + " fastlane/metadata/android/de-DE/short_description.txt"},
+		},
+		{
+			name: "changelog over the limit",
+			files: merge(googleListing("en-US", nil), map[string]string{
+				google + "changelogs/42.txt":      strings.Repeat("a", 501),
+				google + "changelogs/default.txt": strings.Repeat("a", 500),
+			}),
+			want: []string{RuleGoogleMetadataLength + " " + google + "changelogs/42.txt"},
+		},
+		{
+			name:  "Play text files outside android/ are ignored",
+			files: map[string]string{apple + "title.txt": strings.Repeat("a", 40)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProject(t, tt.files)
+			got := findingKeys(CheckStoreMetadata(p))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAgeRating(t *testing.T) {
+	const config = "fastlane/metadata/app_rating_config.json"
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    []string
+		message string
+	}{
+		{
+			name: "listing term without a declared descriptor",
+			files: merge(appleListing("en-US", map[string]string{"description.txt": "Spin the slots and win the jackpot."}), map[string]string{
+				config: `{"gamblingSimulated": "NONE", "violenceRealistic": "NONE"}`,
+			}),
+			want:    []string{RuleAppleAgeRating + " " + config},
+			message: `mentions "slots"`,
+		},
+		{
+			name: "descriptor declared in the current format",
+			files: merge(appleListing("en-US", map[string]string{"description.txt": "Spin the slots and win the jackpot."}), map[string]string{
+				config: `{"gamblingSimulated": "FREQUENT_OR_INTENSE"}`,
+			}),
+		},
+		{
+			name: "descriptor declared in the legacy format",
+			files: merge(appleListing("en-US", map[string]string{"description.txt": "Spin the slots and win the jackpot."}), map[string]string{
+				config: `{"GAMBLING": 2}`,
+			}),
+		},
+		{
+			name: "Kids category with a declared descriptor",
+			files: merge(appleListing("en-US", nil), map[string]string{
+				config: `{"kidsAgeBand": "FIVE_AND_UNDER", "horrorOrFearThemes": "INFREQUENT_OR_MILD"}`,
+			}),
+			want:    []string{RuleAppleAgeRating + " " + config},
+			message: "Kids category (FIVE_AND_UNDER)",
+		},
+		{
+			name: "Kids category without descriptors",
+			files: merge(appleListing("en-US", nil), map[string]string{
+				config: `{"kidsAgeBand": "FIVE_AND_UNDER", "horrorOrFearThemes": "NONE", "unrestrictedWebAccess": false}`,
+			}),
+		},
+		{
+			name: "invalid JSON",
+			files: merge(appleListing("en-US", nil), map[string]string{
+				config: `{"gamblingSimulated": `,
+			}),
+			want:    []string{RuleAppleAgeRating + " " + config},
+			message: "not valid JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProject(t, tt.files)
+			apple, _ := p.storeListings()
+			findings := p.checkAgeRating(apple)
+			if got := findingKeys(findings); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("findings = %v, want %v", got, tt.want)
+			}
+			if tt.message != "" && !strings.Contains(findings[0].Message, tt.message) {
+				t.Errorf("message = %q, want it to contain %q", findings[0].Message, tt.message)
+			}
+		})
+	}
+}
+
+func merge(maps ...map[string]string) map[string]string {
+	out := make(map[string]string)
+	for _, m := range maps {
+		for k, v := range m {
+			out[k] = v
+		}
+	}
+	return out
+}
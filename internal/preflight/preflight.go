@@ -0,0 +1,130 @@
+// Package preflight runs offline checks on a project directory. Findings
+// use the same shape as server findings with Source set to "local".
+package preflight
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/archive"
+	"github.com/hha-nguyen/canopy-cli/internal/fingerprint"
+)
+
+const Source = "local"
+
+type Project struct {
+	Root  string
+	Files []string
+
+	sdks []SDK
+}
+
+type Check func(p *Project) []api.Finding
+
+var checks = []Check{
+	checkUsageDescriptions,
+	CheckPrivacyManifest,
+	checkAndroid,
+	checkEntitlements,
+}
+
+func LoadProject(root string) (*Project, error) {
+	files, err := archive.ListFiles(root, archive.DefaultCompressOptions())
+	if err != nil {
+		return nil, err
+	}
+	return &Project{Root: root, Files: files}, nil
+}
+
+// Run loads the project at root and runs every check against it.
+func Run(root string) ([]api.Finding, error) {
+	p, err := LoadProject(root)
+	if err != nil {
+		return nil, err
+	}
+	return p.Run(), nil
+}
+
+// Run runs the default checks and any extra ones.
+func (p *Project) Run(extra ...Check) []api.Finding {
+	return p.RunChecks(append(checks[:len(checks):len(checks)], extra...)...)
+}
+
+func (p *Project) RunChecks(checks ...Check) []api.Finding {
+	findings := []api.Finding{}
+	for _, check := range checks {
+		findings = append(findings, check(p)...)
+	}
+
+	for i := range findings {
+		f := &findings[i]
+		f.Source = Source
+		if f.ID == "" {
+			f.ID = "local-" + fingerprint.Compute(*f)[:12]
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		ri, rj := api.SeverityRank(findings[i].Severity), api.SeverityRank(findings[j].Severity)
+		if ri != rj {
+			return ri > rj
+		}
+		return findings[i].FilePath < findings[j].FilePath
+	})
+
+	return findings
+}
+
+func (p *Project) Read(rel string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(p.Root, filepath.FromSlash(rel)))
+}
+
+// FilesNamed returns files whose base name is one of names.
+func (p *Project) FilesNamed(names ...string) []string {
+	return p.Find(func(rel string) bool {
+		base := path.Base(rel)
+		for _, name := range names {
+			if base == name {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FilesWithExt returns files with one of the given extensions.
+func (p *Project) FilesWithExt(exts ...string) []string {
+	return p.Find(func(rel string) bool {
+		ext := path.Ext(rel)
+		for _, e := range exts {
+			if ext == e {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (p *Project) Find(match func(rel string) bool) []string {
+	var out []string
+	for _, rel := range p.Files {
+		if match(rel) {
+			out = append(out, rel)
+		}
+	}
+	return out
+}
+
+// isTestPath reports whether a file belongs to a test target.
+func isTestPath(rel string) bool {
+	for _, part := range strings.Split(rel, "/") {
+		if strings.HasSuffix(part, "Tests") || strings.HasSuffix(part, "Test") {
+			return true
+		}
+	}
+	return false
+}
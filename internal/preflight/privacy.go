@@ -0,0 +1,666 @@
+package preflight
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/plist"
+	"github.com/hha-nguyen/canopy-cli/internal/strset"
+)
+
+const (
+	RuleUndeclaredReasonAPI = "APL-PRIVACY-001"
+	RuleInvalidManifest     = "APL-PRIVACY-002"
+	RuleInvalidReason       = "APL-PRIVACY-003"
+	RuleTrackingDomains     = "APL-PRIVACY-004"
+	RuleSDKManifest         = "APL-PRIVACY-005"
+)
+
+const PrivacyManifestName = "PrivacyInfo.xcprivacy"
+
+const (
+	manifestDocsURL = "https://developer.apple.com/documentation/bundleresources/privacy_manifest_files"
+	reasonDocsURL   = "https://developer.apple.com/documentation/bundleresources/privacy_manifest_files/describing_use_of_required_reason_api"
+	sdkDocsURL      = "https://developer.apple.com/support/third-party-SDK-requirements/"
+)
+
+// reasonAPI is a category of required-reason APIs. DefaultReason is the
+// code used in generated drafts and must be reviewed by the developer.
+type reasonAPI struct {
+	Category      string
+	Name          string
+	Reasons       []string
+	DefaultReason string
+	Pattern       *regexp.Regexp
+}
+
+var reasonAPIs = []reasonAPI{
+	{
+		Category:      "NSPrivacyAccessedAPICategoryFileTimestamp",
+		Name:          "file timestamp",
+		Reasons:       []string{"DDA9.1", "C617.1", "3B52.1", "0A2A.1"},
+		DefaultReason: "C617.1",
+		Pattern: regexp.MustCompile(`\b(?:NSFileCreationDate|NSFileModificationDate|NSURLContentModificationDateKey|NSURLCreationDateKey|` +
+			`contentModificationDate(?:Key)?|creationDateKey|fileModificationDate|attributesOfItem|getattrlist(?:bulk|at)?|fgetattrlist)\b|` +
+			`\b(?:stat|fstat|fstatat|lstat)\s*\(`),
+	},
+	{
+		Category:      "NSPrivacyAccessedAPICategorySystemBootTime",
+		Name:          "system boot time",
+		Reasons:       []string{"35F9.1", "8FFB.1", "3D61.1"},
+		DefaultReason: "35F9.1",
+		Pattern:       regexp.MustCompile(`\b(?:systemUptime|mach_absolute_time)\b`),
+	},
+	{
+		Category:      "NSPrivacyAccessedAPICategoryDiskSpace",
+		Name:          "disk space",
+		Reasons:       []string{"85F4.1", "E174.1", "7D9E.1", "B728.1"},
+		DefaultReason: "E174.1",
+		Pattern: regexp.MustCompile(`\b(?:volumeAvailableCapacity\w*|volumeTotalCapacity\w*|NSURLVolume(?:Available|Total)Capacity\w*|` +
+			`systemFreeSize|systemSize|NSFileSystemFreeSize|NSFileSystemSize)\b|\b(?:statfs|statvfs|fstatfs|fstatvfs)\s*\(`),
+	},
+	{
+		Category:      "NSPrivacyAccessedAPICategoryActiveKeyboards",
+		Name:          "active keyboards",
+		Reasons:       []string{"3EC4.1", "54BD.1"},
+		DefaultReason: "54BD.1",
+		Pattern:       regexp.MustCompile(`\bactiveInputModes\b`),
+	},
+	{
+		Category:      "NSPrivacyAccessedAPICategoryUserDefaults",
+		Name:          "user defaults",
+		Reasons:       []string{"CA92.1", "1C8F.1", "C56D.1", "AC6B.1"},
+		DefaultReason: "CA92.1",
+		Pattern:       regexp.MustCompile(`\b(?:NSUserDefaults|UserDefaults)\b|@AppStorage\b`),
+	},
+}
+
+var collectedDataTypes = strset.Of(
+	"Name", "EmailAddress", "PhoneNumber", "PhysicalAddress", "OtherUserContactInfo",
+	"Health", "Fitness", "PaymentInfo", "CreditInfo", "OtherFinancialInfo",
+	"PreciseLocation", "CoarseLocation", "SensitiveInfo", "Contacts",
+	"EmailsOrTextMessages", "PhotosorVideos", "AudioData", "GameplayContent",
+	"CustomerSupport", "OtherUserContent", "BrowsingHistory", "SearchHistory",
+	"UserID", "DeviceID", "PurchaseHistory", "ProductInteraction", "AdvertisingData",
+	"OtherUsageData", "CrashData", "PerformanceData", "OtherDiagnosticData",
+	"EnvironmentScanning", "Hands", "Head", "OtherDataTypes",
+)
+
+var collectionPurposes = strset.Of(
+	"ThirdPartyAdvertising", "DeveloperAdvertising", "Analytics",
+	"ProductPersonalization", "AppFunctionality", "Other",
+)
+
+// listedSDKs are Apple's commonly used SDKs that must ship a privacy
+// manifest and signature.
+var listedSDKs = strset.Of(
+	"Abseil", "AFNetworking", "Alamofire", "AppAuth", "BoringSSL", "openssl_grpc",
+	"Capacitor", "Charts", "connectivity_plus", "Cordova", "device_info_plus",
+	"DKImagePickerController", "DKPhotoGallery", "FBAEMKit", "FBLPromises",
+	"FBSDKCoreKit", "FBSDKCoreKit_Basics", "FBSDKLoginKit", "FBSDKShareKit",
+	"file_picker", "FirebaseABTesting", "FirebaseAuth", "FirebaseCore",
+	"FirebaseCoreDiagnostics", "FirebaseCoreExtension", "FirebaseCoreInternal",
+	"FirebaseCrashlytics", "FirebaseDynamicLinks", "FirebaseFirestore",
+	"FirebaseInstallations", "FirebaseMessaging", "FirebaseRemoteConfig",
+	"Flutter", "flutter_inappwebview", "flutter_local_notifications", "fluttertoast",
+	"FMDB", "geolocator_apple", "GoogleDataTransport", "GoogleSignIn",
+	"GoogleToolboxForMac", "GoogleUtilities", "grpcpp", "GTMAppAuth",
+	"GTMSessionFetcher", "hermes", "image_picker_ios", "IQKeyboardManager",
+	"IQKeyboardManagerSwift", "Kingfisher", "leveldb", "Lottie", "MBProgressHUD",
+	"nanopb", "OneSignal", "OneSignalCore", "OneSignalExtension", "OneSignalOutcomes",
+	"OpenSSL", "OrderedSet", "package_info", "package_info_plus", "path_provider",
+	"path_provider_ios", "Promises", "Protobuf", "Reachability", "RealmSwift",
+	"RxCocoa", "RxRelay", "RxSwift", "SDWebImage", "share_plus",
+	"shared_preferences_ios", "SnapKit", "sqflite", "Starscream", "SVProgressHUD",
+	"SwiftyGif", "SwiftyJSON", "Toast", "UnityFramework", "url_launcher",
+	"url_launcher_ios", "video_player_avfoundation", "wakelock",
+	"webview_flutter_wkwebview",
+)
+
+// sdkDirs are dependency directories whose children are individual SDKs.
+var sdkDirs = []string{"Pods", "Carthage/Checkouts", "Carthage/Build", ".build/checkouts", "SourcePackages/checkouts"}
+
+var podsSupportDirs = strset.Of("Target Support Files", "Headers", "Local Podspecs", "Pods.xcodeproj")
+
+// SDK is a bundled third-party dependency and the privacy manifests it ships.
+type SDK struct {
+	Name      string
+	Dir       string
+	Manifests []string
+}
+
+// ReasonUsage records the first source file using a required-reason API.
+type ReasonUsage struct {
+	Category string
+	File     string
+	Symbol   string
+}
+
+// CheckPrivacyManifest validates the app's and bundled SDKs' privacy
+// manifests against the required-reason APIs used in the sources.
+func CheckPrivacyManifest(p *Project) []api.Finding {
+	manifests := p.PrivacyManifests()
+	if len(manifests) == 0 && !p.isApple() {
+		return nil
+	}
+
+	var findings []api.Finding
+	declared := make(map[string]bool)
+
+	for _, rel := range manifests {
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		dict, problems := validateManifest(rel, data)
+		findings = append(findings, problems...)
+		for category := range declaredCategories(dict) {
+			declared[category] = true
+		}
+	}
+
+	for _, use := range p.ReasonUsages() {
+		if declared[use.Category] {
+			continue
+		}
+		findings = append(findings, undeclaredReasonFinding(use, manifests))
+	}
+
+	for _, sdk := range p.SDKs() {
+		for _, rel := range sdk.Manifests {
+			data, err := p.Read(rel)
+			if err != nil {
+				continue
+			}
+			_, problems := validateManifest(rel, data)
+			findings = append(findings, problems...)
+		}
+		if len(sdk.Manifests) == 0 && listedSDKs[sdk.Name] {
+			findings = append(findings, api.Finding{
+				RuleCode: RuleSDKManifest,
+				RuleName: "SDK without privacy manifest",
+				Severity: "HIGH",
+				Message: fmt.Sprintf("%s is on Apple's list of commonly used SDKs but ships no %s. App Store Connect rejects builds that include it.",
+					sdk.Name, PrivacyManifestName),
+				FilePath: sdk.Dir,
+				Evidence: map[string]interface{}{"sdk": sdk.Name},
+				Remediation: &api.Remediation{
+					Action: fmt.Sprintf("Update %s to a release that includes a privacy manifest", sdk.Name),
+				},
+				DocsURL:  sdkDocsURL,
+				Platform: string(api.PlatformApple),
+			})
+		}
+	}
+
+	return findings
+}
+
+// PrivacyManifests returns the app's own privacy manifests.
+func (p *Project) PrivacyManifests() []string {
+	return p.Find(func(rel string) bool {
+		return path.Base(rel) == PrivacyManifestName && !isTestPath(rel) && !isVendorPath(rel)
+	})
+}
+
+// ReasonUsages scans the app's Swift and Objective-C sources for
+// required-reason APIs, one usage per category.
+func (p *Project) ReasonUsages() []ReasonUsage {
+	found := make(map[string]ReasonUsage)
+
+	for _, rel := range p.FilesWithExt(".swift", ".m", ".mm", ".c", ".cpp", ".h") {
+		if isTestPath(rel) || isVendorPath(rel) {
+			continue
+		}
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		source := stripLineComments(string(data))
+
+		for _, r := range reasonAPIs {
+			if _, seen := found[r.Category]; seen {
+				continue
+			}
+			if m := r.Pattern.FindString(source); m != "" {
+				symbol := strings.TrimSpace(strings.TrimSuffix(m, "("))
+				found[r.Category] = ReasonUsage{Category: r.Category, File: rel, Symbol: symbol}
+			}
+		}
+	}
+
+	var usages []ReasonUsage
+	for _, r := range reasonAPIs {
+		if use, ok := found[r.Category]; ok {
+			usages = append(usages, use)
+		}
+	}
+	return usages
+}
+
+// SDKs lists dependencies in CocoaPods, Carthage and Swift Package Manager
+// checkouts. These directories are skipped when building the project file
+// list, so they are walked separately.
+func (p *Project) SDKs() []SDK {
+	if p.sdks != nil {
+		return p.sdks
+	}
+
+	byName := make(map[string]*SDK)
+	var order []string
+
+	_ = filepath.WalkDir(p.Root, func(full string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(p.Root, full)
+		rel = filepath.ToSlash(rel)
+		if d.Name() == ".git" || d.Name() == "node_modules" || d.Name() == "DerivedData" {
+			return filepath.SkipDir
+		}
+
+		if !isSDKDir(rel) {
+			return nil
+		}
+
+		entries, err := readDirs(full)
+		if err != nil {
+			return filepath.SkipDir
+		}
+		for _, name := range entries {
+			if d.Name() == "Pods" && podsSupportDirs[name] {
+				continue
+			}
+			sdkName := strings.TrimSuffix(strings.TrimSuffix(name, ".xcframework"), ".framework")
+			sdk, ok := byName[sdkName]
+			if !ok {
+				sdk = &SDK{Name: sdkName, Dir: path.Join(rel, name)}
+				byName[sdkName] = sdk
+				order = append(order, sdkName)
+			}
+			sdk.Manifests = append(sdk.Manifests, p.findManifests(path.Join(rel, name))...)
+		}
+		return filepath.SkipDir
+	})
+
+	p.sdks = []SDK{}
+	for _, name := range order {
+		p.sdks = append(p.sdks, *byName[name])
+	}
+	return p.sdks
+}
+
+func (p *Project) findManifests(dir string) []string {
+	var out []string
+	_ = filepath.WalkDir(filepath.Join(p.Root, filepath.FromSlash(dir)), func(full string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() && d.Name() == PrivacyManifestName {
+			rel, _ := filepath.Rel(p.Root, full)
+			out = append(out, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return out
+}
+
+// isApple reports whether the project builds for Apple platforms.
+func (p *Project) isApple() bool {
+	return len(p.Find(func(rel string) bool {
+		base := path.Base(rel)
+		return base == "project.pbxproj" || base == "Package.swift" || base == "Podfile" ||
+			path.Ext(base) == ".podspec" || isInfoPlist(rel)
+	})) > 0
+}
+
+// validateManifest checks the structure and reason codes of a privacy
+// manifest and returns its root dictionary when it could be parsed.
+func validateManifest(rel string, data []byte) (map[string]interface{}, []api.Finding) {
+	dict, err := plist.DecodeDict(data)
+	if err != nil {
+		return nil, []api.Finding{invalidManifest(rel, fmt.Sprintf("%s is not a valid property list: %v", rel, err), nil)}
+	}
+
+	var findings []api.Finding
+	invalid := func(format string, args ...interface{}) {
+		findings = append(findings, invalidManifest(rel, fmt.Sprintf(format, args...), nil))
+	}
+
+	keys := make([]string, 0, len(dict))
+	for k := range dict {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		switch k {
+		case "NSPrivacyTracking", "NSPrivacyTrackingDomains", "NSPrivacyCollectedDataTypes", "NSPrivacyAccessedAPITypes":
+		default:
+			invalid("%s has unknown key %s, which App Store Connect ignores", rel, k)
+		}
+	}
+
+	tracking, ok := dict["NSPrivacyTracking"]
+	if _, isBool := tracking.(bool); ok && !isBool {
+		invalid("NSPrivacyTracking in %s must be a boolean", rel)
+	}
+
+	domains, ok := dict["NSPrivacyTrackingDomains"]
+	domainList, isArray := domains.([]interface{})
+	if ok && (!isArray || !allStrings(domainList)) {
+		invalid("NSPrivacyTrackingDomains in %s must be an array of strings", rel)
+	}
+	if enabled, _ := tracking.(bool); enabled && len(domainList) == 0 {
+		findings = append(findings, api.Finding{
+			RuleCode: RuleTrackingDomains,
+			RuleName: "Tracking without tracking domains",
+			Severity: "MEDIUM",
+			Message:  fmt.Sprintf("%s sets NSPrivacyTracking but lists no NSPrivacyTrackingDomains, so tracking traffic is not blocked when the user declines ATT.", rel),
+			FilePath: rel,
+			Remediation: &api.Remediation{
+				Action: "List the domains used for tracking in NSPrivacyTrackingDomains, or set NSPrivacyTracking to false",
+			},
+			DocsURL:  manifestDocsURL,
+			Platform: string(api.PlatformApple),
+		})
+	}
+
+	if v, ok := dict["NSPrivacyCollectedDataTypes"]; ok {
+		items, isArray := v.([]interface{})
+		if !isArray {
+			invalid("NSPrivacyCollectedDataTypes in %s must be an array", rel)
+		}
+		for i, item := range items {
+			entry, isDict := item.(map[string]interface{})
+			if !isDict {
+				invalid("NSPrivacyCollectedDataTypes[%d] in %s must be a dictionary", i, rel)
+				continue
+			}
+			for _, msg := range validateCollectedType(entry) {
+				invalid("NSPrivacyCollectedDataTypes[%d] in %s %s", i, rel, msg)
+			}
+		}
+	}
+
+	if v, ok := dict["NSPrivacyAccessedAPITypes"]; ok {
+		items, isArray := v.([]interface{})
+		if !isArray {
+			invalid("NSPrivacyAccessedAPITypes in %s must be an array", rel)
+		}
+		for i, item := range items {
+			entry, isDict := item.(map[string]interface{})
+			if !isDict {
+				invalid("NSPrivacyAccessedAPITypes[%d] in %s must be a dictionary", i, rel)
+				continue
+			}
+			findings = append(findings, validateAccessedAPI(rel, i, entry)...)
+		}
+	}
+
+	return dict, findings
+}
+
+func validateCollectedType(entry map[string]interface{}) []string {
+	var problems []string
+
+	dataType, _ := entry["NSPrivacyCollectedDataType"].(string)
+	if dataType == "" {
+		problems = append(problems, "is missing NSPrivacyCollectedDataType")
+	} else if !collectedDataTypes[strings.TrimPrefix(dataType, "NSPrivacyCollectedDataType")] {
+		problems = append(problems, fmt.Sprintf("has unknown data type %s", dataType))
+	}
+
+	for _, key := range []string{"NSPrivacyCollectedDataTypeLinked", "NSPrivacyCollectedDataTypeTracking"} {
+		if _, ok := entry[key].(bool); !ok {
+			problems = append(problems, fmt.Sprintf("needs boolean %s", key))
+		}
+	}
+
+	purposes, _ := entry["NSPrivacyCollectedDataTypePurposes"].([]interface{})
+	if len(purposes) == 0 {
+		problems = append(problems, "needs at least one NSPrivacyCollectedDataTypePurposes entry")
+	}
+	for _, p := range purposes {
+		s, _ := p.(string)
+		if !collectionPurposes[strings.TrimPrefix(s, "NSPrivacyCollectedDataTypePurpose")] {
+			problems = append(problems, fmt.Sprintf("has unknown purpose %v", p))
+		}
+	}
+
+	return problems
+}
+
+func validateAccessedAPI(rel string, i int, entry map[string]interface{}) []api.Finding {
+	category, _ := entry["NSPrivacyAccessedAPIType"].(string)
+	r, known := lookupReasonAPI(category)
+	if !known {
+		msg := fmt.Sprintf("NSPrivacyAccessedAPITypes[%d] in %s has unknown NSPrivacyAccessedAPIType %q", i, rel, category)
+		return []api.Finding{invalidManifest(rel, msg, map[string]interface{}{"category": category})}
+	}
+
+	reasons, _ := entry["NSPrivacyAccessedAPITypeReasons"].([]interface{})
+	if len(reasons) == 0 {
+		return []api.Finding{invalidReason(rel, r, "", fmt.Sprintf("%s in %s declares no NSPrivacyAccessedAPITypeReasons", category, rel))}
+	}
+
+	var findings []api.Finding
+	for _, v := range reasons {
+		code, _ := v.(string)
+		if !slices.Contains(r.Reasons, code) {
+			findings = append(findings, invalidReason(rel, r, code,
+				fmt.Sprintf("%s in %s uses reason %q, which is not valid for %s APIs (valid: %s)",
+					category, rel, code, r.Name, strings.Join(r.Reasons, ", "))))
+		}
+	}
+	return findings
+}
+
+func invalidManifest(rel, msg string, evidence map[string]interface{}) api.Finding {
+	return api.Finding{
+		RuleCode: RuleInvalidManifest,
+		RuleName: "Invalid privacy manifest",
+		Severity: "HIGH",
+		Message:  msg,
+		FilePath: rel,
+		Evidence: evidence,
+		Remediation: &api.Remediation{
+			Action: "Fix the privacy manifest so it matches Apple's schema, or regenerate it with canopy privacy-manifest --write",
+		},
+		DocsURL:  manifestDocsURL,
+		Platform: string(api.PlatformApple),
+	}
+}
+
+func invalidReason(rel string, r reasonAPI, code, msg string) api.Finding {
+	return api.Finding{
+		RuleCode: RuleInvalidReason,
+		RuleName: "Invalid required-reason code",
+		Severity: "HIGH",
+		Message:  msg,
+		FilePath: rel,
+		Evidence: map[string]interface{}{"category": r.Category, "reason": code},
+		Remediation: &api.Remediation{
+			Action: fmt.Sprintf("Use one of %s that describes why the app uses %s APIs", strings.Join(r.Reasons, ", "), r.Name),
+		},
+		DocsURL:  reasonDocsURL,
+		Platform: string(api.PlatformApple),
+	}
+}
+
+func undeclaredReasonFinding(use ReasonUsage, manifests []string) api.Finding {
+	r, _ := lookupReasonAPI(use.Category)
+
+	where := "the app has no " + PrivacyManifestName
+	filePath := use.File
+	if len(manifests) > 0 {
+		where = manifests[0] + " does not declare " + use.Category
+		filePath = manifests[0]
+	}
+
+	return api.Finding{
+		RuleCode: RuleUndeclaredReasonAPI,
+		RuleName: "Undeclared required-reason API",
+		Severity: "BLOCKER",
+		Message: fmt.Sprintf("%s uses %s APIs (%s) but %s. App Store Connect rejects the upload.",
+			use.File, r.Name, use.Symbol, where),
+		FilePath: filePath,
+		Evidence: map[string]interface{}{
+			"category":    use.Category,
+			"symbol":      use.Symbol,
+			"source_file": use.File,
+		},
+		Remediation: &api.Remediation{
+			Action: fmt.Sprintf("Declare %s in NSPrivacyAccessedAPITypes with the reason that applies (%s)", use.Category, strings.Join(r.Reasons, ", ")),
+			Template: fmt.Sprintf("<dict>\n\t<key>NSPrivacyAccessedAPIType</key>\n\t<string>%s</string>\n\t<key>NSPrivacyAccessedAPITypeReasons</key>\n\t<array>\n\t\t<string>%s</string>\n\t</array>\n</dict>",
+				use.Category, r.DefaultReason),
+		},
+		DocsURL:  reasonDocsURL,
+		Platform: string(api.PlatformApple),
+	}
+}
+
+// declaredCategories returns the API categories a manifest declares with
+// at least one reason.
+func declaredCategories(dict map[string]interface{}) map[string]bool {
+	out := make(map[string]bool)
+	items, _ := dict["NSPrivacyAccessedAPITypes"].([]interface{})
+	for _, item := range items {
+		entry, _ := item.(map[string]interface{})
+		category, _ := entry["NSPrivacyAccessedAPIType"].(string)
+		reasons, _ := entry["NSPrivacyAccessedAPITypeReasons"].([]interface{})
+		if category != "" && len(reasons) > 0 {
+			out[category] = true
+		}
+	}
+	return out
+}
+
+// ManifestDraft builds a privacy manifest for the project. An existing
+// manifest is kept as is and only gains entries for undeclared API
+// categories, each with a default reason the developer must review.
+func (p *Project) ManifestDraft() (target string, dict map[string]interface{}, added []string, err error) {
+	manifests := p.PrivacyManifests()
+	dict = map[string]interface{}{}
+
+	if len(manifests) > 0 {
+		target = manifests[0]
+		data, err := p.Read(target)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		if dict, err = plist.DecodeDict(data); err != nil {
+			return "", nil, nil, fmt.Errorf("%s: %w", target, err)
+		}
+	} else {
+		target = path.Join(p.appDir(), PrivacyManifestName)
+	}
+
+	for _, key := range []string{"NSPrivacyTrackingDomains", "NSPrivacyCollectedDataTypes", "NSPrivacyAccessedAPITypes"} {
+		if _, ok := dict[key]; !ok {
+			dict[key] = []interface{}{}
+		}
+	}
+	if _, ok := dict["NSPrivacyTracking"]; !ok {
+		dict["NSPrivacyTracking"] = false
+	}
+
+	declared := declaredCategories(dict)
+	types, _ := dict["NSPrivacyAccessedAPITypes"].([]interface{})
+	for _, use := range p.ReasonUsages() {
+		if declared[use.Category] {
+			continue
+		}
+		r, _ := lookupReasonAPI(use.Category)
+		types = append(types, map[string]interface{}{
+			"NSPrivacyAccessedAPIType":        use.Category,
+			"NSPrivacyAccessedAPITypeReasons": []interface{}{r.DefaultReason},
+		})
+		added = append(added, use.Category)
+	}
+	dict["NSPrivacyAccessedAPITypes"] = types
+
+	return target, dict, added, nil
+}
+
+// appDir is the directory of the main app's Info.plist, or the root.
+func (p *Project) appDir() string {
+	for _, rel := range p.Find(func(rel string) bool {
+		return isInfoPlist(rel) && !isTestPath(rel) && !isVendorPath(rel)
+	}) {
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		if dict, err := plist.DecodeDict(data); err == nil {
+			if _, isExtension := dict["NSExtension"]; !isExtension {
+				return path.Dir(rel)
+			}
+		}
+	}
+	return "."
+}
+
+func lookupReasonAPI(category string) (reasonAPI, bool) {
+	for _, r := range reasonAPIs {
+		if r.Category == category {
+			return r, true
+		}
+	}
+	return reasonAPI{}, false
+}
+
+func isSDKDir(rel string) bool {
+	for _, dir := range sdkDirs {
+		if rel == dir || strings.HasSuffix(rel, "/"+dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// isVendorPath reports whether a file belongs to a bundled dependency.
+func isVendorPath(rel string) bool {
+	for _, dir := range sdkDirs {
+		if strings.HasPrefix(rel, dir+"/") || strings.Contains(rel, "/"+dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func readDirs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+var lineCommentRe = regexp.MustCompile(`(?m)^\s*//.*$`)
+
+func stripLineComments(source string) string {
+	return lineCommentRe.ReplaceAllString(source, "")
+}
+
+func allStrings(items []interface{}) bool {
+	for _, item := range items {
+		if _, ok := item.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
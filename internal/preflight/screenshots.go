@@ -0,0 +1,338 @@
+package preflight
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+)
+
+const (
+	RuleAppleScreenshotSize     = "APL-SHOT-001"
+	RuleAppleScreenshotRequired = "APL-SHOT-002"
+	RuleAppleScreenshotCount    = "APL-SHOT-003"
+	RuleAppleScreenshotIPad     = "APL-SHOT-004"
+	RuleGoogleScreenshotSize    = "GPL-SHOT-001"
+	RuleGoogleScreenshotMissing = "GPL-SHOT-002"
+	RuleGoogleScreenshotCount   = "GPL-SHOT-003"
+	RuleGoogleFeatureGraphic    = "GPL-SHOT-004"
+)
+
+// displayClass is an App Store Connect screenshot size class. Sizes are
+// portrait; landscape screenshots are the same sizes rotated.
+type displayClass struct {
+	Name   string
+	Family string
+	Sizes  [][2]int
+}
+
+var appleDisplayClasses = []displayClass{
+	{Name: `iPhone 6.9"`, Family: "iphone", Sizes: [][2]int{{1320, 2868}, {1290, 2796}, {1260, 2736}}},
+	{Name: `iPhone 6.5"`, Family: "iphone", Sizes: [][2]int{{1242, 2688}, {1284, 2778}}},
+	{Name: `iPhone 6.3"`, Family: "iphone", Sizes: [][2]int{{1206, 2622}, {1179, 2556}}},
+	{Name: `iPhone 6.1"`, Family: "iphone", Sizes: [][2]int{{1170, 2532}, {1125, 2436}, {1080, 2340}}},
+	{Name: `iPhone 5.5"`, Family: "iphone", Sizes: [][2]int{{1242, 2208}}},
+	{Name: `iPhone 4.7"`, Family: "iphone", Sizes: [][2]int{{750, 1334}}},
+	{Name: `iPhone 4"`, Family: "iphone", Sizes: [][2]int{{640, 1096}, {640, 1136}}},
+	{Name: `iPhone 3.5"`, Family: "iphone", Sizes: [][2]int{{640, 920}, {640, 960}}},
+	{Name: `iPad 13"`, Family: "ipad", Sizes: [][2]int{{2064, 2752}, {2048, 2732}}},
+	{Name: `iPad 11"`, Family: "ipad", Sizes: [][2]int{{1488, 2266}, {1668, 2420}, {1668, 2388}, {1640, 2360}}},
+	{Name: `iPad 10.5"`, Family: "ipad", Sizes: [][2]int{{1668, 2224}}},
+	{Name: `iPad 9.7"`, Family: "ipad", Sizes: [][2]int{{1536, 2008}, {1536, 2048}, {768, 1004}, {768, 1024}}},
+	{Name: "Mac", Family: "mac", Sizes: [][2]int{{800, 1280}, {900, 1440}, {1600, 2560}, {1800, 2880}}},
+	{Name: "Apple TV", Family: "tv", Sizes: [][2]int{{1080, 1920}, {2160, 3840}}},
+	{Name: "Apple Watch", Family: "watch", Sizes: [][2]int{{422, 514}, {410, 502}, {416, 496}, {396, 484}, {368, 448}, {312, 390}}},
+}
+
+// requiredIPhoneClasses are the classes App Store Connect accepts as the
+// mandatory iPhone screenshots.
+var requiredIPhoneClasses = []string{`iPhone 6.9"`, `iPhone 6.5"`}
+
+const (
+	appleScreenshotLimit  = 10
+	googleScreenshotLimit = 8
+)
+
+// googleScreenshotTypes are the screenshot directories of a supply locale's
+// images/. Screenshots allow 320 to 3840 px per side at up to 2:1; the
+// fixed graphics in googleGraphicSizes need their exact size.
+var googleScreenshotTypes = []string{"phoneScreenshots", "sevenInchScreenshots", "tenInchScreenshots", "tvScreenshots", "wearScreenshots"}
+
+var googleGraphicSizes = map[string][2]int{
+	"featureGraphic": {1024, 500},
+	"icon":           {512, 512},
+	"tvBanner":       {1280, 720},
+}
+
+var imageExts = []string{".png", ".jpg", ".jpeg"}
+
+type screenshot struct {
+	File   string
+	Locale string
+	Group  string
+}
+
+func (p *Project) checkScreenshots() []api.Finding {
+	return append(p.checkAppleScreenshots(), p.checkGoogleImages()...)
+}
+
+// appleScreenshots returns the images in deliver's screenshots/<locale>/
+// directories. When framed copies exist, only those are uploaded.
+func (p *Project) appleScreenshots() []screenshot {
+	var shots []screenshot
+	framed := make(map[string]bool)
+	for _, rel := range p.Files {
+		if !slices.Contains(imageExts, strings.ToLower(path.Ext(rel))) || isVendorPath(rel) {
+			continue
+		}
+		dir := path.Dir(rel)
+		locale := path.Base(dir)
+		if path.Base(path.Dir(dir)) != "screenshots" || !storeLocaleRe.MatchString(locale) {
+			continue
+		}
+		if strings.Contains(path.Base(rel), "_framed") {
+			framed[dir] = true
+		}
+		shots = append(shots, screenshot{File: rel, Locale: locale, Group: dir})
+	}
+
+	out := shots[:0]
+	for _, s := range shots {
+		if !framed[s.Group] || strings.Contains(path.Base(s.File), "_framed") {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (p *Project) checkAppleScreenshots() []api.Finding {
+	var findings []api.Finding
+	counts := make(map[string]map[string]int)
+	var dirs []string
+
+	for _, s := range p.appleScreenshots() {
+		if counts[s.Group] == nil {
+			counts[s.Group] = make(map[string]int)
+			dirs = append(dirs, s.Group)
+		}
+		w, h, err := p.imageSize(s.File)
+		if err != nil {
+			findings = append(findings, screenshotFinding(api.PlatformApple, RuleAppleScreenshotSize, "Invalid screenshot", "HIGH", s.File,
+				fmt.Sprintf("%s cannot be read as a PNG or JPEG image: %v", s.File, err), nil, "Export the screenshot as PNG or JPEG"))
+			continue
+		}
+		class, ok := appleDisplayClass(w, h)
+		if !ok {
+			findings = append(findings, screenshotFinding(api.PlatformApple, RuleAppleScreenshotSize, "Invalid screenshot", "HIGH", s.File,
+				fmt.Sprintf("%s is %dx%d, which matches no App Store Connect screenshot size. The upload is rejected.", s.File, w, h),
+				map[string]interface{}{"locale": s.Locale, "width": w, "height": h},
+				fmt.Sprintf("Capture the screenshot on a supported device, e.g. %dx%d for iPhone 6.9\"", appleDisplayClasses[0].Sizes[0][0], appleDisplayClasses[0].Sizes[0][1])))
+			continue
+		}
+		counts[s.Group][class.Name]++
+	}
+
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		locale := path.Base(dir)
+		byClass := counts[dir]
+
+		var classes []string
+		iphone, ipad, ipad13, required := false, false, false, false
+		for name := range byClass {
+			classes = append(classes, name)
+		}
+		sort.Strings(classes)
+		for _, name := range classes {
+			class, _ := findDisplayClass(name)
+			switch class.Family {
+			case "iphone":
+				iphone = true
+				required = required || slices.Contains(requiredIPhoneClasses, name)
+			case "ipad":
+				ipad = true
+				ipad13 = ipad13 || name == `iPad 13"`
+			}
+			if byClass[name] > appleScreenshotLimit {
+				findings = append(findings, screenshotFinding(api.PlatformApple, RuleAppleScreenshotCount, "Too many screenshots", "MEDIUM", dir,
+					fmt.Sprintf("%s has %d %s screenshots; App Store Connect accepts %d per display size.", dir, byClass[name], name, appleScreenshotLimit),
+					map[string]interface{}{"locale": locale, "display": name, "count": byClass[name]},
+					fmt.Sprintf("Keep at most %d screenshots per display size", appleScreenshotLimit)))
+			}
+		}
+
+		if iphone && !required {
+			findings = append(findings, screenshotFinding(api.PlatformApple, RuleAppleScreenshotRequired, "Missing required screenshots", "HIGH", dir,
+				fmt.Sprintf("%s has iPhone screenshots (%s) but none for the 6.9\" or 6.5\" display, which App Store Connect requires.", dir, strings.Join(classes, ", ")),
+				map[string]interface{}{"locale": locale, "displays": classes},
+				"Add 1320x2868 (6.9\") or 1284x2778 (6.5\") screenshots"))
+		}
+		if ipad && !ipad13 {
+			findings = append(findings, screenshotFinding(api.PlatformApple, RuleAppleScreenshotIPad, "Missing required screenshots", "HIGH", dir,
+				fmt.Sprintf("%s has iPad screenshots but none for the 13\" display, which App Store Connect requires for apps that run on iPad.", dir),
+				map[string]interface{}{"locale": locale, "displays": classes},
+				"Add 2064x2752 or 2048x2732 (13\") iPad screenshots"))
+		}
+	}
+	return findings
+}
+
+// checkGoogleImages checks supply's <locale>/images/ directories.
+func (p *Project) checkGoogleImages() []api.Finding {
+	var findings []api.Finding
+	counts := make(map[string]map[string]int)
+	var imageDirs []string
+	featureGraphic := false
+
+	for _, rel := range p.Files {
+		if !slices.Contains(imageExts, strings.ToLower(path.Ext(rel))) || isVendorPath(rel) {
+			continue
+		}
+		imagesDir, kind, ok := googleImageKind(rel)
+		if !ok {
+			continue
+		}
+		if counts[imagesDir] == nil {
+			counts[imagesDir] = make(map[string]int)
+			imageDirs = append(imageDirs, imagesDir)
+		}
+		locale := path.Base(path.Dir(imagesDir))
+
+		w, h, err := p.imageSize(rel)
+		if err != nil {
+			findings = append(findings, screenshotFinding(api.PlatformGoogle, RuleGoogleScreenshotSize, "Invalid store image", "HIGH", rel,
+				fmt.Sprintf("%s cannot be read as a PNG or JPEG image: %v", rel, err), nil, "Export the image as PNG or JPEG"))
+			continue
+		}
+
+		evidence := map[string]interface{}{"locale": locale, "type": kind, "width": w, "height": h}
+		if size, fixed := googleGraphicSizes[kind]; fixed {
+			featureGraphic = featureGraphic || kind == "featureGraphic"
+			if w != size[0] || h != size[1] {
+				findings = append(findings, screenshotFinding(api.PlatformGoogle, RuleGoogleScreenshotSize, "Invalid store image", "HIGH", rel,
+					fmt.Sprintf("%s is %dx%d; Play requires %dx%d for %s.", rel, w, h, size[0], size[1], kind), evidence,
+					fmt.Sprintf("Export %s at %dx%d", kind, size[0], size[1])))
+			}
+			continue
+		}
+
+		counts[imagesDir][kind]++
+		short, long := min(w, h), max(w, h)
+		switch {
+		case kind == "wearScreenshots" && w != h:
+			findings = append(findings, screenshotFinding(api.PlatformGoogle, RuleGoogleScreenshotSize, "Invalid store image", "HIGH", rel,
+				fmt.Sprintf("%s is %dx%d; Wear OS screenshots must be square.", rel, w, h), evidence,
+				"Export Wear OS screenshots at 1:1, at least 384x384"))
+		case short < 320 || long > 3840 || long > 2*short:
+			findings = append(findings, screenshotFinding(api.PlatformGoogle, RuleGoogleScreenshotSize, "Invalid store image", "HIGH", rel,
+				fmt.Sprintf("%s is %dx%d; Play screenshots need 320 to 3840 px per side and an aspect ratio of at most 2:1.", rel, w, h), evidence,
+				"Resize the screenshot, e.g. to 1080x1920"))
+		}
+	}
+
+	sort.Strings(imageDirs)
+	for _, dir := range imageDirs {
+		locale := path.Base(path.Dir(dir))
+		total := 0
+		for _, kind := range googleScreenshotTypes {
+			n := counts[dir][kind]
+			total += n
+			if n > googleScreenshotLimit {
+				findings = append(findings, screenshotFinding(api.PlatformGoogle, RuleGoogleScreenshotCount, "Too many screenshots", "MEDIUM", path.Join(dir, kind),
+					fmt.Sprintf("%s has %d screenshots; Play accepts %d per device type.", path.Join(dir, kind), n, googleScreenshotLimit),
+					map[string]interface{}{"locale": locale, "type": kind, "count": n},
+					fmt.Sprintf("Keep at most %d %s", googleScreenshotLimit, kind)))
+			}
+		}
+		if total < 2 {
+			findings = append(findings, screenshotFinding(api.PlatformGoogle, RuleGoogleScreenshotMissing, "Missing required screenshots", "HIGH", dir,
+				fmt.Sprintf("%s has %d screenshots; Play requires at least 2 to publish the listing.", dir, total),
+				map[string]interface{}{"locale": locale, "count": total},
+				"Add at least two phoneScreenshots"))
+		}
+	}
+
+	if len(imageDirs) > 0 && !featureGraphic {
+		findings = append(findings, screenshotFinding(api.PlatformGoogle, RuleGoogleFeatureGraphic, "Missing feature graphic", "HIGH", imageDirs[0],
+			"No Play locale has images/featureGraphic.png. A 1024x500 feature graphic is required to publish the listing.",
+			nil, "Add a 1024x500 featureGraphic.png to the default language's images directory"))
+	}
+	return findings
+}
+
+// googleImageKind classifies a file under android/<locale>/images/: the
+// screenshot directory it is in, or the base name of a fixed graphic.
+func googleImageKind(rel string) (imagesDir, kind string, ok bool) {
+	parts := strings.Split(rel, "/")
+	for i := 2; i < len(parts)-1; i++ {
+		if parts[i] != "images" || parts[i-2] != "android" || !storeLocaleRe.MatchString(parts[i-1]) {
+			continue
+		}
+		imagesDir = strings.Join(parts[:i+1], "/")
+		switch rest := parts[i+1:]; {
+		case len(rest) == 2 && slices.Contains(googleScreenshotTypes, rest[0]):
+			return imagesDir, rest[0], true
+		case len(rest) == 1:
+			kind = strings.TrimSuffix(rest[0], path.Ext(rest[0]))
+			if _, fixed := googleGraphicSizes[kind]; fixed {
+				return imagesDir, kind, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+func (p *Project) imageSize(rel string) (int, int, error) {
+	data, err := p.Read(rel)
+	if err != nil {
+		return 0, 0, err
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+func appleDisplayClass(w, h int) (displayClass, bool) {
+	short, long := min(w, h), max(w, h)
+	for _, class := range appleDisplayClasses {
+		for _, size := range class.Sizes {
+			if size[0] == short && size[1] == long {
+				return class, true
+			}
+		}
+	}
+	return displayClass{}, false
+}
+
+func findDisplayClass(name string) (displayClass, bool) {
+	for _, class := range appleDisplayClasses {
+		if class.Name == name {
+			return class, true
+		}
+	}
+	return displayClass{}, false
+}
+
+func screenshotFinding(platform api.Platform, rule, name, severity, file, message string, evidence map[string]interface{}, action string) api.Finding {
+	return api.Finding{
+		RuleCode: rule,
+		RuleName: name,
+		Severity: severity,
+		Message:  message,
+		FilePath: file,
+		Evidence: evidence,
+		Remediation: &api.Remediation{
+			Action: action,
+		},
+		Platform: string(platform),
+	}
+}
@@ -0,0 +1,237 @@
+package preflight
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"reflect"
+	"testing"
+)
+
+// pngOf returns a blank PNG image of the given size.
+func pngOf(t *testing.T, w, h int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestAppleDisplayClass(t *testing.T) {
+	tests := []struct {
+		w, h int
+		want string
+	}{
+		{1320, 2868, `iPhone 6.9"`},
+		{2868, 1320, `iPhone 6.9"`},
+		{1284, 2778, `iPhone 6.5"`},
+		{1179, 2556, `iPhone 6.3"`},
+		{2048, 2732, `iPad 13"`},
+		{1080, 1920, "Apple TV"},
+		{1000, 2000, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%dx%d", tt.w, tt.h), func(t *testing.T) {
+			class, ok := appleDisplayClass(tt.w, tt.h)
+			if ok != (tt.want != "") || class.Name != tt.want {
+				t.Errorf("appleDisplayClass(%d, %d) = %q, %v, want %q", tt.w, tt.h, class.Name, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAppleScreenshots(t *testing.T) {
+	const dir = "fastlane/screenshots/en-US"
+	iphone69 := pngOf(t, 1320, 2868)
+	iphone63 := pngOf(t, 1179, 2556)
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name: "required iPhone size",
+			files: map[string]string{
+				dir + "/1_iphone69.png": iphone69,
+				dir + "/2_iphone63.png": iphone63,
+			},
+		},
+		{
+			name: "no 6.9 or 6.5 inch screenshots",
+			files: map[string]string{
+				dir + "/1_iphone63.png": iphone63,
+			},
+			want: []string{RuleAppleScreenshotRequired + " " + dir},
+		},
+		{
+			name: "unknown size",
+			files: map[string]string{
+				dir + "/1_iphone69.png": iphone69,
+				dir + "/2_odd.png":      pngOf(t, 1000, 2000),
+			},
+			want: []string{RuleAppleScreenshotSize + " " + dir + "/2_odd.png"},
+		},
+		{
+			name: "unreadable image",
+			files: map[string]string{
+				dir + "/1_iphone69.png": iphone69,
+				dir + "/2_broken.png":   "not a png",
+			},
+			want: []string{RuleAppleScreenshotSize + " " + dir + "/2_broken.png"},
+		},
+		{
+			name: "iPad without the 13 inch size",
+			files: map[string]string{
+				dir + "/1_iphone69.png": iphone69,
+				dir + "/2_ipad11.png":   pngOf(t, 1668, 2388),
+			},
+			want: []string{RuleAppleScreenshotIPad + " " + dir},
+		},
+		{
+			name: "framed copies replace the originals",
+			files: map[string]string{
+				dir + "/1_iphone63.png":        iphone63,
+				dir + "/1_iphone63_framed.png": iphone69,
+			},
+		},
+		{
+			name: "not a locale directory",
+			files: map[string]string{
+				"fastlane/screenshots/originals/1_odd.png": pngOf(t, 1000, 2000),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProject(t, tt.files)
+			if got := findingKeys(p.checkAppleScreenshots()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAppleScreenshotsCount(t *testing.T) {
+	const dir = "fastlane/screenshots/en-US"
+	iphone69 := pngOf(t, 1320, 2868)
+	files := make(map[string]string)
+	for i := 0; i <= appleScreenshotLimit; i++ {
+		files[fmt.Sprintf("%s/%02d_iphone69.png", dir, i)] = iphone69
+	}
+
+	p := newTestProject(t, files)
+	want := []string{RuleAppleScreenshotCount + " " + dir}
+	if got := findingKeys(p.checkAppleScreenshots()); !reflect.DeepEqual(got, want) {
+		t.Errorf("findings = %v, want %v", got, want)
+	}
+}
+
+func TestCheckGoogleImages(t *testing.T) {
+	const images = "fastlane/metadata/android/en-US/images"
+	phone := pngOf(t, 1080, 1920)
+	feature := pngOf(t, 1024, 500)
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name: "valid images",
+			files: map[string]string{
+				images + "/featureGraphic.png":     feature,
+				images + "/icon.png":               pngOf(t, 512, 512),
+				images + "/phoneScreenshots/1.png": phone,
+				images + "/phoneScreenshots/2.png": phone,
+			},
+		},
+		{
+			name: "one screenshot",
+			files: map[string]string{
+				images + "/featureGraphic.png":     feature,
+				images + "/phoneScreenshots/1.png": phone,
+			},
+			want: []string{RuleGoogleScreenshotMissing + " " + images},
+		},
+		{
+			name: "missing feature graphic",
+			files: map[string]string{
+				images + "/phoneScreenshots/1.png": phone,
+				images + "/phoneScreenshots/2.png": phone,
+			},
+			want: []string{RuleGoogleFeatureGraphic + " " + images},
+		},
+		{
+			name: "wrong feature graphic size",
+			files: map[string]string{
+				images + "/featureGraphic.png":     pngOf(t, 1024, 512),
+				images + "/phoneScreenshots/1.png": phone,
+				images + "/phoneScreenshots/2.png": phone,
+			},
+			want: []string{RuleGoogleScreenshotSize + " " + images + "/featureGraphic.png"},
+		},
+		{
+			name: "aspect ratio over 2:1",
+			files: map[string]string{
+				images + "/featureGraphic.png":     feature,
+				images + "/phoneScreenshots/1.png": phone,
+				images + "/phoneScreenshots/2.png": pngOf(t, 400, 1000),
+			},
+			want: []string{RuleGoogleScreenshotSize + " " + images + "/phoneScreenshots/2.png"},
+		},
+		{
+			name: "non-square Wear OS screenshot",
+			files: map[string]string{
+				images + "/featureGraphic.png":     feature,
+				images + "/phoneScreenshots/1.png": phone,
+				images + "/phoneScreenshots/2.png": phone,
+				images + "/wearScreenshots/1.png":  pngOf(t, 384, 384),
+				images + "/wearScreenshots/2.png":  pngOf(t, 384, 400),
+			},
+			want: []string{RuleGoogleScreenshotSize + " " + images + "/wearScreenshots/2.png"},
+		},
+		{
+			name: "images outside android/ are ignored",
+			files: map[string]string{
+				"fastlane/metadata/en-US/images/featureGraphic.png": pngOf(t, 10, 10),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProject(t, tt.files)
+			if got := findingKeys(p.checkGoogleImages()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoogleImageKind(t *testing.T) {
+	tests := []struct {
+		rel       string
+		imagesDir string
+		kind      string
+	}{
+		{"fastlane/metadata/android/en-US/images/phoneScreenshots/1.png", "fastlane/metadata/android/en-US/images", "phoneScreenshots"},
+		{"fastlane/metadata/android/en-US/images/featureGraphic.jpg", "fastlane/metadata/android/en-US/images", "featureGraphic"},
+		{"fastlane/metadata/android/en-US/images/promoGraphic.png", "", ""},
+		{"fastlane/metadata/android/en-US/images/phoneScreenshots/nested/1.png", "", ""},
+		{"fastlane/metadata/android/originals/images/icon.png", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rel, func(t *testing.T) {
+			imagesDir, kind, ok := googleImageKind(tt.rel)
+			if ok != (tt.kind != "") || imagesDir != tt.imagesDir || kind != tt.kind {
+				t.Errorf("googleImageKind(%q) = %q, %q, %v, want %q, %q", tt.rel, imagesDir, kind, ok, tt.imagesDir, tt.kind)
+			}
+		})
+	}
+}
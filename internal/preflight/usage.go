@@ -0,0 +1,384 @@
+package preflight
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/plist"
+)
+
+const (
+	RuleMissingUsage     = "APL-PLIST-001"
+	RuleEmptyUsage       = "APL-PLIST-002"
+	RulePlaceholderUsage = "APL-PLIST-003"
+	RuleVagueUsage       = "APL-PLIST-004"
+	RuleInvalidPlist     = "APL-PLIST-005"
+)
+
+// minPurposeLength is the shortest purpose string that can plausibly
+// explain why access is needed.
+const minPurposeLength = 12
+
+// usageRequirement maps APIs to the Info.plist keys they need. Any of Keys
+// satisfies the requirement; the first is suggested when none is present.
+type usageRequirement struct {
+	Feature    string
+	Keys       []string
+	Frameworks []string
+	Symbols    []string
+}
+
+var usageRequirements = []usageRequirement{
+	{Feature: "camera", Keys: []string{"NSCameraUsageDescription"},
+		Symbols: []string{"AVCaptureDevice", "AVCaptureSession", "VNDocumentCameraViewController", "DataScannerViewController"}},
+	{Feature: "microphone", Keys: []string{"NSMicrophoneUsageDescription"},
+		Symbols: []string{"AVAudioRecorder", "requestRecordPermission"}},
+	{Feature: "location", Keys: []string{"NSLocationWhenInUseUsageDescription", "NSLocationAlwaysAndWhenInUseUsageDescription"},
+		Frameworks: []string{"CoreLocation"}, Symbols: []string{"CLLocationManager"}},
+	{Feature: "background location", Keys: []string{"NSLocationAlwaysAndWhenInUseUsageDescription"},
+		Symbols: []string{"requestAlwaysAuthorization"}},
+	{Feature: "photo library", Keys: []string{"NSPhotoLibraryUsageDescription", "NSPhotoLibraryAddUsageDescription"},
+		Frameworks: []string{"Photos"}, Symbols: []string{"PHPhotoLibrary", "UIImageWriteToSavedPhotosAlbum"}},
+	{Feature: "contacts", Keys: []string{"NSContactsUsageDescription"},
+		Frameworks: []string{"Contacts", "AddressBook"}, Symbols: []string{"CNContactStore"}},
+	{Feature: "calendars and reminders", Keys: []string{"NSCalendarsFullAccessUsageDescription", "NSCalendarsUsageDescription", "NSCalendarsWriteOnlyAccessUsageDescription", "NSRemindersFullAccessUsageDescription", "NSRemindersUsageDescription"},
+		Frameworks: []string{"EventKit"}, Symbols: []string{"EKEventStore"}},
+	{Feature: "health data", Keys: []string{"NSHealthShareUsageDescription", "NSHealthUpdateUsageDescription"},
+		Frameworks: []string{"HealthKit"}},
+	{Feature: "motion data", Keys: []string{"NSMotionUsageDescription"},
+		Frameworks: []string{"CoreMotion"}},
+	{Feature: "Bluetooth", Keys: []string{"NSBluetoothAlwaysUsageDescription"},
+		Frameworks: []string{"CoreBluetooth"}},
+	{Feature: "speech recognition", Keys: []string{"NSSpeechRecognitionUsageDescription"},
+		Frameworks: []string{"Speech"}},
+	{Feature: "Face ID", Keys: []string{"NSFaceIDUsageDescription"},
+		Frameworks: []string{"LocalAuthentication"}},
+	{Feature: "tracking", Keys: []string{"NSUserTrackingUsageDescription"},
+		Frameworks: []string{"AppTrackingTransparency"}},
+	{Feature: "media library", Keys: []string{"NSAppleMusicUsageDescription"},
+		Frameworks: []string{"MusicKit"}, Symbols: []string{"MPMediaLibrary", "MPMediaQuery"}},
+	{Feature: "HomeKit", Keys: []string{"NSHomeKitUsageDescription"},
+		Frameworks: []string{"HomeKit"}},
+	{Feature: "nearby interaction", Keys: []string{"NSNearbyInteractionUsageDescription"},
+		Frameworks: []string{"NearbyInteraction"}},
+}
+
+var placeholderPatterns = []string{
+	"todo", "tbd", "fixme", "placeholder", "lorem ipsum", "your description",
+	"usage description", "add description", "description here", "xxx", "change me",
+	"describe how the app uses",
+}
+
+var (
+	swiftImportRe   = regexp.MustCompile(`(?m)^\s*(?:@\w+\s+)*import\s+(?:(?:class|struct|enum|protocol|func|var|let|typealias)\s+)?(\w+)`)
+	objcImportRe    = regexp.MustCompile(`(?m)^\s*@import\s+(\w+)`)
+	headerImportRe  = regexp.MustCompile(`(?m)^\s*#(?:import|include)\s+<(\w+)/`)
+	pbxInfoPlistRe  = regexp.MustCompile(`INFOPLIST_KEY_(NS\w+UsageDescription)\s*=\s*("(?:[^"\\]|\\.)*"|[^;]*);`)
+	usageKeyPattern = regexp.MustCompile(`^NS\w+UsageDescription$`)
+)
+
+// purposeString is one declaration of a usage description key.
+type purposeString struct {
+	Key   string
+	Value string
+	File  string
+}
+
+// usageTrigger records the first file using an API that needs a key.
+type usageTrigger struct {
+	File   string
+	Reason string
+}
+
+func checkUsageDescriptions(p *Project) []api.Finding {
+	declared, findings, primary, ok := p.purposeStrings()
+	if !ok {
+		return nil
+	}
+
+	findings = append(findings, missingUsageFindings(p, declared, primary)...)
+	for _, d := range declared {
+		if f, ok := purposeStringFinding(d); ok {
+			findings = append(findings, f)
+		}
+	}
+
+	return findings
+}
+
+// PurposeKeys returns the usage description keys declared in Info.plist
+// files and build settings, mapped to the first file declaring each.
+func (p *Project) PurposeKeys() map[string]string {
+	declared, _, _, _ := p.purposeStrings()
+	keys := make(map[string]string, len(declared))
+	for _, d := range declared {
+		if _, seen := keys[d.Key]; !seen {
+			keys[d.Key] = d.File
+		}
+	}
+	return keys
+}
+
+// purposeStrings collects usage descriptions from Info.plist files and
+// project.pbxproj build settings. It also returns findings for plists that
+// cannot be parsed, the main app's Info.plist, and false when the project
+// has neither kind of file.
+func (p *Project) purposeStrings() (declared []purposeString, findings []api.Finding, primary string, ok bool) {
+	plists := p.Find(func(rel string) bool {
+		return isInfoPlist(rel) && !isTestPath(rel)
+	})
+	pbxprojs := p.FilesNamed("project.pbxproj")
+	if len(plists) == 0 && len(pbxprojs) == 0 {
+		return nil, nil, "", false
+	}
+
+	for _, rel := range plists {
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		dict, err := plist.DecodeDict(data)
+		if err != nil {
+			findings = append(findings, api.Finding{
+				RuleCode: RuleInvalidPlist,
+				RuleName: "Info.plist cannot be parsed",
+				Severity: "HIGH",
+				Message:  fmt.Sprintf("%s is not a valid property list: %v", rel, err),
+				FilePath: rel,
+				Platform: string(api.PlatformApple),
+			})
+			continue
+		}
+
+		if _, isExtension := dict["NSExtension"]; primary == "" && !isExtension {
+			primary = rel
+		}
+		keys := make([]string, 0, len(dict))
+		for key := range dict {
+			if usageKeyPattern.MatchString(key) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, _ := dict[key].(string)
+			declared = append(declared, purposeString{Key: key, Value: value, File: rel})
+		}
+	}
+
+	for _, rel := range pbxprojs {
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		// Each build configuration repeats its settings, usually with the
+		// same value; report a value once per file.
+		seen := make(map[purposeString]bool)
+		for _, m := range pbxInfoPlistRe.FindAllStringSubmatch(string(data), -1) {
+			d := purposeString{Key: m[1], Value: pbxValue(m[2]), File: rel}
+			if !seen[d] {
+				seen[d] = true
+				declared = append(declared, d)
+			}
+		}
+	}
+
+	if primary == "" && len(plists) > 0 {
+		primary = plists[0]
+	}
+	return declared, findings, primary, true
+}
+
+func missingUsageFindings(p *Project, declared []purposeString, primary string) []api.Finding {
+	keys := make(map[string]bool, len(declared))
+	for _, d := range declared {
+		keys[d.Key] = true
+	}
+
+	triggers := usageTriggers(p)
+
+	var findings []api.Finding
+	for _, req := range usageRequirements {
+		trigger, used := triggers[req.Feature]
+		if !used || anyKey(keys, req.Keys) {
+			continue
+		}
+
+		key := req.Keys[0]
+		findings = append(findings, api.Finding{
+			RuleCode: RuleMissingUsage,
+			RuleName: "Missing purpose string",
+			Severity: "BLOCKER",
+			Message: fmt.Sprintf("%s uses %s (%s) but no Info.plist declares %s. The app crashes when it requests access and is rejected in review.",
+				trigger.File, req.Feature, trigger.Reason, key),
+			FilePath: primary,
+			Evidence: map[string]interface{}{
+				"key":         key,
+				"feature":     req.Feature,
+				"source_file": trigger.File,
+				"reason":      trigger.Reason,
+			},
+			Remediation: &api.Remediation{
+				Action:   fmt.Sprintf("Add %s to Info.plist explaining why the app needs %s access", key, req.Feature),
+				Template: fmt.Sprintf("<key>%s</key>\n<string>Describe how the app uses %s</string>", key, req.Feature),
+			},
+			DocsURL:  docsURL(key),
+			Platform: string(api.PlatformApple),
+		})
+	}
+
+	return findings
+}
+
+// usageTriggers scans Swift and Objective-C sources for imports and
+// symbols that need a purpose string, keyed by feature.
+func usageTriggers(p *Project) map[string]usageTrigger {
+	return findTriggers(p, usageRequirements)
+}
+
+// findTriggers returns the first source file matching each requirement's
+// frameworks or symbols, keyed by feature.
+func findTriggers(p *Project, reqs []usageRequirement) map[string]usageTrigger {
+	triggers := make(map[string]usageTrigger)
+
+	for _, rel := range p.FilesWithExt(".swift", ".m", ".mm", ".h") {
+		if isTestPath(rel) {
+			continue
+		}
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		source := string(data)
+
+		imports := make(map[string]bool)
+		for _, re := range []*regexp.Regexp{swiftImportRe, objcImportRe, headerImportRe} {
+			for _, m := range re.FindAllStringSubmatch(source, -1) {
+				imports[m[1]] = true
+			}
+		}
+
+		for _, req := range reqs {
+			if _, seen := triggers[req.Feature]; seen {
+				continue
+			}
+			if reason := matchRequirement(req, imports, source); reason != "" {
+				triggers[req.Feature] = usageTrigger{File: rel, Reason: reason}
+			}
+		}
+	}
+
+	return triggers
+}
+
+func matchRequirement(req usageRequirement, imports map[string]bool, source string) string {
+	for _, fw := range req.Frameworks {
+		if imports[fw] {
+			return "imports " + fw
+		}
+	}
+	for _, sym := range req.Symbols {
+		if strings.Contains(source, sym) {
+			return "references " + sym
+		}
+	}
+	return ""
+}
+
+func purposeStringFinding(d purposeString) (api.Finding, bool) {
+	value := strings.TrimSpace(d.Value)
+
+	f := api.Finding{
+		FilePath: d.File,
+		Evidence: map[string]interface{}{"key": d.Key, "value": d.Value},
+		DocsURL:  docsURL(d.Key),
+		Platform: string(api.PlatformApple),
+		Remediation: &api.Remediation{
+			Action: fmt.Sprintf("Replace the %s value with a sentence explaining what the app does with this access and why", d.Key),
+		},
+	}
+
+	switch {
+	case value == "":
+		f.RuleCode = RuleEmptyUsage
+		f.RuleName = "Empty purpose string"
+		f.Severity = "BLOCKER"
+		f.Message = fmt.Sprintf("%s is empty in %s. App Review rejects permission prompts without a purpose.", d.Key, d.File)
+	case IsPlaceholder(d.Key, value):
+		f.RuleCode = RulePlaceholderUsage
+		f.RuleName = "Placeholder purpose string"
+		f.Severity = "HIGH"
+		f.Message = fmt.Sprintf("%s in %s looks like a placeholder: %q", d.Key, d.File, value)
+	case len([]rune(value)) < minPurposeLength && !strings.Contains(value, "$("):
+		f.RuleCode = RuleVagueUsage
+		f.RuleName = "Vague purpose string"
+		f.Severity = "MEDIUM"
+		f.Message = fmt.Sprintf("%s in %s is too short to explain why access is needed: %q", d.Key, d.File, value)
+	default:
+		return api.Finding{}, false
+	}
+
+	return f, true
+}
+
+// IsPlaceholder reports whether a purpose string is filler or template
+// text rather than an explanation a reviewer would accept.
+func IsPlaceholder(key, value string) bool {
+	lower := strings.ToLower(value)
+	if strings.EqualFold(value, key) {
+		return true
+	}
+	for _, pattern := range placeholderPatterns {
+		if strings.Contains(lower, pattern) {
+			return true
+		}
+	}
+	return repeatsOneChar(value)
+}
+
+func repeatsOneChar(s string) bool {
+	runes := []rune(s)
+	for _, r := range runes[1:] {
+		if r != runes[0] {
+			return false
+		}
+	}
+	return len(runes) > 1
+}
+
+// isInfoPlist matches Info.plist and legacy <Target>-Info.plist files,
+// but not configuration plists such as GoogleService-Info.plist.
+func isInfoPlist(rel string) bool {
+	base := path.Base(rel)
+	return base == "Info.plist" || (strings.HasSuffix(base, "-Info.plist") && base != "GoogleService-Info.plist")
+}
+
+func anyKey(keys map[string]bool, candidates []string) bool {
+	for _, k := range candidates {
+		if keys[k] {
+			return true
+		}
+	}
+	return false
+}
+
+// pbxValue unquotes a build setting value.
+func pbxValue(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, `"`) {
+		if s, err := strconv.Unquote(raw); err == nil {
+			return s
+		}
+		return strings.Trim(raw, `"`)
+	}
+	return raw
+}
+
+func docsURL(key string) string {
+	return "https://developer.apple.com/documentation/bundleresources/information_property_list/" + strings.ToLower(key)
+}
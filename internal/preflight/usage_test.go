@@ -0,0 +1,64 @@
+package preflight
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCheckUsageDescriptionsPbxproj(t *testing.T) {
+	const config = `		%s /* %s */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				INFOPLIST_KEY_NSCameraUsageDescription = %s;
+			};
+			name = %s;
+		};
+`
+	pbxproj := func(debug, release string) string {
+		return "{\n" +
+			fmt.Sprintf(config, "13B07F941A680F5B00A75B9A", "Debug", debug, "Debug") +
+			fmt.Sprintf(config, "13B07F951A680F5B00A75B9A", "Release", release, "Release") +
+			"}\n"
+	}
+
+	tests := []struct {
+		name    string
+		pbxproj string
+		want    []string
+	}{
+		{
+			name:    "same value in every configuration",
+			pbxproj: pbxproj("Cam", "Cam"),
+			want:    []string{"Cam"},
+		},
+		{
+			name:    "different values per configuration",
+			pbxproj: pbxproj("Cam", `"TODO"`),
+			want:    []string{"Cam", "TODO"},
+		},
+		{
+			name:    "valid purpose string",
+			pbxproj: pbxproj(`"Scan receipts to attach them to expenses"`, `"Scan receipts to attach them to expenses"`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProject(t, map[string]string{
+				"App.xcodeproj/project.pbxproj": tt.pbxproj,
+			})
+
+			var got []string
+			for _, f := range checkUsageDescriptions(p) {
+				if f.FilePath != "App.xcodeproj/project.pbxproj" {
+					t.Errorf("finding %s points at %s", f.RuleCode, f.FilePath)
+				}
+				got = append(got, f.Evidence["value"].(string))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("flagged values = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package preflight
+
+import (
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/plist"
+)
+
+// XcodeTarget is a native target of an Xcode project with the build
+// settings the checks need. Paths are relative to the project root.
+type XcodeTarget struct {
+	Name        string
+	ProductType string
+	// Project is the project.pbxproj the target is defined in.
+	Project      string
+	BundleID     string
+	InfoPlist    string
+	Entitlements []string
+}
+
+// IsApp reports whether the target builds an application rather than an
+// extension, framework or test bundle.
+func (t XcodeTarget) IsApp() bool {
+	return t.ProductType == "com.apple.product-type.application"
+}
+
+// XcodeTargets parses the native targets of every project.pbxproj. Projects
+// that cannot be parsed are skipped.
+func (p *Project) XcodeTargets() []XcodeTarget {
+	var targets []XcodeTarget
+	for _, rel := range p.FilesNamed("project.pbxproj") {
+		if isVendorPath(rel) {
+			continue
+		}
+		data, err := p.Read(rel)
+		if err != nil {
+			continue
+		}
+		parsed, err := parsePbxproj(rel, data)
+		if err != nil {
+			continue
+		}
+		targets = append(targets, parsed...)
+	}
+	return targets
+}
+
+func parsePbxproj(rel string, data []byte) ([]XcodeTarget, error) {
+	root, err := plist.DecodeDict(data)
+	if err != nil {
+		return nil, err
+	}
+	objects, _ := root["objects"].(map[string]interface{})
+	object := func(id interface{}) map[string]interface{} {
+		s, _ := id.(string)
+		o, _ := objects[s].(map[string]interface{})
+		return o
+	}
+
+	// configurations returns the build settings of a configuration list by
+	// configuration name.
+	configurations := func(listID interface{}) map[string]map[string]interface{} {
+		out := make(map[string]map[string]interface{})
+		list := object(listID)
+		ids, _ := list["buildConfigurations"].([]interface{})
+		for _, id := range ids {
+			cfg := object(id)
+			name, _ := cfg["name"].(string)
+			settings, _ := cfg["buildSettings"].(map[string]interface{})
+			out[name] = settings
+		}
+		return out
+	}
+
+	var projectSettings map[string]map[string]interface{}
+	if project := object(root["rootObject"]); project != nil {
+		projectSettings = configurations(project["buildConfigurationList"])
+	}
+
+	// SRCROOT is the directory holding the .xcodeproj bundle.
+	srcRoot := path.Dir(path.Dir(rel))
+
+	var targets []XcodeTarget
+	for _, v := range objects {
+		obj, _ := v.(map[string]interface{})
+		if obj["isa"] != "PBXNativeTarget" {
+			continue
+		}
+
+		t := XcodeTarget{Project: rel}
+		t.Name, _ = obj["name"].(string)
+		t.ProductType, _ = obj["productType"].(string)
+
+		configs := configurations(obj["buildConfigurationList"])
+		names := make([]string, 0, len(configs))
+		for name := range configs {
+			names = append(names, name)
+		}
+		// Release first, so single-valued settings prefer it.
+		sort.Slice(names, func(i, j int) bool {
+			ri, rj := names[i] == "Release", names[j] == "Release"
+			if ri != rj {
+				return ri
+			}
+			return names[i] < names[j]
+		})
+
+		for _, name := range names {
+			setting := func(key string) string {
+				if s, ok := configs[name][key].(string); ok {
+					return s
+				}
+				s, _ := projectSettings[name][key].(string)
+				return s
+			}
+
+			if file := resolveBuildPath(setting("CODE_SIGN_ENTITLEMENTS"), srcRoot, t.Name); file != "" && !slices.Contains(t.Entitlements, file) {
+				t.Entitlements = append(t.Entitlements, file)
+			}
+			if t.InfoPlist == "" {
+				t.InfoPlist = resolveBuildPath(setting("INFOPLIST_FILE"), srcRoot, t.Name)
+			}
+			if t.BundleID == "" {
+				t.BundleID = setting("PRODUCT_BUNDLE_IDENTIFIER")
+			}
+		}
+
+		targets = append(targets, t)
+	}
+
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+	return targets, nil
+}
+
+// resolveBuildPath turns a path build setting into a project-relative path.
+func resolveBuildPath(value, srcRoot, target string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	for _, v := range []string{"SRCROOT", "PROJECT_DIR", "SOURCE_ROOT"} {
+		value = strings.NewReplacer("$("+v+")/", "", "${"+v+"}/", "", "$("+v+")", "", "${"+v+"}", "").Replace(value)
+	}
+	for _, v := range []string{"TARGET_NAME", "PRODUCT_NAME"} {
+		value = strings.NewReplacer("$("+v+")", target, "${"+v+"}", target).Replace(value)
+	}
+	if strings.HasPrefix(value, "/") || strings.Contains(value, "$") {
+		return ""
+	}
+	return path.Clean(path.Join(srcRoot, value))
+}
@@ -0,0 +1,132 @@
+package rules
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/config"
+	"github.com/hha-nguyen/canopy-cli/internal/match"
+)
+
+const categoryPrefix = "category:"
+
+type Policy struct {
+	Overrides map[string]config.RuleConfig
+	Only      []string
+	Skip      []string
+}
+
+type Report struct {
+	Removed   int
+	Overrides int
+}
+
+func (p Policy) IsEmpty() bool {
+	return len(p.Overrides) == 0 && len(p.Only) == 0 && len(p.Skip) == 0
+}
+
+// Apply drops findings excluded by the filters or disabled in the overrides,
+// applies severity overrides, and recomputes the summary.
+func Apply(result *api.ScanResult, policy Policy) *Report {
+	report := &Report{}
+	patterns := sortedPatterns(policy.Overrides)
+
+	kept := result.Findings[:0]
+	for _, finding := range result.Findings {
+		if !policy.included(finding) {
+			report.Removed++
+			continue
+		}
+
+		override, ok := resolveOverride(finding, patterns, policy.Overrides)
+		if ok && override.Enabled != nil && !*override.Enabled {
+			report.Removed++
+			continue
+		}
+
+		if ok && override.Severity != "" {
+			severity := strings.ToUpper(override.Severity)
+			if !strings.EqualFold(severity, finding.Severity) {
+				if finding.OriginalSeverity == "" {
+					finding.OriginalSeverity = finding.Severity
+				}
+				finding.Severity = severity
+				report.Overrides++
+			}
+		}
+
+		kept = append(kept, finding)
+	}
+	result.Findings = kept
+
+	result.RecomputeSummary()
+	return report
+}
+
+func Matches(pattern string, finding api.Finding) bool {
+	pattern = strings.TrimSpace(pattern)
+	if strings.HasPrefix(strings.ToLower(pattern), categoryPrefix) {
+		category := strings.ToUpper(pattern[len(categoryPrefix):])
+		return match.Glob(category, strings.ToUpper(finding.RuleCategory()))
+	}
+	return match.Glob(strings.ToUpper(pattern), strings.ToUpper(finding.RuleCode))
+}
+
+func (p Policy) included(finding api.Finding) bool {
+	if len(p.Only) > 0 && !matchesAny(p.Only, finding) {
+		return false
+	}
+	return !matchesAny(p.Skip, finding)
+}
+
+func matchesAny(patterns []string, finding api.Finding) bool {
+	for _, pattern := range patterns {
+		if Matches(pattern, finding) {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveOverride(finding api.Finding, patterns []string, overrides map[string]config.RuleConfig) (config.RuleConfig, bool) {
+	for _, pattern := range patterns {
+		if Matches(pattern, finding) {
+			return overrides[pattern], true
+		}
+	}
+	return config.RuleConfig{}, false
+}
+
+// sortedPatterns orders override keys from most to least specific: exact
+// rule codes, then rule globs, then categories.
+func sortedPatterns(overrides map[string]config.RuleConfig) []string {
+	patterns := make([]string, 0, len(overrides))
+	for pattern := range overrides {
+		patterns = append(patterns, pattern)
+	}
+
+	sort.Slice(patterns, func(i, j int) bool {
+		si, sj := specificity(patterns[i]), specificity(patterns[j])
+		if si != sj {
+			return si > sj
+		}
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+
+	return patterns
+}
+
+func specificity(pattern string) int {
+	switch {
+	case strings.HasPrefix(strings.ToLower(pattern), categoryPrefix):
+		return 0
+	case strings.ContainsAny(pattern, "*?["):
+		return 1
+	default:
+		return 2
+	}
+}
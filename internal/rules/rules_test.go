@@ -0,0 +1,126 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/config"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestApply(t *testing.T) {
+	findings := []api.Finding{
+		{RuleCode: "APL-PRIV-012", Severity: "BLOCKER"},
+		{RuleCode: "APL-PRIV-003", Severity: "HIGH"},
+		{RuleCode: "APL-PLIST-001", Severity: "HIGH"},
+		{RuleCode: "GPL-SDK-001", Severity: "MEDIUM", Category: "sdk"},
+	}
+
+	tests := []struct {
+		name          string
+		policy        Policy
+		want          []string
+		wantSeverity  map[string]string
+		wantOriginal  map[string]string
+		wantRemoved   int
+		wantOverrides int
+		wantSummary   api.ScanSummary
+	}{
+		{
+			name:        "only",
+			policy:      Policy{Only: []string{"apl-priv-*"}},
+			want:        []string{"APL-PRIV-012", "APL-PRIV-003"},
+			wantRemoved: 2,
+			wantSummary: api.ScanSummary{Total: 2, Blocker: 1, High: 1},
+		},
+		{
+			name:        "skip wins over only",
+			policy:      Policy{Only: []string{"APL-*"}, Skip: []string{"APL-PLIST-001"}},
+			want:        []string{"APL-PRIV-012", "APL-PRIV-003"},
+			wantRemoved: 2,
+			wantSummary: api.ScanSummary{Total: 2, Blocker: 1, High: 1},
+		},
+		{
+			name:        "category",
+			policy:      Policy{Skip: []string{"category:APL-PRIV", "category:sdk"}},
+			want:        []string{"APL-PLIST-001"},
+			wantRemoved: 3,
+			wantSummary: api.ScanSummary{Total: 1, High: 1},
+		},
+		{
+			name: "most specific override wins",
+			policy: Policy{Overrides: map[string]config.RuleConfig{
+				"category:APL-PRIV": {Severity: "low"},
+				"APL-PRIV-*":        {Severity: "medium"},
+				"APL-PRIV-012":      {Severity: "high"},
+			}},
+			want:          []string{"APL-PRIV-012", "APL-PRIV-003", "APL-PLIST-001", "GPL-SDK-001"},
+			wantSeverity:  map[string]string{"APL-PRIV-012": "HIGH", "APL-PRIV-003": "MEDIUM"},
+			wantOriginal:  map[string]string{"APL-PRIV-012": "BLOCKER", "APL-PRIV-003": "HIGH"},
+			wantOverrides: 2,
+			wantSummary:   api.ScanSummary{Total: 4, High: 2, Medium: 2},
+		},
+		{
+			name: "same severity is not an override",
+			policy: Policy{Overrides: map[string]config.RuleConfig{
+				"APL-PLIST-001": {Severity: "high"},
+			}},
+			want:        []string{"APL-PRIV-012", "APL-PRIV-003", "APL-PLIST-001", "GPL-SDK-001"},
+			wantSummary: api.ScanSummary{Total: 4, Blocker: 1, High: 2, Medium: 1},
+		},
+		{
+			name: "disabled rule",
+			policy: Policy{Overrides: map[string]config.RuleConfig{
+				"GPL-*":         {Enabled: boolPtr(false)},
+				"APL-PLIST-001": {Enabled: boolPtr(true), Severity: "LOW"},
+			}},
+			want:          []string{"APL-PRIV-012", "APL-PRIV-003", "APL-PLIST-001"},
+			wantSeverity:  map[string]string{"APL-PLIST-001": "LOW"},
+			wantOriginal:  map[string]string{"APL-PLIST-001": "HIGH"},
+			wantRemoved:   1,
+			wantOverrides: 1,
+			wantSummary:   api.ScanSummary{Total: 3, Blocker: 1, High: 1, Low: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &api.ScanResult{Findings: append([]api.Finding{}, findings...)}
+			report := Apply(result, tt.policy)
+
+			var codes []string
+			for _, f := range result.Findings {
+				codes = append(codes, f.RuleCode)
+				if want, ok := tt.wantSeverity[f.RuleCode]; ok && f.Severity != want {
+					t.Errorf("%s severity = %q, want %q", f.RuleCode, f.Severity, want)
+				}
+				if f.OriginalSeverity != tt.wantOriginal[f.RuleCode] {
+					t.Errorf("%s original severity = %q, want %q", f.RuleCode, f.OriginalSeverity, tt.wantOriginal[f.RuleCode])
+				}
+			}
+			if !reflect.DeepEqual(codes, tt.want) {
+				t.Errorf("findings = %v, want %v", codes, tt.want)
+			}
+			if report.Removed != tt.wantRemoved || report.Overrides != tt.wantOverrides {
+				t.Errorf("report = %+v, want removed %d, overrides %d", report, tt.wantRemoved, tt.wantOverrides)
+			}
+			if result.Summary == nil || *result.Summary != tt.wantSummary {
+				t.Errorf("summary = %+v, want %+v", result.Summary, tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestApplyKeepsOriginalSeverity(t *testing.T) {
+	result := &api.ScanResult{Findings: []api.Finding{{RuleCode: "APL-PRIV-001", Severity: "BLOCKER"}}}
+	Apply(result, Policy{Overrides: map[string]config.RuleConfig{"APL-PRIV-001": {Severity: "HIGH"}}})
+	Apply(result, Policy{Overrides: map[string]config.RuleConfig{"APL-PRIV-001": {Severity: "LOW"}}})
+
+	if f := result.Findings[0]; f.Severity != "LOW" || f.OriginalSeverity != "BLOCKER" {
+		t.Errorf("finding = %+v, want LOW overriding BLOCKER", f)
+	}
+}
@@ -0,0 +1,155 @@
+package sbom
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	cycloneDXFormat  = "CycloneDX"
+	cycloneDXVersion = "1.5"
+)
+
+type bom struct {
+	BOMFormat    string         `json:"bomFormat"`
+	SpecVersion  string         `json:"specVersion"`
+	SerialNumber string         `json:"serialNumber,omitempty"`
+	Version      int            `json:"version"`
+	Metadata     bomMetadata    `json:"metadata"`
+	Components   []bomComponent `json:"components"`
+}
+
+type bomMetadata struct {
+	Timestamp string `json:"timestamp"`
+	Tools     struct {
+		Components []bomComponent `json:"components"`
+	} `json:"tools"`
+	Component *bomComponent `json:"component,omitempty"`
+}
+
+type bomComponent struct {
+	Type               string        `json:"type"`
+	BOMRef             string        `json:"bom-ref,omitempty"`
+	Group              string        `json:"group,omitempty"`
+	Name               string        `json:"name"`
+	Version            string        `json:"version,omitempty"`
+	PURL               string        `json:"purl,omitempty"`
+	Scope              string        `json:"scope,omitempty"`
+	ExternalReferences []bomExtRef   `json:"externalReferences,omitempty"`
+	Properties         []bomProperty `json:"properties,omitempty"`
+}
+
+type bomExtRef struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type bomProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CycloneDX encodes the inventory as a CycloneDX 1.5 JSON document.
+// project names the application the components belong to.
+func (inv *Inventory) CycloneDX(project, toolVersion string) ([]byte, error) {
+	doc := bom{
+		BOMFormat:    cycloneDXFormat,
+		SpecVersion:  cycloneDXVersion,
+		SerialNumber: serialNumber(),
+		Version:      1,
+		Components:   []bomComponent{},
+	}
+	doc.Metadata.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	doc.Metadata.Tools.Components = []bomComponent{{Type: "application", Name: "canopy", Version: toolVersion}}
+	if project != "" {
+		doc.Metadata.Component = &bomComponent{Type: "application", Name: project}
+	}
+
+	for _, c := range inv.Components {
+		purl := c.PURL()
+		bc := bomComponent{
+			Type:    "library",
+			BOMRef:  purl,
+			Group:   c.Group,
+			Name:    c.Name,
+			Version: c.Version,
+			PURL:    purl,
+			Scope:   "required",
+			Properties: []bomProperty{
+				{Name: "canopy:ecosystem", Value: c.Ecosystem},
+				{Name: "canopy:file", Value: c.File},
+				{Name: "canopy:direct", Value: fmt.Sprint(c.Direct)},
+			},
+		}
+		if bc.BOMRef == "" {
+			bc.BOMRef = c.ID() + "@" + c.Version
+		}
+		if c.Source != "" {
+			bc.ExternalReferences = []bomExtRef{{Type: "vcs", URL: c.Source}}
+		}
+		doc.Components = append(doc.Components, bc)
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// PURL returns the package URL of the component, or "" when the ecosystem
+// has no purl type for its source (e.g. Carthage binaries).
+func (c Component) PURL() string {
+	var purl string
+	switch c.Ecosystem {
+	case CocoaPods:
+		purl = "pkg:cocoapods/" + escape(c.Name)
+	case Maven:
+		purl = "pkg:maven/" + escape(c.Group) + "/" + escape(c.Name)
+	case Pub:
+		purl = "pkg:pub/" + escape(c.Name)
+	case NPM:
+		if scope, name, ok := strings.Cut(c.Name, "/"); ok {
+			purl = "pkg:npm/" + escape(scope) + "/" + escape(name)
+		} else {
+			purl = "pkg:npm/" + escape(c.Name)
+		}
+	case SwiftPM, Carthage:
+		u, err := url.Parse(c.Source)
+		if err != nil || u.Host == "" {
+			return ""
+		}
+		repo := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+		if c.Ecosystem == Carthage && u.Host == "github.com" {
+			purl = "pkg:github/" + repo
+		} else if c.Ecosystem == SwiftPM {
+			purl = "pkg:swift/" + u.Host + "/" + repo
+		} else {
+			return ""
+		}
+	default:
+		return ""
+	}
+	if c.Version != "" {
+		purl += "@" + escape(c.Version)
+	}
+	return purl
+}
+
+func escape(s string) string {
+	return strings.NewReplacer("+", "%2B", "@", "%40").Replace(url.PathEscape(s))
+}
+
+func serialNumber() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
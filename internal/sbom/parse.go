@@ -0,0 +1,358 @@
+package sbom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func readFile(root, rel string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+}
+
+var podLineRe = regexp.MustCompile(`^  - "?([^\s"(]+) \(([^)]+)\)"?:?$`)
+
+// parsePodfileLock reads the PODS section. Subspecs such as Firebase/Core
+// are folded into their pod.
+func parsePodfileLock(data []byte, file string) ([]Component, error) {
+	direct := make(map[string]bool)
+	var pods []Component
+	section := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" && !strings.HasPrefix(line, " ") {
+			section = strings.TrimSuffix(line, ":")
+			continue
+		}
+
+		switch section {
+		case "PODS":
+			if m := podLineRe.FindStringSubmatch(line); m != nil {
+				pods = append(pods, Component{Ecosystem: CocoaPods, Name: podName(m[1]), Version: m[2], File: file})
+			}
+		case "DEPENDENCIES":
+			if strings.HasPrefix(line, "  - ") {
+				name := strings.Trim(strings.TrimPrefix(line, "  - "), `"`)
+				if i := strings.Index(name, " "); i >= 0 {
+					name = name[:i]
+				}
+				direct[podName(name)] = true
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if section == "" {
+		return nil, fmt.Errorf("not a Podfile.lock")
+	}
+
+	for i := range pods {
+		pods[i].Direct = direct[pods[i].Name]
+	}
+	return pods, nil
+}
+
+func podName(name string) string {
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[:i]
+	}
+	return name
+}
+
+type resolvedPin struct {
+	Identity      string `json:"identity"`
+	Package       string `json:"package"`
+	Location      string `json:"location"`
+	RepositoryURL string `json:"repositoryURL"`
+	State         struct {
+		Version  string `json:"version"`
+		Branch   string `json:"branch"`
+		Revision string `json:"revision"`
+	} `json:"state"`
+}
+
+// parsePackageResolved reads SwiftPM's Package.resolved, format version 1
+// (pins under "object") and versions 2 and 3.
+func parsePackageResolved(data []byte, file string) ([]Component, error) {
+	var resolved struct {
+		Pins   []resolvedPin `json:"pins"`
+		Object struct {
+			Pins []resolvedPin `json:"pins"`
+		} `json:"object"`
+	}
+	if err := json.Unmarshal(data, &resolved); err != nil {
+		return nil, err
+	}
+
+	pins := resolved.Pins
+	if len(pins) == 0 {
+		pins = resolved.Object.Pins
+	}
+
+	var components []Component
+	for _, pin := range pins {
+		location := pin.Location
+		if location == "" {
+			location = pin.RepositoryURL
+		}
+		name := pin.Identity
+		if name == "" {
+			name = pin.Package
+		}
+		if name == "" {
+			name = repoName(location)
+		}
+		components = append(components, Component{
+			Ecosystem: SwiftPM,
+			Name:      name,
+			Version:   firstNonEmpty(pin.State.Version, pin.State.Branch, pin.State.Revision),
+			Source:    location,
+			File:      file,
+		})
+	}
+	return components, nil
+}
+
+var cartfileLineRe = regexp.MustCompile(`^(github|git|binary)\s+"([^"]+)"\s+"([^"]+)"`)
+
+func parseCartfileResolved(data []byte, file string) ([]Component, error) {
+	var components []Component
+	for _, line := range strings.Split(string(data), "\n") {
+		m := cartfileLineRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		source := m[2]
+		if m[1] == "github" && !strings.Contains(source, "://") {
+			source = "https://github.com/" + source
+		}
+		name := repoName(m[2])
+		if m[1] == "binary" {
+			name = strings.TrimSuffix(name, ".json")
+		}
+		components = append(components, Component{
+			Ecosystem: Carthage,
+			Name:      name,
+			Version:   m[3],
+			Source:    source,
+			File:      file,
+			Direct:    true,
+		})
+	}
+	return components, nil
+}
+
+// parseGradleLockfile reads Gradle dependency locking files
+// (gradle.lockfile and gradle/dependency-locks/*.lockfile). Dependencies
+// locked only for test or lint configurations are skipped.
+func parseGradleLockfile(data []byte, file string) ([]Component, error) {
+	var components []Component
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "empty=") {
+			continue
+		}
+
+		coords, configs, _ := strings.Cut(line, "=")
+		parts := strings.Split(coords, ":")
+		if len(parts) != 3 {
+			continue
+		}
+		if configs != "" && testOnly(strings.Split(configs, ",")) {
+			continue
+		}
+		components = append(components, Component{Ecosystem: Maven, Group: parts[0], Name: parts[1], Version: parts[2], File: file})
+	}
+	return components, nil
+}
+
+func testOnly(configs []string) bool {
+	for _, c := range configs {
+		lower := strings.ToLower(c)
+		if !strings.Contains(lower, "test") && !strings.Contains(lower, "lint") {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	tomlSectionRe = regexp.MustCompile(`^\[([\w.-]+)\]`)
+	tomlEntryRe   = regexp.MustCompile(`^([\w.-]+)\s*=\s*(.+)$`)
+	tomlFieldRe   = regexp.MustCompile(`([\w.]+)\s*=\s*"([^"]*)"`)
+	tomlCommentRe = regexp.MustCompile(`(?:^|\s)#[^"]*$`)
+)
+
+// parseVersionCatalog reads the [libraries] of a Gradle version catalog.
+// Versions are resolved through [versions]; rich versions use their
+// "strictly" or "require" value.
+func parseVersionCatalog(data []byte, file string) ([]Component, error) {
+	versions := make(map[string]string)
+	var libraries [][2]string
+	section := ""
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(tomlCommentRe.ReplaceAllString(line, ""))
+		if m := tomlSectionRe.FindStringSubmatch(line); m != nil {
+			section = m[1]
+			continue
+		}
+		m := tomlEntryRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		switch section {
+		case "versions":
+			if v := strings.Trim(m[2], `"`); !strings.HasPrefix(v, "{") {
+				versions[m[1]] = v
+				continue
+			}
+			fields := tomlFields(m[2])
+			versions[m[1]] = firstNonEmpty(fields["strictly"], fields["require"], fields["prefer"])
+		case "libraries":
+			libraries = append(libraries, [2]string{m[1], m[2]})
+		}
+	}
+
+	var components []Component
+	for _, lib := range libraries {
+		value := lib[1]
+		var group, name, version string
+
+		if strings.HasPrefix(value, `"`) {
+			parts := strings.Split(strings.Trim(value, `"`), ":")
+			if len(parts) < 2 {
+				continue
+			}
+			group, name = parts[0], parts[1]
+			if len(parts) > 2 {
+				version = parts[2]
+			}
+		} else {
+			fields := tomlFields(value)
+			if module := fields["module"]; module != "" {
+				group, name, _ = strings.Cut(module, ":")
+			} else {
+				group, name = fields["group"], fields["name"]
+			}
+			version = firstNonEmpty(fields["version"], versions[fields["version.ref"]], fields["strictly"], fields["require"])
+		}
+
+		if group == "" || name == "" {
+			continue
+		}
+		components = append(components, Component{Ecosystem: Maven, Group: group, Name: name, Version: version, File: file, Direct: true})
+	}
+	return components, nil
+}
+
+func tomlFields(inline string) map[string]string {
+	fields := make(map[string]string)
+	for _, m := range tomlFieldRe.FindAllStringSubmatch(inline, -1) {
+		fields[m[1]] = m[2]
+	}
+	return fields
+}
+
+// parsePubspecLock reads Flutter and Dart packages, skipping those that
+// come with the SDK.
+func parsePubspecLock(data []byte, file string) ([]Component, error) {
+	var lock struct {
+		Packages map[string]struct {
+			Dependency  string    `yaml:"dependency"`
+			Source      string    `yaml:"source"`
+			Version     string    `yaml:"version"`
+			Description yaml.Node `yaml:"description"`
+		} `yaml:"packages"`
+	}
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	var components []Component
+	for name, pkg := range lock.Packages {
+		if pkg.Source == "sdk" || pkg.Dependency == "direct dev" {
+			continue
+		}
+		c := Component{Ecosystem: Pub, Name: name, Version: pkg.Version, File: file, Direct: strings.HasPrefix(pkg.Dependency, "direct")}
+		if pkg.Source == "git" {
+			var desc struct {
+				URL string `yaml:"url"`
+			}
+			if pkg.Description.Decode(&desc) == nil {
+				c.Source = desc.URL
+			}
+		}
+		components = append(components, c)
+	}
+	return components, nil
+}
+
+// parsePackageLock reads the production dependencies of an npm lockfile,
+// format version 2 or 3 ("packages") or 1 ("dependencies").
+func parsePackageLock(data []byte, file string) ([]Component, error) {
+	type lockPackage struct {
+		Version      string            `json:"version"`
+		Dev          bool              `json:"dev"`
+		DevOptional  bool              `json:"devOptional"`
+		Dependencies map[string]string `json:"dependencies"`
+	}
+	var lock struct {
+		Packages     map[string]lockPackage `json:"packages"`
+		Dependencies map[string]lockPackage `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	var components []Component
+	if len(lock.Packages) > 0 {
+		direct := lock.Packages[""].Dependencies
+		for key, pkg := range lock.Packages {
+			i := strings.LastIndex(key, "node_modules/")
+			if key == "" || pkg.Dev || pkg.DevOptional || i < 0 {
+				continue
+			}
+			name := key[i+len("node_modules/"):]
+			_, isDirect := direct[name]
+			components = append(components, Component{Ecosystem: NPM, Name: name, Version: pkg.Version, File: file, Direct: isDirect && i == 0})
+		}
+		return components, nil
+	}
+
+	for name, pkg := range lock.Dependencies {
+		if pkg.Dev {
+			continue
+		}
+		components = append(components, Component{Ecosystem: NPM, Name: name, Version: pkg.Version, File: file})
+	}
+	return components, nil
+}
+
+func repoName(location string) string {
+	location = strings.TrimSuffix(strings.TrimSuffix(location, "/"), ".git")
+	if i := strings.LastIndexAny(location, "/:"); i >= 0 {
+		return location[i+1:]
+	}
+	return location
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
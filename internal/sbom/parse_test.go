@@ -0,0 +1,247 @@
+package sbom
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []Component
+		wantErr string
+	}{
+		{
+			name: "Podfile.lock",
+			file: "ios/Podfile.lock",
+			content: `PODS:
+  - Firebase/Analytics (10.20.0):
+    - FirebaseAnalytics (~> 10.20.0)
+  - Firebase/Core (10.20.0)
+  - FirebaseAnalytics (10.20.0)
+  - "GoogleUtilities/Environment (7.12.0)"
+
+DEPENDENCIES:
+  - Firebase/Analytics
+  - FirebaseAnalytics (= 10.20.0)
+
+COCOAPODS: 1.15.2
+`,
+			want: []Component{
+				{Ecosystem: CocoaPods, Name: "Firebase", Version: "10.20.0", File: "ios/Podfile.lock", Direct: true},
+				{Ecosystem: CocoaPods, Name: "Firebase", Version: "10.20.0", File: "ios/Podfile.lock", Direct: true},
+				{Ecosystem: CocoaPods, Name: "FirebaseAnalytics", Version: "10.20.0", File: "ios/Podfile.lock", Direct: true},
+				{Ecosystem: CocoaPods, Name: "GoogleUtilities", Version: "7.12.0", File: "ios/Podfile.lock"},
+			},
+		},
+		{
+			name:    "not a Podfile.lock",
+			file:    "Podfile.lock",
+			content: "",
+			wantErr: "not a Podfile.lock",
+		},
+		{
+			name: "Package.resolved v2",
+			file: "App.xcworkspace/xcshareddata/swiftpm/Package.resolved",
+			content: `{
+  "pins" : [
+    {
+      "identity" : "alamofire",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/Alamofire/Alamofire.git",
+      "state" : { "revision" : "abc", "version" : "5.9.1" }
+    },
+    {
+      "identity" : "swift-log",
+      "location" : "https://github.com/apple/swift-log",
+      "state" : { "branch" : "main", "revision" : "def" }
+    }
+  ],
+  "version" : 2
+}`,
+			want: []Component{
+				{Ecosystem: SwiftPM, Name: "alamofire", Version: "5.9.1", Source: "https://github.com/Alamofire/Alamofire.git", File: "App.xcworkspace/xcshareddata/swiftpm/Package.resolved"},
+				{Ecosystem: SwiftPM, Name: "swift-log", Version: "main", Source: "https://github.com/apple/swift-log", File: "App.xcworkspace/xcshareddata/swiftpm/Package.resolved"},
+			},
+		},
+		{
+			name: "Package.resolved v1",
+			file: "Package.resolved",
+			content: `{"object": {"pins": [
+  {"package": "Kingfisher", "repositoryURL": "https://github.com/onevcat/Kingfisher.git", "state": {"revision": "abc", "version": "7.10.0"}},
+  {"repositoryURL": "https://github.com/pointfreeco/swift-snapshot-testing.git", "state": {"revision": "def"}}
+]}, "version": 1}`,
+			want: []Component{
+				{Ecosystem: SwiftPM, Name: "Kingfisher", Version: "7.10.0", Source: "https://github.com/onevcat/Kingfisher.git", File: "Package.resolved"},
+				{Ecosystem: SwiftPM, Name: "swift-snapshot-testing", Version: "def", Source: "https://github.com/pointfreeco/swift-snapshot-testing.git", File: "Package.resolved"},
+			},
+		},
+		{
+			name:    "invalid Package.resolved",
+			file:    "Package.resolved",
+			content: "{",
+			wantErr: "unexpected end of JSON input",
+		},
+		{
+			name: "Cartfile.resolved",
+			file: "Cartfile.resolved",
+			content: `github "ReactiveX/RxSwift" "6.6.0"
+git "https://example.com/kit.git" "1.2.0"
+binary "https://dl.example.com/SDK.json" "3.0.0"
+# comment
+`,
+			want: []Component{
+				{Ecosystem: Carthage, Name: "RxSwift", Version: "6.6.0", Source: "https://github.com/ReactiveX/RxSwift", File: "Cartfile.resolved", Direct: true},
+				{Ecosystem: Carthage, Name: "kit", Version: "1.2.0", Source: "https://example.com/kit.git", File: "Cartfile.resolved", Direct: true},
+				{Ecosystem: Carthage, Name: "SDK", Version: "3.0.0", Source: "https://dl.example.com/SDK.json", File: "Cartfile.resolved", Direct: true},
+			},
+		},
+		{
+			name: "gradle.lockfile",
+			file: "app/gradle.lockfile",
+			content: `# This is a Gradle generated file for dependency locking.
+com.google.firebase:firebase-analytics:21.5.0=releaseRuntimeClasspath,debugRuntimeClasspath
+junit:junit:4.13.2=testRuntimeClasspath
+com.android.tools.lint:lint-api:31.2.0=lintClassPath
+androidx.core:core:1.12.0
+empty=annotationProcessor
+`,
+			want: []Component{
+				{Ecosystem: Maven, Group: "com.google.firebase", Name: "firebase-analytics", Version: "21.5.0", File: "app/gradle.lockfile"},
+				{Ecosystem: Maven, Group: "androidx.core", Name: "core", Version: "1.12.0", File: "app/gradle.lockfile"},
+			},
+		},
+		{
+			name: "libs.versions.toml",
+			file: "gradle/libs.versions.toml",
+			content: `[versions]
+okhttp = "4.12.0"
+retrofit = { strictly = "2.9.0" } # pinned
+
+[libraries]
+okhttp = { module = "com.squareup.okhttp3:okhttp", version.ref = "okhttp" }
+retrofit = { group = "com.squareup.retrofit2", name = "retrofit", version.ref = "retrofit" }
+timber = "com.jakewharton.timber:timber:5.0.1"
+bom = { module = "androidx.compose:compose-bom" }
+broken = "no-colon"
+
+[plugins]
+android = { id = "com.android.application", version = "8.2.0" }
+`,
+			want: []Component{
+				{Ecosystem: Maven, Group: "com.squareup.okhttp3", Name: "okhttp", Version: "4.12.0", File: "gradle/libs.versions.toml", Direct: true},
+				{Ecosystem: Maven, Group: "com.squareup.retrofit2", Name: "retrofit", Version: "2.9.0", File: "gradle/libs.versions.toml", Direct: true},
+				{Ecosystem: Maven, Group: "com.jakewharton.timber", Name: "timber", Version: "5.0.1", File: "gradle/libs.versions.toml", Direct: true},
+				{Ecosystem: Maven, Group: "androidx.compose", Name: "compose-bom", File: "gradle/libs.versions.toml", Direct: true},
+			},
+		},
+		{
+			name: "pubspec.lock",
+			file: "pubspec.lock",
+			content: `packages:
+  firebase_core:
+    dependency: "direct main"
+    description:
+      name: firebase_core
+      url: "https://pub.dev"
+    source: hosted
+    version: "2.24.2"
+  plugin_platform_interface:
+    dependency: transitive
+    description:
+      name: plugin_platform_interface
+      url: "https://pub.dev"
+    source: hosted
+    version: "2.1.8"
+  my_fork:
+    dependency: "direct main"
+    description:
+      path: "."
+      ref: main
+      url: "https://github.com/example/my_fork.git"
+    source: git
+    version: "1.0.0"
+  flutter_lints:
+    dependency: "direct dev"
+    source: hosted
+    version: "3.0.1"
+  flutter:
+    dependency: "direct main"
+    description: flutter
+    source: sdk
+    version: "0.0.0"
+sdks:
+  dart: ">=3.2.0 <4.0.0"
+`,
+			want: []Component{
+				{Ecosystem: Pub, Name: "firebase_core", Version: "2.24.2", File: "pubspec.lock", Direct: true},
+				{Ecosystem: Pub, Name: "my_fork", Version: "1.0.0", Source: "https://github.com/example/my_fork.git", File: "pubspec.lock", Direct: true},
+				{Ecosystem: Pub, Name: "plugin_platform_interface", Version: "2.1.8", File: "pubspec.lock"},
+			},
+		},
+		{
+			name: "package-lock.json v3",
+			file: "package-lock.json",
+			content: `{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"name": "app", "dependencies": {"react-native": "0.73.0", "@sentry/react-native": "5.15.0"}},
+    "node_modules/react-native": {"version": "0.73.0"},
+    "node_modules/@sentry/react-native": {"version": "5.15.0"},
+    "node_modules/react-native/node_modules/semver": {"version": "7.5.4"},
+    "node_modules/jest": {"version": "29.7.0", "dev": true},
+    "node_modules/fsevents": {"version": "2.3.3", "devOptional": true}
+  }
+}`,
+			want: []Component{
+				{Ecosystem: NPM, Name: "@sentry/react-native", Version: "5.15.0", File: "package-lock.json", Direct: true},
+				{Ecosystem: NPM, Name: "react-native", Version: "0.73.0", File: "package-lock.json", Direct: true},
+				{Ecosystem: NPM, Name: "semver", Version: "7.5.4", File: "package-lock.json"},
+			},
+		},
+		{
+			name: "package-lock.json v1",
+			file: "package-lock.json",
+			content: `{
+  "lockfileVersion": 1,
+  "dependencies": {
+    "react": {"version": "18.2.0"},
+    "eslint": {"version": "8.0.0", "dev": true}
+  }
+}`,
+			want: []Component{
+				{Ecosystem: NPM, Name: "react", Version: "18.2.0", File: "package-lock.json"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parse := parserFor(tt.file)
+			if parse == nil {
+				t.Fatalf("parserFor(%q) = nil", tt.file)
+			}
+			got, err := parse([]byte(tt.content), tt.file)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parse error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse error = %v", err)
+			}
+			// Pub and npm lockfiles are maps, so their order is not stable.
+			if strings.HasSuffix(tt.file, ".lock") || strings.HasSuffix(tt.file, ".json") {
+				sort.Slice(got, func(i, j int) bool { return got[i].Name < got[j].Name })
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parse =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Package sbom builds an inventory of the third-party SDKs a mobile project
+// depends on from its dependency manifests and lockfiles.
+package sbom
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/hha-nguyen/canopy-cli/internal/archive"
+)
+
+// Ecosystems of the parsed dependency files.
+const (
+	CocoaPods = "cocoapods"
+	SwiftPM   = "swiftpm"
+	Carthage  = "carthage"
+	Maven     = "maven"
+	Pub       = "pub"
+	NPM       = "npm"
+)
+
+type Component struct {
+	Ecosystem string `json:"ecosystem"`
+	Group     string `json:"group,omitempty"`
+	Name      string `json:"name"`
+	Version   string `json:"version,omitempty"`
+	// Source is the repository URL for git-based dependencies.
+	Source string `json:"source,omitempty"`
+	// File is the manifest or lockfile the component was read from.
+	File   string `json:"file"`
+	Direct bool   `json:"direct,omitempty"`
+}
+
+// ID identifies a component independent of its version.
+func (c Component) ID() string {
+	if c.Group != "" {
+		return c.Ecosystem + ":" + c.Group + ":" + c.Name
+	}
+	return c.Ecosystem + ":" + c.Name
+}
+
+type parser func(data []byte, file string) ([]Component, error)
+
+// parsers by file base name. Gradle lockfiles are matched by extension.
+var parsers = map[string]parser{
+	"Podfile.lock":       parsePodfileLock,
+	"Package.resolved":   parsePackageResolved,
+	"Cartfile.resolved":  parseCartfileResolved,
+	"libs.versions.toml": parseVersionCatalog,
+	"pubspec.lock":       parsePubspecLock,
+	"package-lock.json":  parsePackageLock,
+}
+
+func parserFor(rel string) parser {
+	base := path.Base(rel)
+	if p, ok := parsers[base]; ok {
+		return p
+	}
+	if path.Ext(base) == ".lockfile" {
+		return parseGradleLockfile
+	}
+	return nil
+}
+
+// IsDependencyFile reports whether a file is read by Collect.
+func IsDependencyFile(rel string) bool {
+	return parserFor(rel) != nil
+}
+
+// Inventory is the result of Collect. Problems lists files that could not
+// be parsed; they do not stop the rest of the inventory.
+type Inventory struct {
+	Components []Component
+	Files      []string
+	Problems   []string
+}
+
+// Collect parses the dependency files of the project at root.
+func Collect(root string) (*Inventory, error) {
+	files, err := archive.ListFiles(root, archive.DefaultCompressOptions())
+	if err != nil {
+		return nil, err
+	}
+	return CollectFiles(files, func(rel string) ([]byte, error) {
+		return readFile(root, rel)
+	}), nil
+}
+
+// CollectFiles parses the dependency files among files, reading them with
+// read.
+func CollectFiles(files []string, read func(rel string) ([]byte, error)) *Inventory {
+	inv := &Inventory{}
+	seen := make(map[string]int)
+
+	for _, rel := range files {
+		parse := parserFor(rel)
+		if parse == nil || isVendored(rel) {
+			continue
+		}
+		data, err := read(rel)
+		if err != nil {
+			inv.Problems = append(inv.Problems, fmt.Sprintf("%s: %v", rel, err))
+			continue
+		}
+		components, err := parse(data, rel)
+		if err != nil {
+			inv.Problems = append(inv.Problems, fmt.Sprintf("%s: %v", rel, err))
+			continue
+		}
+		inv.Files = append(inv.Files, rel)
+
+		for _, c := range components {
+			key := c.ID() + "@" + c.Version
+			if i, ok := seen[key]; ok {
+				inv.Components[i].Direct = inv.Components[i].Direct || c.Direct
+				continue
+			}
+			seen[key] = len(inv.Components)
+			inv.Components = append(inv.Components, c)
+		}
+	}
+
+	sort.SliceStable(inv.Components, func(i, j int) bool {
+		a, b := inv.Components[i], inv.Components[j]
+		if a.Ecosystem != b.Ecosystem {
+			return a.Ecosystem < b.Ecosystem
+		}
+		if a.ID() != b.ID() {
+			return strings.ToLower(a.ID()) < strings.ToLower(b.ID())
+		}
+		return a.Version < b.Version
+	})
+	return inv
+}
+
+// vendorDirs hold copies of dependencies whose own lockfiles describe the
+// dependency's build, not the app's.
+var vendorDirs = []string{"Pods", "Carthage", ".build", "SourcePackages", "node_modules", ".dart_tool", ".symlinks"}
+
+func isVendored(rel string) bool {
+	for _, part := range strings.Split(path.Dir(rel), "/") {
+		for _, dir := range vendorDirs {
+			if part == dir {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Locate returns the component a vendored file belongs to, matching the
+// directory layouts of CocoaPods, Carthage and SwiftPM.
+func (inv *Inventory) Locate(rel string) (Component, bool) {
+	parts := strings.Split(rel, "/")
+	for i := 0; i+1 < len(parts); i++ {
+		var ecosystem, name string
+		switch {
+		case parts[i] == "Pods":
+			ecosystem, name = CocoaPods, parts[i+1]
+		case parts[i] == "Carthage" && i+2 < len(parts) && parts[i+1] == "Checkouts":
+			ecosystem, name = Carthage, parts[i+2]
+		case parts[i] == "checkouts" && i > 0 && (parts[i-1] == ".build" || parts[i-1] == "SourcePackages"):
+			ecosystem, name = SwiftPM, parts[i+1]
+		default:
+			continue
+		}
+
+		for _, c := range inv.Components {
+			if c.Ecosystem == ecosystem && strings.EqualFold(c.Name, name) {
+				return c, true
+			}
+		}
+	}
+	return Component{}, false
+}
@@ -0,0 +1,115 @@
+package sbom
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCollectFiles(t *testing.T) {
+	files := map[string]string{
+		"ios/Podfile.lock":                   "PODS:\n  - Firebase/Core (10.20.0)\n  - Firebase/Analytics (10.20.0)\n\nDEPENDENCIES:\n  - Firebase/Core\n",
+		"ios/Pods/Manifest.lock":             "PODS:\n  - Vendored (1.0.0)\n",
+		"node_modules/lib/package-lock.json": `{"packages": {"node_modules/x": {"version": "1.0.0"}}}`,
+		"app/gradle.lockfile":                "androidx.core:core:1.12.0=releaseRuntimeClasspath\n",
+		"Package.resolved":                   "{",
+		"README.md":                          "not a dependency file",
+	}
+	var names []string
+	for rel := range files {
+		names = append(names, rel)
+	}
+	read := func(rel string) ([]byte, error) {
+		content, ok := files[rel]
+		if !ok {
+			return nil, fmt.Errorf("missing")
+		}
+		return []byte(content), nil
+	}
+
+	inv := CollectFiles(append(names, "ios/Missing/Podfile.lock"), read)
+
+	want := []Component{
+		{Ecosystem: CocoaPods, Name: "Firebase", Version: "10.20.0", File: "ios/Podfile.lock", Direct: true},
+		{Ecosystem: Maven, Group: "androidx.core", Name: "core", Version: "1.12.0", File: "app/gradle.lockfile"},
+	}
+	if !reflect.DeepEqual(inv.Components, want) {
+		t.Errorf("Components =\n%+v\nwant\n%+v", inv.Components, want)
+	}
+	if len(inv.Problems) != 2 {
+		t.Errorf("Problems = %v, want the invalid Package.resolved and the unreadable Podfile.lock", inv.Problems)
+	}
+	if len(inv.Files) != 2 {
+		t.Errorf("Files = %v, want 2 parsed files", inv.Files)
+	}
+}
+
+func TestIsDependencyFile(t *testing.T) {
+	tests := []struct {
+		rel  string
+		want bool
+	}{
+		{"ios/Podfile.lock", true},
+		{"Package.resolved", true},
+		{"app/gradle.lockfile", true},
+		{"gradle/dependency-locks/releaseRuntimeClasspath.lockfile", true},
+		{"gradle/libs.versions.toml", true},
+		{"pubspec.lock", true},
+		{"package-lock.json", true},
+		{"package.json", false},
+		{"app/build.gradle", false},
+	}
+	for _, tt := range tests {
+		if got := IsDependencyFile(tt.rel); got != tt.want {
+			t.Errorf("IsDependencyFile(%q) = %v, want %v", tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestLocate(t *testing.T) {
+	inv := &Inventory{Components: []Component{
+		{Ecosystem: CocoaPods, Name: "GoogleUtilities", Version: "7.12.0"},
+		{Ecosystem: Carthage, Name: "RxSwift", Version: "6.6.0"},
+		{Ecosystem: SwiftPM, Name: "alamofire", Version: "5.9.1"},
+	}}
+
+	tests := []struct {
+		rel    string
+		want   string
+		wantOK bool
+	}{
+		{"ios/Pods/GoogleUtilities/PrivacyInfo.xcprivacy", "GoogleUtilities", true},
+		{"Carthage/Checkouts/RxSwift/Sources/PrivacyInfo.xcprivacy", "RxSwift", true},
+		{".build/checkouts/Alamofire/Source/PrivacyInfo.xcprivacy", "alamofire", true},
+		{"DerivedData/App/SourcePackages/checkouts/Alamofire/PrivacyInfo.xcprivacy", "alamofire", true},
+		{"ios/Pods/Unknown/PrivacyInfo.xcprivacy", "", false},
+		{"App/PrivacyInfo.xcprivacy", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := inv.Locate(tt.rel)
+		if ok != tt.wantOK || got.Name != tt.want {
+			t.Errorf("Locate(%q) = %q, %v, want %q, %v", tt.rel, got.Name, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestPURL(t *testing.T) {
+	tests := []struct {
+		c    Component
+		want string
+	}{
+		{Component{Ecosystem: CocoaPods, Name: "Firebase", Version: "10.20.0"}, "pkg:cocoapods/Firebase@10.20.0"},
+		{Component{Ecosystem: Maven, Group: "androidx.core", Name: "core", Version: "1.12.0"}, "pkg:maven/androidx.core/core@1.12.0"},
+		{Component{Ecosystem: Pub, Name: "firebase_core", Version: "2.24.2+1"}, "pkg:pub/firebase_core@2.24.2%2B1"},
+		{Component{Ecosystem: NPM, Name: "@sentry/react-native", Version: "5.15.0"}, "pkg:npm/%40sentry/react-native@5.15.0"},
+		{Component{Ecosystem: SwiftPM, Name: "alamofire", Version: "5.9.1", Source: "https://github.com/Alamofire/Alamofire.git"}, "pkg:swift/github.com/Alamofire/Alamofire@5.9.1"},
+		{Component{Ecosystem: Carthage, Name: "RxSwift", Version: "6.6.0", Source: "https://github.com/ReactiveX/RxSwift"}, "pkg:github/ReactiveX/RxSwift@6.6.0"},
+		{Component{Ecosystem: Carthage, Name: "SDK", Source: "https://dl.example.com/SDK.json"}, ""},
+		{Component{Ecosystem: SwiftPM, Name: "local"}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.c.PURL(); got != tt.want {
+			t.Errorf("PURL(%+v) = %q, want %q", tt.c, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+// Package strset holds helpers for string slices and maps used as sets.
+package strset
+
+import "slices"
+
+// Of returns a set holding items.
+func Of(items ...string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
+
+// Add appends s to list unless it is already present, keeping the order
+// of first appearance.
+func Add(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
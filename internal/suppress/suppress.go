@@ -0,0 +1,158 @@
+package suppress
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/config"
+	"github.com/hha-nguyen/canopy-cli/internal/fingerprint"
+	"github.com/hha-nguyen/canopy-cli/internal/match"
+)
+
+const ExpiredRuleCode = "CANOPY-SUPPRESSION-EXPIRED"
+
+type Report struct {
+	Suppressed int
+	Expired    []config.Suppression
+	Unused     []config.Suppression
+}
+
+// Apply marks findings matched by an active suppression, adds a blocker
+// finding for each expired suppression when expiry is set to fail, and
+// recomputes the summary. What an earlier Apply of the same config added,
+// e.g. in a result converted with report convert, is replaced.
+func Apply(result *api.ScanResult, cfg *config.ProjectConfig, now time.Time) *Report {
+	source := cfg.Source()
+	clearPrevious(result, source)
+
+	report := &Report{}
+	used := make([]bool, len(cfg.Suppressions))
+	expiredMatched := make([]bool, len(cfg.Suppressions))
+
+	for i := range result.Findings {
+		finding := &result.Findings[i]
+
+		for j, s := range cfg.Suppressions {
+			if !Matches(s, *finding) {
+				continue
+			}
+			if s.Expired(now) {
+				expiredMatched[j] = true
+				continue
+			}
+
+			used[j] = true
+			finding.Suppressions = append(finding.Suppressions, api.Suppression{
+				Kind:          kind(s),
+				Justification: s.Reason,
+				Owner:         s.Owner,
+				Expires:       s.Expires,
+				Source:        source,
+			})
+		}
+
+		if finding.Suppressed() {
+			report.Suppressed++
+		}
+	}
+
+	for j, s := range cfg.Suppressions {
+		switch {
+		case s.Expired(now):
+			report.Expired = append(report.Expired, s)
+			if cfg.SuppressionExpiry == config.ExpiryFail && expiredMatched[j] {
+				result.Findings = append(result.Findings, expiredFinding(s, source))
+			}
+		case !used[j]:
+			report.Unused = append(report.Unused, s)
+		}
+	}
+
+	result.RecomputeSummary()
+	return report
+}
+
+func clearPrevious(result *api.ScanResult, source string) {
+	findings := result.Findings[:0]
+	for _, finding := range result.Findings {
+		if finding.RuleCode == ExpiredRuleCode && sameSource(finding.FilePath, source) {
+			continue
+		}
+		kept := finding.Suppressions[:0]
+		for _, s := range finding.Suppressions {
+			if !sameSource(s.Source, source) {
+				kept = append(kept, s)
+			}
+		}
+		if len(kept) == 0 {
+			kept = nil
+		}
+		finding.Suppressions = kept
+		findings = append(findings, finding)
+	}
+	result.Findings = findings
+}
+
+// sameSource reports whether a recorded source refers to the config at the
+// project-relative path source. Results saved by older versions recorded the
+// path as given on the command line, which may be absolute or use
+// backslashes.
+func sameSource(recorded, source string) bool {
+	recorded = fingerprint.NormalizePath(recorded)
+	source = fingerprint.NormalizePath(source)
+	return recorded == source || strings.HasSuffix(recorded, "/"+source)
+}
+
+func Matches(s config.Suppression, finding api.Finding) bool {
+	if !strings.EqualFold(s.Rule, finding.RuleCode) && !match.Glob(strings.ToUpper(s.Rule), strings.ToUpper(finding.RuleCode)) {
+		return false
+	}
+
+	if s.Path != "" && !match.Glob(fingerprint.NormalizePath(s.Path), fingerprint.NormalizePath(finding.FilePath)) {
+		return false
+	}
+
+	for key, pattern := range s.Evidence {
+		value, ok := finding.Evidence[key]
+		if !ok {
+			return false
+		}
+		if !match.Glob(pattern, fmt.Sprint(value)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func kind(s config.Suppression) string {
+	if s.Kind == "" {
+		return config.SuppressionAcceptedRisk
+	}
+	return s.Kind
+}
+
+func expiredFinding(s config.Suppression, source string) api.Finding {
+	msg := fmt.Sprintf("Suppression for %s expired on %s", s.Rule, s.Expires)
+	if s.Path != "" {
+		msg += fmt.Sprintf(" (path %s)", s.Path)
+	}
+	if s.Owner != "" {
+		msg += fmt.Sprintf("; owner: %s", s.Owner)
+	}
+
+	return api.Finding{
+		RuleCode: ExpiredRuleCode,
+		RuleName: "Expired suppression",
+		Severity: "BLOCKER",
+		Message:  msg + ". Renew or remove the suppression.",
+		FilePath: source,
+		Evidence: map[string]interface{}{
+			"rule":    s.Rule,
+			"reason":  s.Reason,
+			"expires": s.Expires,
+		},
+	}
+}
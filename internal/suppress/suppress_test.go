@@ -0,0 +1,168 @@
+package suppress
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hha-nguyen/canopy-cli/internal/api"
+	"github.com/hha-nguyen/canopy-cli/internal/config"
+)
+
+func loadConfig(t *testing.T, content string) *config.ProjectConfig {
+	t.Helper()
+	root := t.TempDir()
+	path := filepath.Join(root, ".canopy.yml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadProject(path)
+	if err != nil {
+		t.Fatalf("LoadProject() error = %v", err)
+	}
+	cfg.SetRoot(root)
+	return cfg
+}
+
+func TestApplyExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		expiry         string
+		expires        string
+		wantSuppressed int
+		wantExpired    int
+		wantFindings   []string
+	}{
+		{
+			name:           "active",
+			expires:        "2026-04-01",
+			wantSuppressed: 1,
+			wantFindings:   []string{"APL-PRIV-001"},
+		},
+		{
+			name:           "expiry date is still valid",
+			expires:        "2026-03-15",
+			wantSuppressed: 1,
+			wantFindings:   []string{"APL-PRIV-001"},
+		},
+		{
+			name:         "expired warns",
+			expires:      "2026-03-14",
+			wantExpired:  1,
+			wantFindings: []string{"APL-PRIV-001"},
+		},
+		{
+			name:         "expired fails",
+			expiry:       config.ExpiryFail,
+			expires:      "2026-03-14",
+			wantExpired:  1,
+			wantFindings: []string{"APL-PRIV-001", ExpiredRuleCode},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := "suppressions:\n  - rule: APL-PRIV-*\n    reason: vendor SDK\n    owner: mobile\n    expires: \"" + tt.expires + "\"\n"
+			if tt.expiry != "" {
+				content += "suppression_expiry: " + tt.expiry + "\n"
+			}
+			cfg := loadConfig(t, content)
+			result := &api.ScanResult{Findings: []api.Finding{
+				{RuleCode: "APL-PRIV-001", Severity: "BLOCKER", FilePath: "PrivacyInfo.xcprivacy"},
+			}}
+
+			report := Apply(result, cfg, now)
+
+			if report.Suppressed != tt.wantSuppressed || len(report.Expired) != tt.wantExpired {
+				t.Errorf("suppressed=%d expired=%d, want %d and %d",
+					report.Suppressed, len(report.Expired), tt.wantSuppressed, tt.wantExpired)
+			}
+			var codes []string
+			for _, f := range result.Findings {
+				codes = append(codes, f.RuleCode)
+			}
+			if !reflect.DeepEqual(codes, tt.wantFindings) {
+				t.Errorf("findings = %v, want %v", codes, tt.wantFindings)
+			}
+
+			blockers := 1 - tt.wantSuppressed
+			if len(tt.wantFindings) == 2 {
+				expired := result.Findings[1]
+				if expired.FilePath != ".canopy.yml" || expired.Severity != "BLOCKER" {
+					t.Errorf("expired finding = %+v", expired)
+				}
+				blockers++
+			}
+			if result.Summary == nil || result.Summary.Blocker != blockers {
+				t.Errorf("summary = %+v, want %d blockers", result.Summary, blockers)
+			}
+		})
+	}
+}
+
+func TestApplyReplacesPreviousRun(t *testing.T) {
+	cfg := loadConfig(t, "suppression_expiry: fail\nsuppressions:\n"+
+		"  - rule: APL-PRIV-001\n    reason: vendor SDK\n    owner: mobile\n    expires: \"2099-01-01\"\n"+
+		"  - rule: GPL-SDK-001\n    reason: migration\n    owner: android\n    expires: \"2020-01-01\"\n")
+	result := &api.ScanResult{Findings: []api.Finding{
+		{RuleCode: "APL-PRIV-001", Severity: "BLOCKER"},
+		{RuleCode: "GPL-SDK-001", Severity: "HIGH"},
+		{RuleCode: "APL-PLIST-001", Severity: "HIGH", Suppressions: []api.Suppression{
+			{Kind: "inline", Justification: "kept", Source: "Info.plist"},
+		}},
+	}}
+
+	Apply(result, cfg, time.Now())
+
+	// A result saved by an older version recorded the absolute config path.
+	for i := range result.Findings[0].Suppressions {
+		result.Findings[0].Suppressions[i].Source = filepath.Join(cfg.Dir(), ".canopy.yml")
+	}
+	Apply(result, cfg, time.Now())
+
+	if len(result.Findings) != 4 {
+		t.Fatalf("got %d findings, want 4 with one expired-suppression finding", len(result.Findings))
+	}
+	if got := result.Findings[0].Suppressions; len(got) != 1 || got[0].Source != ".canopy.yml" || got[0].Kind != config.SuppressionAcceptedRisk {
+		t.Errorf("suppressions = %+v, want one from .canopy.yml", got)
+	}
+	if got := result.Findings[2].Suppressions; len(got) != 1 || got[0].Justification != "kept" {
+		t.Errorf("suppressions = %+v, want the inline one kept", got)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	finding := api.Finding{
+		RuleCode: "APL-PRIVACY-005",
+		FilePath: `Pods\Alamofire\Source`,
+		Evidence: map[string]interface{}{"sdk": "Alamofire", "count": float64(2)},
+	}
+
+	tests := []struct {
+		name string
+		s    config.Suppression
+		want bool
+	}{
+		{name: "rule", s: config.Suppression{Rule: "APL-PRIVACY-005"}, want: true},
+		{name: "rule case", s: config.Suppression{Rule: "apl-privacy-005"}, want: true},
+		{name: "rule glob", s: config.Suppression{Rule: "APL-PRIVACY-*"}, want: true},
+		{name: "other rule", s: config.Suppression{Rule: "APL-PRIVACY-004"}},
+		{name: "path glob", s: config.Suppression{Rule: "*", Path: "Pods/**"}, want: true},
+		{name: "other path", s: config.Suppression{Rule: "*", Path: "App/**"}},
+		{name: "evidence", s: config.Suppression{Rule: "*", Evidence: map[string]string{"sdk": "Alamo*", "count": "2"}}, want: true},
+		{name: "other evidence", s: config.Suppression{Rule: "*", Evidence: map[string]string{"sdk": "Kingfisher"}}},
+		{name: "missing evidence", s: config.Suppression{Rule: "*", Evidence: map[string]string{"plugin": "*"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Matches(tt.s, finding); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package vcs
+
+import (
+	"os"
+	"strings"
+)
+
+// DetectCI reads git metadata from the environment of supported CI
+// providers. It returns nil when no provider is detected.
+func DetectCI() *Info {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return githubActions()
+	case os.Getenv("GITLAB_CI") != "":
+		return gitlab()
+	case os.Getenv("BITBUCKET_BUILD_NUMBER") != "":
+		return bitbucket()
+	case os.Getenv("CIRCLECI") == "true":
+		return circleCI()
+	case os.Getenv("JENKINS_URL") != "":
+		return jenkins()
+	}
+	return nil
+}
+
+func githubActions() *Info {
+	info := &Info{
+		CIProvider: "github-actions",
+		Commit:     os.Getenv("GITHUB_SHA"),
+		Author:     os.Getenv("GITHUB_ACTOR"),
+	}
+
+	if repo := os.Getenv("GITHUB_REPOSITORY"); repo != "" {
+		server := os.Getenv("GITHUB_SERVER_URL")
+		if server == "" {
+			server = "https://github.com"
+		}
+		info.RepositoryURL = strings.TrimSuffix(server, "/") + "/" + repo
+	}
+
+	switch {
+	case os.Getenv("GITHUB_REF_TYPE") == "tag":
+		info.Tag = os.Getenv("GITHUB_REF_NAME")
+	case os.Getenv("GITHUB_HEAD_REF") != "":
+		info.Branch = os.Getenv("GITHUB_HEAD_REF")
+	default:
+		info.Branch = os.Getenv("GITHUB_REF_NAME")
+	}
+
+	return info
+}
+
+func gitlab() *Info {
+	info := &Info{
+		CIProvider:    "gitlab",
+		RepositoryURL: os.Getenv("CI_PROJECT_URL"),
+		Commit:        os.Getenv("CI_COMMIT_SHA"),
+		Tag:           os.Getenv("CI_COMMIT_TAG"),
+		Author:        os.Getenv("CI_COMMIT_AUTHOR"),
+	}
+
+	if branch := os.Getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"); branch != "" {
+		info.Branch = branch
+	} else {
+		info.Branch = os.Getenv("CI_COMMIT_BRANCH")
+	}
+
+	return info
+}
+
+func bitbucket() *Info {
+	return &Info{
+		CIProvider:    "bitbucket",
+		RepositoryURL: os.Getenv("BITBUCKET_GIT_HTTP_ORIGIN"),
+		Commit:        os.Getenv("BITBUCKET_COMMIT"),
+		Branch:        os.Getenv("BITBUCKET_BRANCH"),
+		Tag:           os.Getenv("BITBUCKET_TAG"),
+	}
+}
+
+func circleCI() *Info {
+	return &Info{
+		CIProvider:    "circleci",
+		RepositoryURL: NormalizeRemoteURL(os.Getenv("CIRCLE_REPOSITORY_URL")),
+		Commit:        os.Getenv("CIRCLE_SHA1"),
+		Branch:        os.Getenv("CIRCLE_BRANCH"),
+		Tag:           os.Getenv("CIRCLE_TAG"),
+		Author:        os.Getenv("CIRCLE_USERNAME"),
+	}
+}
+
+func jenkins() *Info {
+	info := &Info{
+		CIProvider:    "jenkins",
+		RepositoryURL: NormalizeRemoteURL(os.Getenv("GIT_URL")),
+		Commit:        os.Getenv("GIT_COMMIT"),
+		Tag:           os.Getenv("TAG_NAME"),
+	}
+
+	branch := os.Getenv("CHANGE_BRANCH")
+	if branch == "" {
+		branch = os.Getenv("BRANCH_NAME")
+	}
+	if branch == "" {
+		branch = strings.TrimPrefix(os.Getenv("GIT_BRANCH"), "origin/")
+	}
+	info.Branch = branch
+
+	if name := os.Getenv("GIT_AUTHOR_NAME"); name != "" {
+		info.Author = name
+		if email := os.Getenv("GIT_AUTHOR_EMAIL"); email != "" {
+			info.Author += " <" + email + ">"
+		}
+	}
+
+	return info
+}
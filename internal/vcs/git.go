@@ -0,0 +1,303 @@
+package vcs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type Info struct {
+	RepositoryURL string `json:"repository_url,omitempty"`
+	Branch        string `json:"branch,omitempty"`
+	Commit        string `json:"commit,omitempty"`
+	Author        string `json:"author,omitempty"`
+	Tag           string `json:"tag,omitempty"`
+	Dirty         bool   `json:"dirty,omitempty"`
+	CIProvider    string `json:"ci_provider,omitempty"`
+}
+
+// Collect combines the local repository state with CI environment
+// variables. CI values win because CI checkouts are usually detached.
+func Collect(dir string) *Info {
+	info, err := Detect(dir)
+	if err != nil {
+		info = &Info{}
+	}
+
+	if ci := DetectCI(); ci != nil {
+		info.Merge(ci)
+	}
+
+	return info
+}
+
+// CollectRef describes rev. Only the repository URL and CI provider are
+// taken from the CI environment, since the other values describe the
+// checked-out commit rather than rev.
+func CollectRef(dir, rev string) (*Info, error) {
+	info, err := DetectRef(dir, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	if ci := DetectCI(); ci != nil {
+		info.Merge(&Info{RepositoryURL: ci.RepositoryURL, CIProvider: ci.CIProvider})
+	}
+
+	return info, nil
+}
+
+// Merge overwrites fields of i with the non-empty fields of other.
+func (i *Info) Merge(other *Info) {
+	if other.RepositoryURL != "" {
+		i.RepositoryURL = other.RepositoryURL
+	}
+	if other.Branch != "" {
+		i.Branch = other.Branch
+	}
+	if other.Commit != "" {
+		i.Commit = other.Commit
+	}
+	if other.Author != "" {
+		i.Author = other.Author
+	}
+	if other.Tag != "" {
+		i.Tag = other.Tag
+	}
+	if other.Dirty {
+		i.Dirty = true
+	}
+	if other.CIProvider != "" {
+		i.CIProvider = other.CIProvider
+	}
+}
+
+func (i *Info) IsEmpty() bool {
+	return i == nil || (i.RepositoryURL == "" && i.Branch == "" && i.Commit == "" && i.Tag == "")
+}
+
+func Detect(dir string) (*Info, error) {
+	info, err := DetectRef(dir, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	if status, err := git(dir, "status", "--porcelain", "--untracked-files=no"); err == nil {
+		info.Dirty = status != ""
+	}
+
+	return info, nil
+}
+
+// DetectRef describes rev instead of the checked-out commit. It never
+// reports the working tree as dirty.
+func DetectRef(dir, rev string) (*Info, error) {
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository: %w", err)
+	}
+
+	commit, err := ResolveCommit(root, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{Commit: commit}
+
+	if branch, err := git(root, "rev-parse", "--abbrev-ref", rev); err == nil && branch != "HEAD" {
+		if _, err := git(root, "show-ref", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+			info.Branch = branch
+		} else if _, err := git(root, "show-ref", "--verify", "--quiet", "refs/remotes/"+branch); err == nil {
+			if _, name, ok := strings.Cut(branch, "/"); ok {
+				info.Branch = name
+			}
+		}
+	}
+
+	if remote, err := git(root, "config", "--get", "remote.origin.url"); err == nil {
+		info.RepositoryURL = NormalizeRemoteURL(remote)
+	}
+
+	if author, err := git(root, "log", "-1", "--format=%an <%ae>", commit); err == nil {
+		info.Author = author
+	}
+
+	if tag, err := git(root, "describe", "--tags", "--exact-match", commit); err == nil {
+		info.Tag = tag
+	}
+
+	return info, nil
+}
+
+func ResolveCommit(dir, rev string) (string, error) {
+	commit, err := git(dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil || commit == "" {
+		return "", fmt.Errorf("unknown revision %q", rev)
+	}
+	return commit, nil
+}
+
+// ArchiveRef streams a tar archive of the directory dir as it exists at rev,
+// with paths relative to dir.
+func ArchiveRef(dir, rev string) (io.ReadCloser, error) {
+	prefix, err := git(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository: %w", err)
+	}
+
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository: %w", err)
+	}
+
+	commit, err := ResolveCommit(dir, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	// git archive only includes the current directory when run from a
+	// subdirectory, so run it from the root with an explicit subtree.
+	treeish := commit
+	if prefix != "" {
+		treeish += ":" + strings.TrimSuffix(prefix, "/")
+	}
+
+	cmd := exec.Command("git", "archive", "--format=tar", treeish)
+	cmd.Dir = root
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("git archive: %w", err)
+	}
+
+	return &archiveReader{ReadCloser: stdout, cmd: cmd, stderr: &stderr}, nil
+}
+
+// ShowFile returns the content of path (relative to dir) at rev, or
+// os.ErrNotExist when it does not exist at that revision.
+func ShowFile(dir, rev, path string) ([]byte, error) {
+	prefix, err := git(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository: %w", err)
+	}
+
+	cmd := exec.Command("git", "show", rev+":"+prefix+path)
+	cmd.Dir = dir
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, os.ErrNotExist
+	}
+	return out, nil
+}
+
+type archiveReader struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+}
+
+func (r *archiveReader) Close() error {
+	r.ReadCloser.Close()
+	if err := r.cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(r.stderr.String()); msg != "" {
+			return fmt.Errorf("git archive: %s", msg)
+		}
+		return fmt.Errorf("git archive: %w", err)
+	}
+	return nil
+}
+
+func NormalizeRemoteURL(remote string) string {
+	remote = strings.TrimSpace(remote)
+	if remote == "" {
+		return ""
+	}
+
+	// scp-like syntax: git@github.com:org/repo.git
+	if !strings.Contains(remote, "://") {
+		if at := strings.Index(remote, "@"); at >= 0 {
+			remote = remote[at+1:]
+		}
+		if host, path, ok := strings.Cut(remote, ":"); ok {
+			remote = "https://" + host + "/" + strings.TrimPrefix(path, "/")
+		}
+	}
+
+	u, err := url.Parse(remote)
+	if err != nil {
+		return remote
+	}
+
+	u.User = nil
+	if u.Scheme == "ssh" || u.Scheme == "git" {
+		u.Scheme = "https"
+		u.Host = u.Hostname()
+	}
+	u.Path = strings.TrimSuffix(u.Path, ".git")
+
+	return u.String()
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// ChangedFiles lists files under dir that differ from the merge base of ref
+// and HEAD, including uncommitted and untracked files. Paths are relative
+// to dir and slash-separated. Deleted files are omitted.
+func ChangedFiles(dir, ref string) ([]string, error) {
+	base, err := git(dir, "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("find merge base with %s: %w", ref, err)
+	}
+
+	diff, err := git(dir, "diff", "--name-only", "--relative", "--diff-filter=ACMRT", base)
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := git(dir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, out := range []string{diff, untracked} {
+		for _, line := range strings.Split(out, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || seen[line] {
+				continue
+			}
+			seen[line] = true
+			files = append(files, line)
+		}
+	}
+
+	return files, nil
+}